@@ -0,0 +1,32 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/christopher-s-jones/ghcontributions/reporting"
+)
+
+// sampleActivity samples each collector's commit times on the repositories,
+// from the providers that can list them, and counts them by hour of the day
+func sampleActivity(ctx context.Context, collectors []*reporting.ProviderCollector, repositories []reporting.Repository, location *time.Location) *reporting.HourlyActivity {
+
+	var times []time.Time
+	for _, collector := range collectors {
+		source, ok := collector.Provider.(reporting.CommitTimeSource)
+		if !ok {
+			continue
+		}
+		for _, repository := range repositories {
+			commitTimes, err := source.CommitTimes(ctx, collector.User, repository, collector.Range)
+			if err != nil {
+				slog.Warn("Couldn't sample the commit times", "user", collector.User, "repository", repository, "error", err)
+				continue
+			}
+			times = append(times, commitTimes...)
+		}
+	}
+	activity := reporting.NewHourlyActivity(times, location)
+	return &activity
+}
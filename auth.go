@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/christopher-s-jones/ghcontributions/reporting"
+)
+
+// authCommand runs the auth subcommand, adding, listing, or removing the
+// credentials kept in the OS keychain
+func authCommand(args []string) {
+
+	flags := flag.NewFlagSet("auth", flag.ExitOnError)
+	service := flags.String("service", reporting.DefaultKeyringService, "The keyring service the credentials are kept under, \nread with -credentials keyring:service.")
+	user := flags.String("user", "", "The username of the credential to add or remove.")
+	provider := flags.String("provider", "", "The provider of the credential, github (the default), gitlab, \nbitbucket, azuredevops, or gerrit.")
+	url := flags.String("url", "", "The base URL of the provider instance, like a GitHub Enterprise Server.")
+	flags.Usage = func() {
+		fmt.Println("Manage the credentials kept in the OS keychain, reading the token to add from standard input")
+		fmt.Println("\nUsage:")
+		fmt.Printf(" %s auth add|list|remove [options]\n\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+
+	// Allow the options before or after the action
+	flags.Parse(args)
+	action := flags.Arg(0)
+	flags.Parse(flags.Args()[min(1, flags.NArg()):])
+
+	keyring := reporting.NewKeyring(*service)
+	credential := reporting.Credential{Username: *user, Provider: *provider, URL: *url}
+	switch action {
+	case "add":
+		fmt.Fprintf(os.Stderr, "Token for %s: ", *user)
+		token, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && token == "" {
+			log.Fatalf("Couldn't read the token: %s", err)
+		}
+		credential.Token = strings.TrimSpace(token)
+		err = keyring.Add(credential)
+		if err != nil {
+			log.Fatalf("Couldn't add the credential: %s", err)
+		}
+		slog.Info("Added the credential to the keyring", "user", *user)
+	case "list":
+		credentials, err := keyring.Credentials()
+		if err != nil {
+			log.Fatalf("Couldn't list the credentials: %s", err)
+		}
+		for _, credential := range credentials {
+			provider := credential.Provider
+			if provider == "" {
+				provider = reporting.ProviderGitHub
+			}
+			fmt.Printf("%-12s %-24s %s\n", provider, credential.Username, credential.URL)
+		}
+	case "remove":
+		err := keyring.Remove(credential)
+		if err != nil {
+			log.Fatalf("Couldn't remove the credential: %s", err)
+		}
+		slog.Info("Removed the credential from the keyring", "user", *user)
+	default:
+		flags.Usage()
+		log.Fatalf("The auth subcommand requires add, list, or remove")
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/christopher-s-jones/ghcontributions/reporting"
+)
+
+// cacheCommand runs the cache subcommand, listing, summarizing, or purging the
+// cached query results of each user-year
+func cacheCommand(args []string) {
+
+	defaultDir, err := reporting.DefaultCacheDir()
+	if err != nil {
+		log.Fatalf("Couldn't find the cache: %s", err)
+	}
+	flags := flag.NewFlagSet("cache", flag.ExitOnError)
+	dir := flags.String("dir", defaultDir, "The directory holding the cached query results.")
+	user := flags.String("user", "", "Only the entries of this username.")
+	year := flags.Int("year", 0, "Only the entries of this year.")
+	olderThan := flags.Duration("older-than", 0, "Only the entries last written longer ago than this, like 720h.")
+	asJSON := flags.Bool("json", false, "Whether to print the entries or statistics as JSON.")
+	flags.Usage = func() {
+		fmt.Println("Inspect and purge the cached query results")
+		fmt.Println("\nUsage:")
+		fmt.Printf(" %s cache ls|purge|stats [options]\n\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+
+	// Allow the options before or after the action
+	flags.Parse(args)
+	action := flags.Arg(0)
+	flags.Parse(flags.Args()[min(1, flags.NArg()):])
+
+	cache, err := reporting.NewCache(*dir)
+	if err != nil {
+		log.Fatalf("Couldn't open the cache: %s", err)
+	}
+	matches := func(entry reporting.CacheEntry) bool {
+		return (*user == "" || entry.User == *user) &&
+			(*year == 0 || entry.Year == *year) &&
+			(*olderThan == 0 || time.Since(entry.Modified) > *olderThan)
+	}
+
+	var output any
+	switch action {
+	case "ls":
+		entries, err := cache.Entries()
+		if err != nil {
+			log.Fatalf("Couldn't list the cache: %s", err)
+		}
+		var matched []reporting.CacheEntry
+		for _, entry := range entries {
+			if matches(entry) {
+				matched = append(matched, entry)
+			}
+		}
+		if !*asJSON {
+			for _, entry := range matched {
+				fmt.Printf("%-12s %-24s %4d %10d %s\n", entry.Provider, entry.User, entry.Year,
+					entry.Size, entry.Modified.Format(time.DateTime))
+			}
+			return
+		}
+		output = matched
+	case "purge":
+		if *user == "" && *year == 0 && *olderThan == 0 {
+			slog.Info("Purging every entry", "dir", cache.Dir)
+		}
+		purged, err := cache.Purge(matches)
+		if err != nil {
+			log.Fatalf("Couldn't purge the cache: %s", err)
+		}
+		slog.Info("Purged the cached user-years", "count", len(purged))
+		return
+	case "stats":
+		stats, err := cache.Stats()
+		if err != nil {
+			log.Fatalf("Couldn't summarize the cache: %s", err)
+		}
+		if !*asJSON {
+			fmt.Printf("Directory: %s\nEntries: %d\nUsers: %d\nSize: %d bytes\n",
+				stats.Dir, stats.Entries, stats.Users, stats.Size)
+			if stats.Oldest != nil {
+				fmt.Printf("Oldest: %s\nNewest: %s\n",
+					stats.Oldest.Format(time.DateTime), stats.Newest.Format(time.DateTime))
+			}
+			return
+		}
+		output = stats
+	default:
+		flags.Usage()
+		log.Fatalf("The cache subcommand requires ls, purge, or stats")
+	}
+
+	outputJSON, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		log.Fatalf("Couldn't report the cache: %s", err)
+	}
+	fmt.Println(string(outputJSON))
+}
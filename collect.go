@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/christopher-s-jones/ghcontributions/reporting"
+)
+
+// The default number of users collected at once
+const defaultConcurrency = 4
+
+// A userCollection is what was collected for one credential
+type userCollection struct {
+	queryResults map[string]reporting.QueryResult
+	// The query results of each quarter or month, for a finer granularity than years
+	periodResults map[string]map[string]reporting.QueryResult
+	// The final years reused from the previous report, and the range collected
+	reused       map[string]reporting.QueryResult
+	dateRange    reporting.DateRange
+	err          error
+	duration     time.Duration
+	profile      *reporting.Profile
+	pullRequests []reporting.PullRequest
+}
+
+// collectUsers collects each credential's contributions with its collector, with
+// its profile and any pull requests, running up to the concurrency at once. Each
+// collector has its own provider and token, so a collector pausing for its rate
+// limit doesn't hold up the others. The past years are read from the cache when
+// it's set, and the final years are reused from the previous report when it's
+// set. Each collection finishes its progress task, if any. The collections are
+// returned in the collectors' order.
+func collectUsers(ctx context.Context, credentials reporting.Credentials, collectors []*reporting.ProviderCollector,
+	tasks []*reporting.ProgressTask, cache *reporting.Cache, previous *reporting.Report, concurrency int, pullRequests bool) []userCollection {
+
+	collections := make([]userCollection, len(collectors))
+	sources := credentials.Sources()
+	workers := make(chan struct{}, max(concurrency, 1))
+	var wait sync.WaitGroup
+	for i, collector := range collectors {
+		wait.Add(1)
+		workers <- struct{}{}
+		go func() {
+			defer func() { <-workers }()
+			defer wait.Done()
+			collections[i] = collectUser(ctx, credentials[i].Source(), sources[collector.User], collector, cache,
+				previous, pullRequests)
+			tasks[i].Finish()
+		}()
+	}
+	wait.Wait()
+	return collections
+}
+
+// collectUser collects a collector's contributions, profile, and pull requests. The
+// source names the cache entries, and the user's sources match the previous report's.
+func collectUser(ctx context.Context, source string, userSources []string, collector *reporting.ProviderCollector,
+	cache *reporting.Cache, previous *reporting.Report, pullRequests bool) userCollection {
+
+	var collection userCollection
+	start := time.Now()
+
+	// Collect only the years after those that were final in the previous report
+	remaining := *collector
+	if previous != nil {
+		collection.reused, remaining.Range = previous.Reuse(collector.User, userSources, collector.Range)
+	}
+	collection.dateRange = remaining.Range
+	if !remaining.Range.From.Before(remaining.Range.To) {
+		collection.queryResults = make(map[string]reporting.QueryResult)
+	} else if collector.Granularity != "" && collector.Granularity != reporting.GranularityYear {
+		collection.queryResults, collection.periodResults, collection.err = remaining.CollectPeriods(ctx)
+	} else if cache != nil {
+		collection.queryResults, collection.err = cache.Collect(ctx, source, &remaining, start)
+	} else {
+		collection.queryResults, collection.err = remaining.Collect(ctx)
+	}
+	collection.duration = time.Since(start)
+	if collection.err != nil {
+		slog.Error("Couldn't collect", "user", collector.User, "error", collection.err)
+	}
+
+	// Describe the user with the provider's profile
+	if source, ok := collector.Provider.(reporting.ProfileSource); ok {
+		profile, err := source.Profile(ctx, collector.User)
+		if err != nil {
+			slog.Warn("Couldn't look up the profile", "user", collector.User, "error", err)
+		} else {
+			collection.profile = &profile
+		}
+	}
+
+	// List pull requests from the providers that can to find issue keys
+	if source, ok := collector.Provider.(reporting.PullRequestSource); ok && pullRequests {
+		userPullRequests, err := source.PullRequests(ctx, collector.User, collector.Range)
+		collection.pullRequests = userPullRequests
+		if err != nil {
+			slog.Warn("Couldn't list the pull requests", "user", collector.User, "error", err)
+		}
+	}
+	return collection
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/shurcooL/githubv4"
+	"golang.org/x/oauth2"
+)
+
+// newProvider builds the provider the credential names, sending its API
+// requests with the HTTP client
+func newProvider(credential reporting.Credential, httpClient *http.Client) (reporting.Provider, error) {
+
+	switch credential.Provider {
+	case "", reporting.ProviderGitHub:
+		src := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: credential.Token})
+		// Authenticate as a GitHub App installation, refreshing its tokens
+		if credential.AppID != 0 {
+			app, err := newGitHubApp(credential, httpClient)
+			if err != nil {
+				return nil, err
+			}
+			src = app.TokenSource()
+		}
+		ctx := context.WithValue(context.Background(), oauth2.HTTPClient, httpClient)
+		oauthClient := oauth2.NewClient(ctx, src)
+		gitHub, err := reporting.NewGitHub(githubv4.NewClient(oauthClient))
+		if err != nil {
+			return nil, err
+		}
+		gitHub.HTTPClient = oauthClient
+		// Talk to a GitHub Enterprise Server instead of github.com
+		if credential.URL != "" {
+			graphQLURL, restURL := reporting.GitHubEnterpriseURLs(credential.URL)
+			gitHub.Client = githubv4.NewEnterpriseClient(graphQLURL, oauthClient)
+			gitHub.RESTURL = restURL
+		}
+		return gitHub, nil
+	case reporting.ProviderGitLab:
+		gitLab, err := reporting.NewGitLab(credential.URL, credential.Token)
+		if err != nil {
+			return nil, err
+		}
+		gitLab.Client = httpClient
+		return gitLab, nil
+	case reporting.ProviderBitbucket:
+		bitbucket, err := reporting.NewBitbucket(credential.URL, credential.Username, credential.Token)
+		if err != nil {
+			return nil, err
+		}
+		bitbucket.Client = httpClient
+		return bitbucket, nil
+	case reporting.ProviderAzureDevOps:
+		azureDevOps, err := reporting.NewAzureDevOps(credential.URL, credential.Token)
+		if err != nil {
+			return nil, err
+		}
+		azureDevOps.Client = httpClient
+		return azureDevOps, nil
+	case reporting.ProviderGerrit:
+		gerrit, err := reporting.NewGerrit(credential.URL, credential.Username, credential.Token)
+		if err != nil {
+			return nil, err
+		}
+		gerrit.Client = httpClient
+		return gerrit, nil
+	case reporting.ProviderLocalGit:
+		return reporting.NewLocalGit(credential.Emails, credential.Directories)
+	case reporting.ProviderDemo:
+		return &reporting.Demo{}, nil
+	default:
+		return nil, fmt.Errorf("unknown provider %q", credential.Provider)
+	}
+}
+
+// newGitHubApp builds the GitHub App the credential names, reading its private key
+// from the file unless the credential holds the PEM key itself
+func newGitHubApp(credential reporting.Credential, httpClient *http.Client) (*reporting.GitHubApp, error) {
+
+	privateKey := []byte(credential.PrivateKey)
+	if !strings.HasPrefix(strings.TrimSpace(credential.PrivateKey), "-----BEGIN") {
+		var err error
+		privateKey, err = os.ReadFile(credential.PrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the github app private key: %w", err)
+		}
+	}
+	app, err := reporting.NewGitHubApp(credential.AppID, credential.InstallationID, privateKey)
+	if err != nil {
+		return nil, err
+	}
+	app.Client = httpClient
+	if credential.URL != "" {
+		_, app.RESTURL = reporting.GitHubEnterpriseURLs(credential.URL)
+	}
+	return app, nil
+}
+
+// newHTTPClient builds the HTTP client for provider API requests, setting the
+// User-Agent and the comma separated Name=Value headers when given, with the
+// transport that counts the requests for the run statistics
+func newHTTPClient(userAgent string, headers string) (*http.Client, *reporting.StatsTransport, error) {
+
+	stats := &reporting.StatsTransport{}
+	if userAgent == "" && headers == "" {
+		return &http.Client{Transport: stats}, stats, nil
+	}
+	headerValues, err := parseKeyValues(headers)
+	if err != nil {
+		return nil, nil, fmt.Errorf("couldn't parse the headers: %w", err)
+	}
+	stats.Base, err = reporting.NewHeaderTransport(nil, userAgent, headerValues)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &http.Client{Transport: stats}, stats, nil
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// A subcommand is a mode of the command, named by the first argument
+type subcommand struct {
+	name string
+	// The arguments shown in the usage, after the name
+	usage string
+	run   func(ctx context.Context, args []string)
+}
+
+// subcommands lists the subcommands, in the order they're listed in the usage.
+// Arguments that start with an option run collect, as the command did before it
+// had subcommands.
+func subcommands() []subcommand {
+	return []subcommand{
+		{"collect", "[options]", func(ctx context.Context, args []string) { collectCommand(ctx, args, false) }},
+		{"report", "report.json [options]", func(ctx context.Context, args []string) { reportCommand(args) }},
+		{"serve", "-listen :8080 [options]", func(ctx context.Context, args []string) { collectCommand(ctx, args, true) }},
+		{"auth", "add|list|remove [options]", func(ctx context.Context, args []string) { authCommand(args) }},
+		{"cache", "ls|purge|stats [options]", func(ctx context.Context, args []string) { cacheCommand(args) }},
+		{"compare-periods", "-a 2022 -b 2023 [options]", comparePeriods},
+		{"history", "-snapshots history.db [options]", func(ctx context.Context, args []string) { history(args) }},
+		{"resume", "-periods jobs.json [options]", resume},
+		{"retry-failed", "report.json [options]", retryFailed},
+		{"validate", "[options]", validate},
+		{"version", "", func(ctx context.Context, args []string) { versionCommand() }},
+	}
+}
+
+// runSubcommand runs the subcommand the arguments name, or collect when they
+// start with an option or are empty, returning false for an unknown name
+func runSubcommand(ctx context.Context, args []string) bool {
+
+	name := "collect"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+	for _, command := range subcommands() {
+		if command.name == name {
+			command.run(ctx, args)
+			return true
+		}
+	}
+	return false
+}
+
+// printSubcommands prints the usage line of each subcommand
+func printSubcommands() {
+	for _, command := range subcommands() {
+		if command.usage == "" {
+			fmt.Printf(" %s %s\n", os.Args[0], command.name)
+			continue
+		}
+		fmt.Printf(" %s %s %s\n", os.Args[0], command.name, command.usage)
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"log/slog"
+	"net/http"
+	"os"
+
+	"github.com/christopher-s-jones/ghcontributions/reporting"
+)
+
+// comparePeriods runs the compare-periods subcommand, collecting the
+// contributions of two periods and printing the change from A to B
+func comparePeriods(ctx context.Context, args []string) {
+
+	flags := flag.NewFlagSet("compare-periods", flag.ExitOnError)
+	periodA := flags.String("a", "", "The first period, as a year, a month like 2023-06, \nor dates like 2023-01-01..2023-06-30.")
+	periodB := flags.String("b", "", "The second period, in the same forms as -a.")
+	credentialsFilePath := flags.String("credentials", "gh-tokens.json", "The name of the file containing Github usernames \nand API token values")
+	encrypted := flags.Bool("encrypted", false, "Whether the credentials file is PGP encrypted.")
+	passphraseFile := flags.String("passphrase-file", "", "The path of a file holding the passphrase of the encrypted \ncredentials file, instead of prompting for it.")
+	useGHAuth := flags.Bool("use-gh-auth", false, "Whether to read the tokens of the GitHub CLI's accounts, \nlike -credentials gh:, instead of the credentials file.")
+	user := flags.String("user", "", "A GitHub username to report on with the GITHUB_TOKEN \nvariable, instead of the credentials file.")
+	users := flags.String("users", "", "Comma separated GitHub usernames to report on with one \nshared token, GITHUB_TOKEN or the credentials file's first.")
+	usersFilePath := flags.String("users-file", "", "The path of a file listing a GitHub username on each line, \nreported on like -users.")
+	apiURL := flags.String("api-url", "", "The base URL of a GitHub Enterprise Server, like https://ghe.example.com, \nfor the GitHub credentials without a url of their own.")
+	userAgent := flags.String("user-agent", "", "The User-Agent header sent with provider API requests.")
+	headers := flags.String("headers", "", "Comma separated Name=Value headers added to \nprovider API requests.")
+	includeRestricted := flags.Bool("include-restricted", false, "Whether to count the anonymized contributions to private \nGitHub repositories among the other contributions.")
+	asJSON := flags.Bool("json", false, "Whether to print the comparison as JSON.")
+	flags.Usage = func() {
+		fmt.Println("Compare contributions between two periods")
+		fmt.Println("\nUsage:")
+		fmt.Printf(" %s compare-periods -a 2022 -b 2023 [options]\n\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if *useGHAuth {
+		*credentialsFilePath = ghAuthCredentials
+	}
+
+	rangeA, err := reporting.ParsePeriod(*periodA)
+	if err != nil {
+		flags.Usage()
+		log.Fatalf("Couldn't parse the -a period: %s", err)
+	}
+	rangeB, err := reporting.ParsePeriod(*periodB)
+	if err != nil {
+		flags.Usage()
+		log.Fatalf("Couldn't parse the -b period: %s", err)
+	}
+
+	usernames, err := listUsers(*user, *users, *usersFilePath)
+	if err != nil {
+		flags.Usage()
+		log.Fatalf("Couldn't list the users: %s", err)
+	}
+	credentials, err := loadCredentials(*credentialsFilePath, *encrypted, *passphraseFile, usernames, *apiURL)
+	if err != nil {
+		flags.Usage()
+		log.Fatalf("Couldn't load the credentials: %s", err)
+	}
+	httpClient, _, err := newHTTPClient(*userAgent, *headers)
+	if err != nil {
+		flags.Usage()
+		log.Fatalf("Couldn't configure the API requests: %s", err)
+	}
+
+	reporter := reporting.Reporter{IncludeRestricted: *includeRestricted}
+	comparison := reporter.ComparePeriods(
+		rangeA, collectPeriod(ctx, *credentials, httpClient, rangeA),
+		rangeB, collectPeriod(ctx, *credentials, httpClient, rangeB))
+	printComparison(comparison, *asJSON)
+}
+
+// collectPeriod collects every credential's contributions over the period
+func collectPeriod(ctx context.Context, credentials reporting.Credentials, httpClient *http.Client, period reporting.DateRange) map[string]reporting.QueryResult {
+
+	var queryResults = make(map[string]reporting.QueryResult)
+	for _, credential := range credentials {
+		provider, err := newProvider(credential, httpClient)
+		if err != nil {
+			log.Fatalf("Couldn't create a provider for %s: %s", credential.Username, err)
+		}
+		collector := &reporting.ProviderCollector{Provider: provider, User: credential.Username, Range: period}
+		userQueryResults, err := collector.Collect(ctx)
+		reporting.MergeQueryResults(queryResults, userQueryResults)
+		if err != nil {
+			slog.Error("Couldn't collect", "user", credential.Username, "error", err)
+		}
+	}
+	return queryResults
+}
+
+// printComparison prints the comparison as a table or as JSON
+func printComparison(comparison reporting.PeriodComparison, asJSON bool) {
+	if !asJSON {
+		fmt.Print(comparison.Text())
+		return
+	}
+	comparisonJSON, err := json.MarshalIndent(comparison, "", "  ")
+	if err != nil {
+		log.Fatalf("Couldn't report the comparison: %s", err)
+	}
+	fmt.Println(string(comparisonJSON))
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/christopher-s-jones/ghcontributions/reporting"
+)
+
+// The prefix of the environment variables setting options, like GHCONTRIB_FIRSTYEAR
+const envPrefix = "GHCONTRIB_"
+
+// optionEnv names the environment variable of an option, like GHCONTRIB_POST_REPORT
+// for -post-report
+func optionEnv(name string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// An optionSource is where an option was set, ordered by precedence
+type optionSource int
+
+// The sources of options, from the lowest precedence to the highest
+const (
+	sourceDefault optionSource = iota
+	sourceConfigFile
+	sourceEnv
+	sourceCommandLine
+)
+
+// appendList returns a function appending the comma separated values of an option
+// to the list, for options that can be repeated
+func appendList(list *[]string) func(string) error {
+	return func(value string) error {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				*list = append(*list, item)
+			}
+		}
+		return nil
+	}
+}
+
+// applyDefaults sets each option that wasn't set on the command line from its
+// environment variable, or else from the config file. Without a path, the config
+// file is the GHCONTRIB_CONFIG variable, or the default config file when there's one.
+// The source of each option set is returned, as the flags can't tell them apart.
+func applyDefaults(flags *flag.FlagSet, path string) (map[string]optionSource, error) {
+
+	if path == "" {
+		path = os.Getenv(optionEnv("config"))
+	}
+	values, err := readConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	for name := range values {
+		if flags.Lookup(name) == nil || name == "config" {
+			return nil, fmt.Errorf("the config file has an unknown option %s", name)
+		}
+	}
+
+	var sources = make(map[string]optionSource)
+	flags.Visit(func(f *flag.Flag) { sources[f.Name] = sourceCommandLine })
+	flags.VisitAll(func(f *flag.Flag) {
+		if err != nil || sources[f.Name] == sourceCommandLine || f.Name == "config" {
+			return
+		}
+		if value, found := os.LookupEnv(optionEnv(f.Name)); found {
+			sources[f.Name] = sourceEnv
+			err = flags.Set(f.Name, value)
+			if err != nil {
+				err = fmt.Errorf("the %s variable is invalid: %w", optionEnv(f.Name), err)
+			}
+		} else if value, found := values[f.Name]; found {
+			sources[f.Name] = sourceConfigFile
+			err = flags.Set(f.Name, value)
+			if err != nil {
+				err = fmt.Errorf("the config file has an invalid %s: %w", f.Name, err)
+			}
+		}
+	})
+	return sources, err
+}
+
+// applyFiscalYearDefault makes the current fiscal year the last year, in place of
+// the calendar year, when the years are fiscal years. A lastyear set anywhere, on
+// the command line, in the environment, or in the config file, is kept as asked for.
+func applyFiscalYearDefault(config *Configuration, now time.Time) {
+	fiscalYearStart := time.Month(config.fiscalYearStart)
+	if fiscalYearStart > time.January && config.sources["lastyear"] == sourceDefault {
+		config.lastReportingYear = reporting.FiscalYear(now, fiscalYearStart)
+	}
+}
+
+// resolveDateRange settles the -from and -to bounds against -firstyear and
+// -lastyear by the precedence of their sources, so a command line -from replaces
+// a config file's firstyear, and a command line -firstyear replaces an environment
+// variable's from. Both set by the same source is an error.
+func resolveDateRange(config *Configuration) error {
+	for _, bound := range []struct {
+		year  string
+		name  string
+		value *string
+	}{{"firstyear", "from", &config.from}, {"lastyear", "to", &config.to}} {
+		yearSource, boundSource := config.sources[bound.year], config.sources[bound.name]
+		if yearSource == sourceDefault || boundSource == sourceDefault {
+			continue
+		}
+		if yearSource == boundSource {
+			return fmt.Errorf("the -from and -to flags replace -firstyear and -lastyear")
+		}
+		if yearSource > boundSource {
+			*bound.value = ""
+		}
+	}
+	return nil
+}
+
+// readConfig reads the option values of the config file at the path, or of the
+// default config file when there's one
+func readConfig(path string) (map[string]string, error) {
+
+	configPath := path
+	if configPath == "" {
+		var err error
+		configPath, err = reporting.DefaultConfigPath()
+		if err != nil {
+			return nil, nil
+		}
+	}
+	configYAML, err := os.ReadFile(configPath)
+	if errors.Is(err, os.ErrNotExist) && path == "" {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the config file: %w", err)
+	}
+	return reporting.ParseConfig(configYAML)
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestFlags returns a flag set with the options of the tests, parsed from the arguments
+func newTestFlags(t *testing.T, config *Configuration, args ...string) *flag.FlagSet {
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.String("config", "", "")
+	flags.IntVar(&config.firstReportingYear, "firstyear", 0, "")
+	flags.IntVar(&config.lastReportingYear, "lastyear", 2024, "")
+	flags.StringVar(&config.from, "from", "", "")
+	flags.StringVar(&config.to, "to", "", "")
+	flags.StringVar(&config.output, "output", "json", "")
+	require.NoError(t, flags.Parse(args))
+	return flags
+}
+
+// writeTestConfig writes a config file, returning its path
+func writeTestConfig(t *testing.T, configYAML string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(configYAML), 0600))
+	return path
+}
+
+// Test the precedence of the command line over the environment over the config file
+func TestApplyDefaults(t *testing.T) {
+	path := writeTestConfig(t, "firstyear: 2018\nlastyear: 2020\noutput: csv\n")
+	t.Setenv(optionEnv("lastyear"), "2021")
+	t.Setenv(optionEnv("output"), "yaml")
+
+	var config Configuration
+	flags := newTestFlags(t, &config, "-output", "markdown")
+	sources, err := applyDefaults(flags, path)
+	require.NoError(t, err)
+	assert.Equal(t, "markdown", config.output)
+	assert.Equal(t, 2021, config.lastReportingYear)
+	assert.Equal(t, 2018, config.firstReportingYear)
+	assert.Equal(t, map[string]optionSource{
+		"output":    sourceCommandLine,
+		"lastyear":  sourceEnv,
+		"firstyear": sourceConfigFile,
+	}, sources)
+
+	// Ensure options that aren't set anywhere keep their defaults and no source
+	assert.Equal(t, sourceDefault, sources["from"])
+
+	// Ensure an unknown option in the config file is an error
+	_, err = applyDefaults(newTestFlags(t, &config), writeTestConfig(t, "nope: 1\n"))
+	assert.ErrorContains(t, err, "unknown option nope")
+}
+
+// Test settling -from and -to against -firstyear and -lastyear by their sources
+func TestResolveDateRange(t *testing.T) {
+	// Ensure a command line -from replaces a config file's firstyear
+	var config Configuration
+	flags := newTestFlags(t, &config, "-from", "2019-06")
+	config.sources, _ = applyDefaults(flags, writeTestConfig(t, "firstyear: 2018\n"))
+	require.NoError(t, resolveDateRange(&config))
+	assert.Equal(t, "2019-06", config.from)
+
+	// Ensure a command line -lastyear replaces an environment variable's to
+	t.Setenv(optionEnv("to"), "2022-03")
+	config = Configuration{}
+	flags = newTestFlags(t, &config, "-lastyear", "2023")
+	config.sources, _ = applyDefaults(flags, writeTestConfig(t, ""))
+	require.NoError(t, resolveDateRange(&config))
+	assert.Empty(t, config.to)
+	assert.Equal(t, 2023, config.lastReportingYear)
+
+	// Ensure both set by the same source conflict
+	config = Configuration{}
+	flags = newTestFlags(t, &config, "-firstyear", "2018", "-from", "2019-06")
+	config.sources, _ = applyDefaults(flags, writeTestConfig(t, ""))
+	assert.Error(t, resolveDateRange(&config))
+
+	config = Configuration{}
+	flags = newTestFlags(t, &config)
+	config.sources, _ = applyDefaults(flags, writeTestConfig(t, "firstyear: 2018\nfrom: 2019-06\n"))
+	assert.Error(t, resolveDateRange(&config))
+}
+
+// Test defaulting the last year to the current fiscal year
+func TestApplyFiscalYearDefault(t *testing.T) {
+	now := time.Date(2024, time.September, 1, 0, 0, 0, 0, time.UTC)
+
+	config := Configuration{fiscalYearStart: 7, lastReportingYear: 2024}
+	applyFiscalYearDefault(&config, now)
+	assert.Equal(t, 2025, config.lastReportingYear)
+
+	// Ensure a lastyear from the config file or the environment is kept like one on the command line
+	for _, source := range []optionSource{sourceConfigFile, sourceEnv, sourceCommandLine} {
+		config = Configuration{fiscalYearStart: 7, lastReportingYear: 2023,
+			sources: map[string]optionSource{"lastyear": source}}
+		applyFiscalYearDefault(&config, now)
+		assert.Equal(t, 2023, config.lastReportingYear)
+	}
+
+	// Ensure calendar years keep the last year
+	config = Configuration{fiscalYearStart: 1, lastReportingYear: 2024}
+	applyFiscalYearDefault(&config, now)
+	assert.Equal(t, 2024, config.lastReportingYear)
+}
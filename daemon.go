@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/christopher-s-jones/ghcontributions/reporting"
+)
+
+// daemon collects at once and then again at each interval until the context is
+// done, collecting out of schedule on a refresh or a refresh signal, like SIGUSR1
+func daemon(ctx context.Context, interval time.Duration, refresh chan struct{},
+	collect func(ctx context.Context) error) error {
+
+	// Without any refresh signals, Notify would relay every signal. Stop doesn't
+	// close the channel, so the relay stops once polling does.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if len(refreshSignals) > 0 {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, refreshSignals...)
+		defer signal.Stop(signals)
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case received := <-signals:
+					slog.Info("Collecting now", "signal", received.String())
+					select {
+					case refresh <- struct{}{}:
+					default:
+					}
+				}
+			}
+		}()
+	}
+
+	slog.Info("Collecting until interrupted", "interval", interval)
+	return reporting.Poll(ctx, interval, refresh, collect)
+}
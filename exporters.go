@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/christopher-s-jones/ghcontributions/reporting"
+)
+
+// configureExporters creates the exporters enabled in the configuration.
+// The previousResults are from the last snapshot, used by notifiers to report deltas.
+func configureExporters(config Configuration, previousResults *reporting.AggregatedResults) (
+	exporters []reporting.Exporter, err error) {
+
+	if config.pushgatewayURL != "" {
+		pushgateway, err := reporting.NewPushgateway(config.pushgatewayURL)
+		if err != nil {
+			return nil, err
+		}
+		exporters = append(exporters, &pushgateway)
+	}
+
+	if config.textfilePath != "" {
+		textfile, err := reporting.NewTextfile(config.textfilePath)
+		if err != nil {
+			return nil, err
+		}
+		exporters = append(exporters, textfile)
+	}
+
+	if config.statsdAddress != "" {
+		statsd, err := reporting.NewStatsD(config.statsdAddress, config.statsdTagged)
+		if err != nil {
+			return nil, err
+		}
+		exporters = append(exporters, statsd)
+	}
+
+	if config.cloudWatchNamespace != "" {
+		awsCredentials, err := reporting.LoadAWSCredentials()
+		if err != nil {
+			return nil, err
+		}
+		dimensions, err := parseKeyValues(config.cloudWatchDimensions)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse the cloudwatch dimensions: %w", err)
+		}
+		cloudWatch, err := reporting.NewCloudWatch(config.cloudWatchNamespace,
+			config.awsRegion, dimensions, awsCredentials)
+		if err != nil {
+			return nil, err
+		}
+		exporters = append(exporters, cloudWatch)
+	}
+
+	if config.s3Bucket != "" {
+		awsCredentials, err := reporting.LoadAWSCredentials()
+		if err != nil {
+			return nil, err
+		}
+		s3, err := reporting.NewS3(config.s3Bucket, config.s3Prefix, config.awsRegion, awsCredentials)
+		if err != nil {
+			return nil, err
+		}
+		s3.ServerSideEncryption = config.s3ServerSideEncryption
+		s3.KMSKeyID = config.s3KMSKeyID
+		s3.Format = config.output
+		exporters = append(exporters, s3)
+	}
+
+	if config.gcsBucket != "" {
+		client, err := reporting.NewGoogleClient(context.Background(), reporting.GCSScope)
+		if err != nil {
+			return nil, err
+		}
+		gcs, err := reporting.NewGCS(config.gcsBucket, config.gcsObjectTemplate, client)
+		if err != nil {
+			return nil, err
+		}
+		gcs.Format = config.output
+		exporters = append(exporters, gcs)
+	}
+
+	if config.azureContainer != "" {
+		var azureBlob *reporting.AzureBlob
+		if connectionString := os.Getenv("AZURE_STORAGE_CONNECTION_STRING"); connectionString != "" {
+			azureBlob, err = reporting.NewAzureBlob(connectionString, config.azureContainer, config.azurePrefix)
+		} else {
+			azureBlob, err = reporting.NewAzureBlobWithManagedIdentity(config.azureAccount,
+				config.azureContainer, config.azurePrefix, config.azureClientID)
+		}
+		if err != nil {
+			return nil, err
+		}
+		azureBlob.Format = config.output
+		exporters = append(exporters, azureBlob)
+	}
+
+	if config.sheetsSpreadsheetID != "" {
+		client, err := reporting.NewGoogleClient(context.Background(), reporting.SheetsScope)
+		if err != nil {
+			return nil, err
+		}
+		sheets, err := reporting.NewSheets(config.sheetsSpreadsheetID, config.sheetsRange,
+			config.sheetsRows, client)
+		if err != nil {
+			return nil, err
+		}
+		exporters = append(exporters, sheets)
+	}
+
+	if config.bigQueryTable != "" {
+		client, err := reporting.NewGoogleClient(context.Background(), reporting.BigQueryScope)
+		if err != nil {
+			return nil, err
+		}
+		bigQuery, err := reporting.NewBigQuery(config.bigQueryTable, client)
+		if err != nil {
+			return nil, err
+		}
+		exporters = append(exporters, bigQuery)
+	}
+
+	if config.influxDBFilePath != "" || config.influxDBWriteURL != "" {
+		influxDB, err := reporting.NewInfluxDB(config.influxDBFilePath,
+			config.influxDBWriteURL, os.Getenv("INFLUXDB_TOKEN"))
+		if err != nil {
+			return nil, err
+		}
+		exporters = append(exporters, influxDB)
+	}
+
+	if config.slackWebhookURL != "" {
+		slack, err := reporting.NewSlack(config.slackWebhookURL)
+		if err != nil {
+			return nil, err
+		}
+		slack.Previous = previousResults
+		exporters = append(exporters, slack)
+	}
+
+	if config.discordWebhookURL != "" {
+		discord, err := reporting.NewDiscord(config.discordWebhookURL)
+		if err != nil {
+			return nil, err
+		}
+		discord.Previous = previousResults
+		exporters = append(exporters, discord)
+	}
+
+	if config.teamsWebhookURL != "" {
+		teams, err := reporting.NewTeams(config.teamsWebhookURL)
+		if err != nil {
+			return nil, err
+		}
+		teams.Previous = previousResults
+		exporters = append(exporters, teams)
+	}
+
+	if config.smtpHost != "" {
+		var recipients []string
+		for _, recipient := range strings.Split(config.emailTo, ",") {
+			if strings.TrimSpace(recipient) != "" {
+				recipients = append(recipients, strings.TrimSpace(recipient))
+			}
+		}
+		email, err := reporting.NewEmail(config.smtpHost, config.smtpPort, config.emailFrom, recipients)
+		if err != nil {
+			return nil, err
+		}
+		email.Username = config.smtpUsername
+		email.Password = os.Getenv("SMTP_PASSWORD")
+		email.Previous = previousResults
+		email.Format = config.output
+		exporters = append(exporters, email)
+	}
+
+	if config.matrixHomeserver != "" {
+		matrix, err := reporting.NewMatrix(config.matrixHomeserver, config.matrixRoom,
+			os.Getenv("MATRIX_ACCESS_TOKEN"))
+		if err != nil {
+			return nil, err
+		}
+		matrix.Previous = previousResults
+		exporters = append(exporters, matrix)
+	}
+
+	return exporters, nil
+}
+
+// parseKeyValues parses a comma separated list of key=value pairs
+func parseKeyValues(pairs string) (keyValues map[string]string, err error) {
+
+	keyValues = make(map[string]string)
+	if pairs == "" {
+		return keyValues, nil
+	}
+
+	for _, pair := range strings.Split(pairs, ",") {
+		key, value, found := strings.Cut(pair, "=")
+		if !found || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("expected a key=value pair, got %q", pair)
+		}
+		keyValues[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return keyValues, nil
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/christopher-s-jones/ghcontributions/reporting"
+)
+
+// forecastYear projects the current year's contributions from the daily counts
+// of every collector's provider that has a contribution calendar
+func forecastYear(ctx context.Context, collectors []*reporting.ProviderCollector, now time.Time) *reporting.Forecast {
+
+	var days []reporting.ContributionDay
+	yearToDate := reporting.DateRange{From: reporting.YearRange(now.UTC().Year(), now.UTC().Year()).From, To: now}
+	for _, collector := range collectors {
+		if source, ok := collector.Provider.(reporting.CalendarSource); ok {
+			userDays, err := source.ContributionDays(ctx, collector.User, yearToDate)
+			if err != nil {
+				slog.Warn("Couldn't read the contribution calendar", "user", collector.User, "error", err)
+				continue
+			}
+			days = append(days, userDays...)
+		}
+	}
+	forecast := reporting.ForecastYear(days, now)
+	return &forecast
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/christopher-s-jones/ghcontributions/reporting"
+)
+
+// loadGoals reads and validates the JSON goals file
+func loadGoals(path string) ([]reporting.Goal, error) {
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the goals file: %w", err)
+	}
+	return reporting.ParseGoals(data)
+}
+
+// trackGoals collects each goal's current period from every collector's provider
+// and measures the progress toward the goal, counting like the reporter
+func trackGoals(ctx context.Context, reporter *reporting.Reporter, goals []reporting.Goal,
+	collectors []*reporting.ProviderCollector, now time.Time) []reporting.GoalProgress {
+
+	// Goals over the same period share a collection
+	var queryResultsByPeriod = make(map[reporting.DateRange]map[string]reporting.QueryResult)
+	var progress []reporting.GoalProgress
+	for _, goal := range goals {
+		dateRange, _ := goal.Range(now)
+		queryResults, found := queryResultsByPeriod[dateRange]
+		if !found {
+			queryResults = make(map[string]reporting.QueryResult)
+			for _, collector := range collectors {
+				periodCollector := reporting.ProviderCollector{
+					Provider: collector.Provider,
+					User:     collector.User,
+					Range:    dateRange,
+				}
+				userQueryResults, err := periodCollector.Collect(ctx)
+				reporting.MergeQueryResults(queryResults, userQueryResults)
+				if err != nil {
+					slog.Error("Couldn't collect", "user", collector.User, "error", err)
+				}
+			}
+			queryResultsByPeriod[dateRange] = queryResults
+		}
+		progress = append(progress, reporter.GoalProgress(goal, queryResults, now))
+	}
+	return progress
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/christopher-s-jones/ghcontributions/reporting"
+)
+
+// writeHeatmap renders the past year's daily counts of every collector's provider
+// that has a contribution calendar as one SVG heatmap, written to the path
+func writeHeatmap(ctx context.Context, path string, collectors []*reporting.ProviderCollector, now time.Time) error {
+
+	var days []reporting.ContributionDay
+	dateRange := reporting.HeatmapRange(now)
+	for _, collector := range collectors {
+		if source, ok := collector.Provider.(reporting.CalendarSource); ok {
+			userDays, err := source.ContributionDays(ctx, collector.User, dateRange)
+			if err != nil {
+				slog.Warn("Couldn't read the contribution calendar", "user", collector.User, "error", err)
+				continue
+			}
+			days = append(days, userDays...)
+		}
+	}
+
+	var heatmap bytes.Buffer
+	err := reporting.WriteHeatmap(&heatmap, days, dateRange)
+	if err != nil {
+		return err
+	}
+	return reporting.WriteFileAtomically(path, heatmap.Bytes())
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/christopher-s-jones/ghcontributions/reporting"
+)
+
+// history runs the history subcommand, showing how the totals changed over
+// the snapshots recorded with -snapshots
+func history(args []string) {
+
+	flags := flag.NewFlagSet("history", flag.ExitOnError)
+	snapshotsPath := flags.String("snapshots", "", "The path of the snapshot history, as given to -snapshots \nwhen reporting.")
+	period := flags.String("by", "month", "The period to show the last run of, either run, day, \nmonth, or year.")
+	user := flags.String("user", "", "Only the totals of this username.")
+	asJSON := flags.Bool("json", false, "Whether to print the history as JSON.")
+	flags.Usage = func() {
+		fmt.Println("Show how the totals changed over the recorded runs")
+		fmt.Println("\nUsage:")
+		fmt.Printf(" %s history -snapshots history.db [options]\n\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if *snapshotsPath == "" {
+		flags.Usage()
+		log.Fatalf("The history subcommand requires a -snapshots file")
+	}
+	if _, err := os.Stat(*snapshotsPath); err != nil {
+		log.Fatalf("Couldn't find the snapshots: %s", err)
+	}
+	store, err := reporting.NewSnapshotStore(*snapshotsPath)
+	if err != nil {
+		log.Fatalf("Couldn't open the snapshots: %s", err)
+	}
+	snapshots, err := store.Snapshots()
+	if err != nil {
+		log.Fatalf("Couldn't load the snapshots: %s", err)
+	}
+	points, err := reporting.History(snapshots, *period, *user)
+	if err != nil {
+		flags.Usage()
+		log.Fatalf("Couldn't summarize the history: %s", err)
+	}
+
+	if !*asJSON {
+		fmt.Print(reporting.HistoryText(points))
+		return
+	}
+	pointsJSON, err := json.MarshalIndent(points, "", "  ")
+	if err != nil {
+		log.Fatalf("Couldn't report the history: %s", err)
+	}
+	fmt.Println(string(pointsJSON))
+}
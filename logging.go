@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+)
+
+// The formats the run's logs can be written in
+var logFormats = []string{"text", "json"}
+
+// newLogger builds the logger of a run, writing text or JSON records to the writer,
+// including each user-year collected when verbose, and only warnings and errors
+// when quiet
+func newLogger(w io.Writer, format string, verbose bool, quiet bool) (*slog.Logger, error) {
+
+	level := slog.LevelInfo
+	if verbose {
+		level = slog.LevelDebug
+	}
+	if quiet {
+		level = slog.LevelWarn
+	}
+	options := &slog.HandlerOptions{Level: level}
+	switch format {
+	case "text":
+		return slog.New(slog.NewTextHandler(w, options)), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, options)), nil
+	default:
+		return nil, fmt.Errorf("unknown log format %q", format)
+	}
+}
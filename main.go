@@ -1,106 +1,1013 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
-	"maps"
+	"log/slog"
 	"os"
 	"os/exec"
+	"os/signal"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/christopher-s-jones/ghcontributions/reporting"
-	"github.com/shurcooL/githubv4"
-	"golang.org/x/oauth2"
+	"golang.org/x/term"
 )
 
 func main() {
 
+	// Stop collecting on an interrupt or termination
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Run the subcommand named, or collect
+	if !runSubcommand(ctx, os.Args[1:]) {
+		fmt.Println("Usage:")
+		printSubcommands()
+		log.Fatalf("Unknown subcommand %q", os.Args[1])
+	}
+}
+
+// collectCommand runs the collect subcommand, collecting and reporting the
+// contributions once or as a daemon, or the serve subcommand, serving them
+func collectCommand(ctx context.Context, args []string, serving bool) {
+
 	// Configure the command based on command line flags
-	config, err := Configure()
+	config, err := Configure(args)
 	if err != nil {
 		flag.Usage()
 		log.Fatalf("Couldn't parse the command line arguments: %s\n", err)
 	}
 
-	// Load and set Github API tokens per user
-	var jsonBytes []byte
-	if config.credentialsAreEncrypted {
-		jsonBytes, err = exec.Command("gpg", "-d", config.credentialsFilePath).Output()
+	// Log with the level and format asked for, with the remaining log calls,
+	// like the fatal ones, logged as errors so -quiet keeps them
+	logger, err := newLogger(os.Stderr, config.logFormat, config.verbose, config.quiet)
+	if err != nil {
+		flag.Usage()
+		log.Fatalf("The -log-format must be one of %s", strings.Join(logFormats, ", "))
+	}
+	slog.SetDefault(logger)
+	slog.SetLogLoggerLevel(slog.LevelError)
+
+	// Load and set Github API tokens per user, or use synthetic users for a demo
+	credentials := &reporting.Credentials{}
+	if config.demo {
+		*credentials = reporting.DemoCredentials()
+	} else {
+		users, err := listUsers(config.user, config.users, config.usersFilePath)
 		if err != nil {
 			flag.Usage()
-			log.Fatalf("Couldn't decrypt the credentials file: %s", err)
+			log.Fatalf("Couldn't list the users: %s", err)
 		}
-	} else {
-		jsonBytes, err = os.ReadFile(config.credentialsFilePath)
+		credentials, err = loadCredentials(config.credentialsFilePath, config.credentialsAreEncrypted, config.passphraseFile,
+			users, config.apiURL)
 		if err != nil {
 			flag.Usage()
-			log.Fatalf("Couldn't read the credentials file: %s\n", err)
+			log.Fatalf("Couldn't load the credentials: %s", err)
 		}
 	}
 
-	// Build a Credentials object from the JSON file
-	jsonStr := string(jsonBytes)
-	credentials := &reporting.Credentials{}
-	err = json.Unmarshal([]byte(jsonStr), credentials)
+	if _, err := reporting.NewEncoder(config.output); err != nil {
+		flag.Usage()
+		log.Fatalf("The -output format must be one of %s", strings.Join(reporting.EncoderFormats(), ", "))
+	}
+
+	if config.atomFeedPath != "" && config.snapshotsPath == "" {
+		flag.Usage()
+		log.Fatalf("The -atom flag requires a -snapshots file")
+	}
+
+	if config.incremental && config.reportPath == "" {
+		flag.Usage()
+		log.Fatalf("The -incremental flag requires a -report file")
+	}
+
+	if config.daemon && config.reportPath == "" {
+		flag.Usage()
+		log.Fatalf("The -daemon flag requires a -report file")
+	}
+
+	// Name fiscal years like the year they end in, with the current one last by default
+	if config.fiscalYearStart < 0 || config.fiscalYearStart > 12 {
+		flag.Usage()
+		log.Fatalf("The -fiscal-year-start must be a month from 1 to 12")
+	}
+	fiscalYearStart := time.Month(config.fiscalYearStart)
+	if fiscalYearStart > time.January && config.incremental {
+		flag.Usage()
+		log.Fatalf("The -incremental flag can't reuse the calendar years of a report as fiscal years")
+	}
+	applyFiscalYearDefault(&config, time.Now())
+
+	// Break the years down into quarters or months, collected a period at a time
+	if !slices.Contains(reporting.Granularities, config.granularity) {
+		flag.Usage()
+		log.Fatalf("The -granularity must be one of %s", strings.Join(reporting.Granularities, ", "))
+	}
+	if config.granularity != reporting.GranularityYear && config.incremental {
+		flag.Usage()
+		log.Fatalf("The -incremental flag can't reuse the years of a report without their %ss", config.granularity)
+	}
+
+	// Count the repositories from the lists by repository or from GitHub's totals
+	if !slices.Contains(reporting.RepositoryCountModes, config.repositoryCount) {
+		flag.Usage()
+		log.Fatalf("The -repository-count must be one of %s", strings.Join(reporting.RepositoryCountModes, ", "))
+	}
+
+	// Aggregate only the repositories of the organizations and patterns asked for,
+	// and leave out forks and archived repositories when asked to
+	config.repositoryFilter = reporting.RepositoryFilter{Owners: config.orgs, ExcludedOwners: config.excludeOrgs,
+		Include: config.includeRepos, Exclude: config.excludeRepos, ExcludeForks: config.excludeForks,
+		ExcludeArchived: config.excludeArchived}
+	if err := config.repositoryFilter.Validate(); err != nil {
+		flag.Usage()
+		log.Fatalf("The -include-repo and -exclude-repo patterns must be valid: %s", err)
+	}
+
+	// Collect from and to the months asked for, rather than whole years
+	if err := resolveDateRange(&config); err != nil {
+		flag.Usage()
+		log.Fatalf("The -from and -to flags replace -firstyear and -lastyear")
+	}
+	config.dateRange, err = reporting.ParseDateRange(config.from, config.to)
+	if err != nil {
+		flag.Usage()
+		log.Fatalf("Couldn't parse the -from and -to range: %s", err)
+	}
+	if !config.dateRange.From.IsZero() {
+		config.firstReportingYear = reporting.FiscalYear(config.dateRange.From, fiscalYearStart)
+	}
+	if !config.dateRange.To.IsZero() {
+		config.lastReportingYear = reporting.FiscalYear(config.dateRange.To.Add(-time.Second), fiscalYearStart)
+	}
+
+	// Keep the snapshots to report changes since the last run
+	var snapshotStore reporting.SnapshotStore
+	if config.snapshotsPath != "" {
+		snapshotStore, err = reporting.NewSnapshotStore(config.snapshotsPath)
+		if err != nil {
+			log.Fatalf("Couldn't create a snapshot store: %s", err)
+		}
+	}
+
+	// Check the issue key pattern before collecting anything
+	var ticketPattern *regexp.Regexp
+	if config.tickets {
+		ticketPattern, err = regexp.Compile(config.ticketPattern)
+		if err != nil {
+			flag.Usage()
+			log.Fatalf("Couldn't parse the -ticket-pattern: %s", err)
+		}
+	}
+
+	// Check the goals before collecting anything
+	var goals []reporting.Goal
+	if config.goalsPath != "" {
+		goals, err = loadGoals(config.goalsPath)
+		if err != nil {
+			flag.Usage()
+			log.Fatalf("Couldn't load the goals: %s", err)
+		}
+	}
+
+	// Serve the latest results or collect again at each interval as a daemon, or else once
+	if serving {
+		err = serve(ctx, config.listen, config.interval, func(ctx context.Context, server *reporting.Server) error {
+			_, err := collectAndReport(ctx, config, *credentials, snapshotStore, ticketPattern, goals, server)
+			return err
+		})
+		if err != nil {
+			log.Fatalf("Couldn't serve the results: %s", err)
+		}
+		return
+	}
+	if config.daemon {
+		err = daemon(ctx, config.interval, make(chan struct{}, 1), func(ctx context.Context) error {
+			_, err := collectAndReport(ctx, config, *credentials, snapshotStore, ticketPattern, goals)
+			return err
+		})
+		if err != nil {
+			log.Fatalf("Couldn't run the daemon: %s", err)
+		}
+		return
+	}
+	aggregatedResults, err := collectAndReport(ctx, config, *credentials, snapshotStore, ticketPattern, goals)
 	if err != nil {
-		log.Fatalf("Couldn't parse JSON credentials file: %s", err)
+		log.Fatalf("Couldn't report the contributions: %s", err)
+	}
+
+	// Signal any goals that are behind pace to the calling automation
+	// with an exit status distinct from the failures log.Fatal reports
+	if config.failBehind {
+		behind := false
+		for _, goal := range aggregatedResults.Goals {
+			if goal.Behind {
+				slog.Warn("Behind on a goal", "goal", goal.String())
+				behind = true
+			}
+		}
+		if behind {
+			os.Exit(3)
+		}
+	}
+}
+
+// collectAndReport runs the collection once, reporting the results to the
+// configured outputs and any other exporters. The report is returned for any
+// goals behind pace.
+func collectAndReport(ctx context.Context, config Configuration, credentials reporting.Credentials,
+	snapshotStore reporting.SnapshotStore, ticketPattern *regexp.Regexp, goals []reporting.Goal,
+	otherExporters ...reporting.Exporter) (aggregatedResults reporting.AggregatedResults, err error) {
+
+	// Load the previous report to reuse its final years, unless it's the first run
+	var previousReport *reporting.Report
+	if config.incremental {
+		previousReport, err = readReport(config.reportPath)
+		if errors.Is(err, os.ErrNotExist) {
+			slog.Info("Collecting every year, as there's no previous report", "report", config.reportPath)
+		} else if err != nil {
+			return aggregatedResults, fmt.Errorf("failed to load the previous report: %w", err)
+		}
+	}
+
+	// Load the previous snapshot to report changes since the last run
+	var previousResults *reporting.AggregatedResults
+	if snapshotStore != nil {
+		previous, found, err := reporting.LatestSnapshot(snapshotStore)
+		if err != nil {
+			return aggregatedResults, fmt.Errorf("failed to load the previous snapshot: %w", err)
+		}
+		if found {
+			previousResults = &previous.Results
+		}
+	}
+
+	// Configure the exporters that receive the results
+	exporters, err := configureExporters(config, previousResults)
+	if err != nil {
+		return aggregatedResults, fmt.Errorf("failed to configure the exporters: %w", err)
+	}
+	exporters = append(exporters, otherExporters...)
+	var statsd *reporting.StatsD
+	for _, exporter := range exporters {
+		if s, ok := exporter.(*reporting.StatsD); ok {
+			statsd = s
+			defer statsd.Close()
+		}
+	}
+
+	// Let a hook prepare for the run, stopping it if the hook fails
+	hookVariables := map[string]string{
+		"REPORT": config.reportPath,
+		"USERS":  credentials.Usernames(),
+	}
+	if config.preCollectHook != "" {
+		err = reporting.RunHook(ctx, config.preCollectHook, hookVariables)
+		if err != nil {
+			return aggregatedResults, fmt.Errorf("failed to run the -pre-collect hook: %w", err)
+		}
+	}
+
+	// Send the provider API requests with any custom headers
+	httpClient, stats, err := newHTTPClient(config.userAgent, config.headers)
+	if err != nil {
+		return aggregatedResults, fmt.Errorf("failed to configure the API requests: %w", err)
+	}
+
+	// Read the past years from the cache, except for the synthetic demo users, or
+	// for fiscal years or finer periods, as the cache holds calendar years, or for
+	// the repository details, as the cache holds the years collected without them
+	fiscalYearStart := time.Month(config.fiscalYearStart)
+	var cache *reporting.Cache
+	if !config.noCache && !config.demo && fiscalYearStart <= time.January &&
+		config.granularity == reporting.GranularityYear && !config.repositoryDetails {
+		cache, err = reporting.NewCache(config.cacheDir)
+		if err != nil {
+			return aggregatedResults, fmt.Errorf("failed to open the cache: %w", err)
+		}
+		cache.TTL = config.cacheTTL
+		cache.OnLookup = stats.RecordCacheLookup
 	}
 
 	// List repositories for each user and get statistics
-	var reporter reporting.Reporter
+	runStart := time.Now()
+	var users []string
+	reporter := reporting.Reporter{IncludeRestricted: config.includeRestricted, FiscalYearStart: fiscalYearStart,
+		RepositoryCount: config.repositoryCount, Filter: config.repositoryFilter}
 	var queryResultsByUser = make(map[string]reporting.QueryResult)
-	for _, credential := range *credentials {
-		src := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: credential.Token})
-		httpClient := oauth2.NewClient(context.Background(), src)
-		apiClient := githubv4.NewClient(httpClient)
+	var periodResults = make(map[string]map[string]reporting.QueryResult)
+	var pullRequests []reporting.PullRequest
+	var collectors []*reporting.ProviderCollector
+	var collectionErrors []reporting.CollectionError
+	var profiles = make(map[string]reporting.Profile)
+
+	// Draw the progress of each user's years on a terminal, unless asked not to
+	var progress *reporting.Progress
+	var tasks []*reporting.ProgressTask
+	if !config.noProgress && !config.quiet && term.IsTerminal(int(os.Stderr.Fd())) {
+		progress = reporting.NewProgress(os.Stderr, config.concurrency)
+		// Log above the bars until the collection is reported
+		logger, err := newLogger(progress, config.logFormat, config.verbose, config.quiet)
+		if err != nil {
+			return aggregatedResults, err
+		}
+		defer slog.SetDefault(slog.Default())
+		slog.SetDefault(logger)
+	}
+	for _, credential := range credentials {
+		provider, err := newProvider(credential, httpClient)
+		if err != nil {
+			return aggregatedResults, fmt.Errorf("failed to create a provider for %s: %w", credential.Username, err)
+		}
+		if gitHub, ok := provider.(*reporting.GitHub); ok {
+			gitHub.PublicOrganizationsOnly = config.publicOrganizations
+			gitHub.RepositoryDetails = config.repositoryDetails
+			gitHub.StopAtInactiveYear = config.stopAtInactiveYear
+			gitHub.FiscalYearStart = fiscalYearStart
+			gitHub.Granularity = config.granularity
+			gitHub.RetryPolicy = reporting.DefaultRetryPolicy(config.retries)
+			gitHub.RetryPolicy.OnRetry = func(attempt int, err error) {
+				slog.Warn("Retrying", "user", credential.Username, "attempt", attempt, "error", err)
+				stats.RecordRetry()
+			}
+			gitHub.RateLimitPolicy.OnRateLimit = func(user string, rateLimit reporting.RateLimit) {
+				stats.RecordQueryCost(int(rateLimit.Cost))
+				if config.showRateLimit {
+					slog.Info("Queried", "user", user, "cost", int(rateLimit.Cost), "remaining", int(rateLimit.Remaining),
+						"reset", rateLimit.ResetAt.Local().Format(time.TimeOnly))
+				}
+			}
+		}
+
+		// Without a first year, start from the first year the user could have contributed in
 		firstYear := config.firstReportingYear
+		if source, ok := provider.(reporting.FirstYearSource); ok && firstYear == 0 {
+			firstYear, err = source.FirstYear(ctx, credential.Username)
+			if err != nil {
+				slog.Warn("Couldn't find the first year", "user", credential.Username,
+					"using", reporting.DefaultFirstContributionYear, "error", err)
+			}
+			firstYear = min(firstYear, config.lastReportingYear)
+		}
+		// The current fiscal year can end after this year
 		lastYear := config.lastReportingYear
-		reporter, err = reporting.NewReporter(apiClient, credential.Username, firstYear, lastYear)
+		if fiscalYearStart > time.January {
+			lastYear = min(lastYear, time.Now().UTC().Year())
+		}
+		collector, err := reporting.NewProviderCollector(provider, credential.Username, firstYear, lastYear)
+		if err != nil {
+			return aggregatedResults, fmt.Errorf("failed to create a collector for %s: %w", credential.Username, err)
+		}
+		collector.Granularity = config.granularity
+		if fiscalYearStart > time.January {
+			collector.FiscalYearStart = fiscalYearStart
+			collector.Range = reporting.FiscalYearRange(collector.Range.From.Year(), config.lastReportingYear, fiscalYearStart)
+		}
+		if !config.dateRange.From.IsZero() {
+			collector.Range.From = config.dateRange.From
+		}
+		if !config.dateRange.To.IsZero() {
+			collector.Range.To = config.dateRange.To
+		}
+		users = append(users, credential.Username)
+		collectors = append(collectors, collector)
+
+		var task *reporting.ProgressTask
+		if progress != nil {
+			task = progress.Track(credential.Username,
+				reporting.FiscalYear(collector.Range.To.Add(-time.Second), fiscalYearStart)-
+					reporting.FiscalYear(collector.Range.From, fiscalYearStart)+1)
+		}
+		if gitHub, ok := provider.(*reporting.GitHub); ok {
+			gitHub.OnQuery = func(user string, years int, latency time.Duration) {
+				task.Advance(years, latency)
+			}
+		}
+		tasks = append(tasks, task)
+	}
+
+	// Collect the users concurrently, then combine their results in order. The
+	// reused years already combine every provider, so they're added once per user.
+	var collected = make(map[string]reporting.DateRange)
+	reused := 0
+	for i, collection := range collectUsers(ctx, credentials, collectors, tasks, cache, previousReport,
+		config.concurrency, config.tickets) {
+		credential := credentials[i]
+		if _, found := collected[credential.Username]; !found {
+			reporting.MergeQueryResults(queryResultsByUser, collection.reused)
+			reused += len(collection.reused)
+		}
+		collected[credential.Username] = collectors[i].Range
+		reporting.MergeQueryResults(queryResultsByUser, collection.queryResults)
+		reporting.MergePeriodResults(periodResults, collection.periodResults)
+		if collection.err != nil {
+			collectionErrors = append(collectionErrors, reporting.NewCollectionError(credential,
+				collection.dateRange, fiscalYearStart, collection.queryResults, collection.err))
+		}
+		if statsd != nil {
+			tags := map[string]string{"user": credential.Username}
+			statsd.Timing("collect.duration", collection.duration, tags)
+			statsd.Gauge("collect.user_years", len(collection.queryResults), tags)
+		}
+
+		// Describe each user with the first profile found
+		if _, found := profiles[credential.Username]; !found && collection.profile != nil {
+			profiles[credential.Username] = *collection.profile
+		}
+		pullRequests = append(pullRequests, collection.pullRequests...)
+	}
+
+	if previousReport != nil {
+		slog.Info("Reused the final user-years of the previous report", "count", reused)
+	}
+
+	// After an interrupt, still report the user-years collected so far, as
+	// their errors let retry-failed finish the collection later
+	interrupted := ctx.Err() != nil
+	if interrupted {
+		slog.Warn("Interrupted, reporting the user-years collected so far")
+	}
+	aggregatedResults, err = reporter.Aggregate(context.WithoutCancel(ctx), queryResultsByUser)
+	if err != nil {
+		return aggregatedResults, fmt.Errorf("failed to aggregate the results: %w", err)
+	}
+	aggregatedResults.Errors = collectionErrors
+	if counts := aggregatedResults.RepositoryCounts; counts != nil && len(counts.Diverged) > 0 {
+		slog.Warn("The repositories listed diverge from GitHub's totals", "listed", counts.Listed,
+			"totals", counts.Totals, "userYears", strings.Join(counts.Diverged, ","))
+	}
+	if config.granularity != reporting.GranularityYear {
+		aggregatedResults.ByPeriod = reporter.AggregatePeriods(periodResults)
+		aggregatedResults.Granularity = config.granularity
+	}
+	if config.top > 0 {
+		aggregatedResults.Repositories = aggregatedResults.Repositories[:min(config.top, len(aggregatedResults.Repositories))]
+	}
+	if len(profiles) > 0 {
+		aggregatedResults.Profiles = profiles
+	}
+	if config.tickets {
+		aggregatedResults.TicketProjects = reporting.GroupByTicketProject(pullRequests, ticketPattern)
+	}
+	if config.collaborators > 0 {
+		// Use the first provider that can list contributors, as any token can read public repositories
+		for _, collector := range collectors {
+			if source, ok := collector.Provider.(reporting.ContributorSource); ok {
+				repositories := reporting.TopRepositories(queryResultsByUser, config.collaborators)
+				aggregatedResults.Collaborators, err = reporting.FindCollaborators(ctx,
+					source, repositories, users)
+				if err != nil {
+					slog.Warn("Couldn't find the collaborators", "error", err)
+				}
+				break
+			}
+		}
+	}
+	if config.activity > 0 {
+		location, err := time.LoadLocation(config.activityTimezone)
+		if err != nil {
+			return aggregatedResults, fmt.Errorf("failed to load the -activity-timezone: %w", err)
+		}
+		repositories := reporting.TopRepositories(queryResultsByUser, config.activity)
+		aggregatedResults.Activity = sampleActivity(ctx, collectors, repositories, location)
+	}
+	if config.scriptPath != "" {
+		script, err := os.ReadFile(config.scriptPath)
+		if err != nil {
+			return aggregatedResults, fmt.Errorf("failed to read the script: %w", err)
+		}
+		aggregatedResults.Custom, err = reporting.RunScript(config.scriptPath, script, queryResultsByUser)
+		if err != nil {
+			return aggregatedResults, fmt.Errorf("failed to run the script: %w", err)
+		}
+	}
+	if config.forecast {
+		aggregatedResults.Forecast = forecastYear(ctx, collectors, time.Now())
+	}
+	if config.streaks {
+		aggregatedResults.Streaks = findStreaks(ctx, collectors, time.Now())
+	}
+	if config.heatmapPath != "" {
+		err = writeHeatmap(ctx, config.heatmapPath, collectors, time.Now())
+		if err != nil {
+			return aggregatedResults, fmt.Errorf("failed to write the heatmap: %w", err)
+		}
+	}
+	if len(goals) > 0 {
+		aggregatedResults.Goals = trackGoals(ctx, &reporter, goals, collectors, time.Now())
+	}
+	runStats := stats.RunStats(time.Since(runStart))
+	aggregatedResults.RunStats = &runStats
+	if config.showRateLimit {
+		slog.Info("The contributions queries cost GitHub rate limit points", "cost", runStats.QueryCost)
+	}
+	err = printResults(config.output, config.outputPath, queryResultsByUser, aggregatedResults)
+	if err != nil {
+		return aggregatedResults, fmt.Errorf("failed to report the results: %w", err)
+	}
+
+	// Keep the query results with the report so failed collections can be retried,
+	// except that an interrupted run doesn't replace a complete report
+	if config.reportPath != "" && interrupted && isCompleteReport(config.reportPath) {
+		slog.Warn("Interrupted, keeping the complete report", "report", config.reportPath)
+	} else if config.reportPath != "" {
+		err = writeReport(config.reportPath, reporting.Report{
+			AggregatedResults: aggregatedResults,
+			QueryResults:      queryResultsByUser,
+			Collected:         collected,
+			Sources:           credentials.Sources(),
+		})
+		if err != nil {
+			return aggregatedResults, fmt.Errorf("failed to write the report: %w", err)
+		}
+	}
+
+	// The partial results of an interrupted run would skew the deltas and the
+	// history, so they're only printed and kept in the report
+	if interrupted {
+		slog.Warn("Interrupted, skipping the exporters and the snapshot")
+		runPostReportHook(ctx, config, hookVariables, aggregatedResults, true)
+		return aggregatedResults, nil
+	}
+
+	// Send the results to the configured exporters
+	for _, exporter := range exporters {
+		err = exporter.Export(queryResultsByUser, aggregatedResults)
+		if err != nil {
+			slog.Error("Couldn't export the results", "error", err)
+		}
+	}
+
+	// Record the results for comparison with the next run, from the earliest first year collected
+	if snapshotStore != nil {
+		firstYear := config.lastReportingYear
+		for _, collector := range collectors {
+			firstYear = min(firstYear, collector.Range.From.Year())
+		}
+		snapshot := reporting.Snapshot{
+			Timestamp:    aggregatedResults.Timestamp,
+			Results:      aggregatedResults,
+			QueryResults: queryResultsByUser,
+			Run: reporting.RunMetadata{
+				StartTime:       int(runStart.Unix()),
+				DurationSeconds: time.Since(runStart).Seconds(),
+				FirstYear:       firstYear,
+				LastYear:        config.lastReportingYear,
+				Users:           users,
+			},
+		}
+		err = snapshotStore.Save(snapshot)
+		if err != nil {
+			return aggregatedResults, fmt.Errorf("failed to save the snapshot: %w", err)
+		}
+	}
+
+	// Publish the snapshot history as an Atom feed
+	if config.atomFeedPath != "" {
+		snapshots, err := snapshotStore.Snapshots()
+		if err != nil {
+			return aggregatedResults, fmt.Errorf("failed to load the snapshots: %w", err)
+		}
+		var feed bytes.Buffer
+		err = reporting.WriteAtomFeed(&feed, snapshots, config.atomFeedURL)
+		if err != nil {
+			return aggregatedResults, fmt.Errorf("failed to render the Atom feed: %w", err)
+		}
+		err = reporting.WriteFileAtomically(config.atomFeedPath, feed.Bytes())
+		if err != nil {
+			return aggregatedResults, fmt.Errorf("failed to write the Atom feed: %w", err)
+		}
+	}
+
+	runPostReportHook(ctx, config, hookVariables, aggregatedResults, false)
+	return aggregatedResults, nil
+}
+
+// runPostReportHook lets the -post-report hook, if any, pass the report on, with
+// a partial status when the run was interrupted or a collection failed
+func runPostReportHook(ctx context.Context, config Configuration, hookVariables map[string]string,
+	aggregatedResults reporting.AggregatedResults, interrupted bool) {
+
+	if config.postReportHook == "" {
+		return
+	}
+	hookVariables["STATUS"] = reporting.HookStatusSuccess
+	if interrupted || len(aggregatedResults.Errors) > 0 {
+		hookVariables["STATUS"] = reporting.HookStatusPartial
+	}
+	hookVariables["TIMESTAMP"] = strconv.Itoa(aggregatedResults.Timestamp)
+	err := reporting.RunHook(context.WithoutCancel(ctx), config.postReportHook, hookVariables)
+	if err != nil {
+		slog.Error("Couldn't run the -post-report hook", "error", err)
+	}
+}
+
+// loadCredentials reads the credentials like readCredentials, pointing the GitHub
+// credentials without a URL of their own at the API URL, if any
+func loadCredentials(path string, encrypted bool, passphraseFile string, users []string,
+	apiURL string) (*reporting.Credentials, error) {
+
+	credentials, err := readCredentials(path, encrypted, passphraseFile, users)
+	if err != nil {
+		return nil, err
+	}
+	for i, credential := range *credentials {
+		if (credential.Provider == "" || credential.Provider == reporting.ProviderGitHub) && credential.URL == "" {
+			(*credentials)[i].URL = apiURL
+		}
+	}
+	return credentials, nil
+}
+
+// The credentials path reading the tokens of the GitHub CLI's accounts
+const ghAuthCredentials = "gh:"
+
+// readCredentials reads the JSON credentials file, decrypting it if needed, and
+// expands the environment variables in it. A path like keyring: reads them from
+// the OS keychain instead, one like vault://secret/data/ghtokens from a Vault
+// secret, at the VAULT_ADDR server with the VAULT_TOKEN token, and gh: from the
+// accounts the GitHub CLI is logged in to.
+// With users, each is collected with one shared token, the GITHUB_TOKEN variable
+// or else the first GitHub token in the file, as public contributions need no
+// token of their own.
+func readCredentials(path string, encrypted bool, passphraseFile string, users []string) (*reporting.Credentials, error) {
+
+	if len(users) > 0 {
+		token := os.Getenv("GITHUB_TOKEN")
+		if token == "" {
+			fileCredentials, err := readCredentials(path, encrypted, passphraseFile, nil)
+			if err != nil {
+				return nil, fmt.Errorf("the users need the GITHUB_TOKEN variable or a credentials file: %w", err)
+			}
+			for _, credential := range *fileCredentials {
+				if credential.Provider == "" || credential.Provider == reporting.ProviderGitHub {
+					token = credential.Token
+					break
+				}
+			}
+			if token == "" {
+				return nil, fmt.Errorf("the credentials file has no github token for the users")
+			}
+		}
+		credentials := &reporting.Credentials{}
+		for _, user := range users {
+			*credentials = append(*credentials, reporting.Credential{Username: user, Token: token})
+		}
+		return credentials, nil
+	}
+
+	// Read the credentials from the OS keychain, like keyring: or keyring:service
+	if service, found := strings.CutPrefix(path, "keyring:"); found {
+		credentials, err := reporting.NewKeyring(service).Credentials()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the credentials from the keyring: %w", err)
+		}
+		return &credentials, nil
+	}
+
+	// Read the tokens of the accounts gh is logged in to, on every host
+	if path == ghAuthCredentials {
+		credentials, err := reporting.NewGitHubCLI().Credentials(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the credentials from gh: %w", err)
+		}
+		return &credentials, nil
+	}
+
+	// Read the credentials from a Vault key/value secret, like vault://secret/data/ghtokens
+	if secretPath, found := strings.CutPrefix(path, "vault://"); found {
+		vault, err := reporting.NewVault(os.Getenv("VAULT_ADDR"), os.Getenv("VAULT_TOKEN"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the credentials from vault: %w", err)
+		}
+		vault.Namespace = os.Getenv("VAULT_NAMESPACE")
+		credentials, err := vault.Credentials(context.Background(), secretPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the credentials from vault: %w", err)
+		}
+		return &credentials, nil
+	}
+
+	var jsonBytes []byte
+	var err error
+	if encrypted {
+		jsonBytes, err = decryptCredentials(path, passphraseFile)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		jsonBytes, err = os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the credentials file: %w", err)
+		}
+	}
+
+	// Build a Credentials object from the JSON file
+	credentials := &reporting.Credentials{}
+	err = json.Unmarshal(jsonBytes, credentials)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the JSON credentials file: %w", err)
+	}
+	err = credentials.ExpandEnv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to expand the credentials file: %w", err)
+	}
+	return credentials, nil
+}
+
+// decryptCredentials decrypts the PGP encrypted credentials file. A file encrypted
+// with a passphrase, like with gpg -c, is decrypted natively, with the passphrase
+// from the file or prompted for. Otherwise gpg decrypts it, with the keys of its agent.
+func decryptCredentials(path string, passphraseFile string) ([]byte, error) {
+
+	ciphertext, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the credentials file: %w", err)
+	}
+	plaintext, err := reporting.DecryptOpenPGP(ciphertext, func() ([]byte, error) {
+		return readPassphrase(passphraseFile)
+	})
+	if err == nil {
+		return plaintext, nil
+	}
+
+	plaintext, gpgErr := exec.Command("gpg", "-d", path).Output()
+	if gpgErr != nil {
+		return nil, fmt.Errorf("failed to decrypt the credentials file: %w", errors.Join(err, gpgErr))
+	}
+	return plaintext, nil
+}
+
+// readPassphrase reads the passphrase from the file, without its trailing newline,
+// or else prompts for it on the terminal without echoing it
+func readPassphrase(path string) ([]byte, error) {
+
+	if path != "" {
+		passphrase, err := os.ReadFile(path)
 		if err != nil {
-			log.Fatalf("Couldn't create a reporter object: %s", err)
+			return nil, fmt.Errorf("failed to read the passphrase file: %w", err)
+		}
+		return bytes.TrimRight(passphrase, "\r\n"), nil
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return nil, errors.New("the passphrase needs a -passphrase-file without a terminal to prompt on")
+	}
+	fmt.Fprint(os.Stderr, "Passphrase for the credentials file: ")
+	passphrase, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the passphrase: %w", err)
+	}
+	return passphrase, nil
+}
+
+// listUsers combines the usernames of the -user, -users, and -users-file
+// flags, where the file lists a username on each line
+func listUsers(user string, users string, usersFilePath string) ([]string, error) {
+
+	var usernames []string
+	if user != "" {
+		usernames = append(usernames, user)
+	}
+	if users != "" {
+		for _, username := range strings.Split(users, ",") {
+			usernames = append(usernames, strings.TrimSpace(username))
 		}
-		queryResults, err := reporter.Collect()
-		maps.Copy(queryResultsByUser, queryResults)
+	}
+	if usersFilePath != "" {
+		usersFile, err := os.ReadFile(usersFilePath)
 		if err != nil {
-			log.Print(err)
+			return nil, fmt.Errorf("failed to read the users file: %w", err)
+		}
+		for _, line := range strings.Split(string(usersFile), "\n") {
+			if username := strings.TrimSpace(line); username != "" && !strings.HasPrefix(username, "#") {
+				usernames = append(usernames, username)
+			}
 		}
 	}
-	aggregatedResults, _ := reporter.Report(queryResultsByUser)
-	log.Print(aggregatedResults)
+	if slices.Contains(usernames, "") {
+		return nil, fmt.Errorf("the usernames cannot be blank")
+	}
+	return usernames, nil
 }
 
 // A simple configuration to store and pass command line settings
 type Configuration struct {
 	credentialsAreEncrypted bool
+	passphraseFile          string
+	useGHAuth               bool
 	credentialsFilePath     string
+	user                    string
+	users                   string
+	usersFilePath           string
+	demo                    bool
+	output                  string
+	userAgent               string
+	headers                 string
 	firstReportingYear      int
 	lastReportingYear       int
+	fiscalYearStart         int
+	granularity             string
+	from                    string
+	to                      string
+	dateRange               reporting.DateRange
+	pushgatewayURL          string
+	textfilePath            string
+	statsdAddress           string
+	statsdTagged            bool
+	cloudWatchNamespace     string
+	cloudWatchDimensions    string
+	awsRegion               string
+	s3Bucket                string
+	s3Prefix                string
+	s3ServerSideEncryption  string
+	s3KMSKeyID              string
+	gcsBucket               string
+	gcsObjectTemplate       string
+	azureAccount            string
+	azureContainer          string
+	azurePrefix             string
+	azureClientID           string
+	sheetsSpreadsheetID     string
+	sheetsRange             string
+	sheetsRows              string
+	bigQueryTable           string
+	tickets                 bool
+	ticketPattern           string
+	goalsPath               string
+	collaborators           int
+	activity                int
+	activityTimezone        string
+	forecast                bool
+	streaks                 bool
+	heatmapPath             string
+	publicOrganizations     bool
+	repositoryDetails       bool
+	repositoryCount         string
+	orgs                    []string
+	excludeOrgs             []string
+	includeRepos            []string
+	excludeRepos            []string
+	excludeForks            bool
+	excludeArchived         bool
+	repositoryFilter        reporting.RepositoryFilter
+	stopAtInactiveYear      bool
+	top                     int
+	retries                 int
+	showRateLimit           bool
+	outputPath              string
+	configPath              string
+	verbose                 bool
+	quiet                   bool
+	noProgress              bool
+	logFormat               string
+	concurrency             int
+	incremental             bool
+	apiURL                  string
+	includeRestricted       bool
+	daemon                  bool
+	listen                  string
+	interval                time.Duration
+	noCache                 bool
+	cacheDir                string
+	cacheTTL                time.Duration
+	failBehind              bool
+	influxDBFilePath        string
+	influxDBWriteURL        string
+	slackWebhookURL         string
+	discordWebhookURL       string
+	teamsWebhookURL         string
+	smtpHost                string
+	smtpPort                int
+	smtpUsername            string
+	emailFrom               string
+	emailTo                 string
+	matrixHomeserver        string
+	matrixRoom              string
+	reportPath              string
+	scriptPath              string
+	preCollectHook          string
+	postReportHook          string
+	snapshotsPath           string
+	atomFeedPath            string
+	atomFeedURL             string
+	// Where each option was set, like on the command line or in the config file
+	sources map[string]optionSource
 }
 
 // Configure creates a simple configuration based on
 // command line arguments, or uses defaults
-func Configure() (config Configuration, err error) {
+func Configure(args []string) (config Configuration, err error) {
 
 	config = Configuration{}
 
 	// Handle incoming command line flags
+	flag.StringVar(&config.configPath,
+		"config",
+		"",
+		"The path of a YAML config file setting the defaults of the other options, \nread from $GHCONTRIB_CONFIG or ~/.config/ghcontributions/config.yaml when there's one.")
+
+	flag.StringVar(&config.apiURL,
+		"api-url",
+		"",
+		"The base URL of a GitHub Enterprise Server, like https://ghe.example.com, \nfor the GitHub credentials without a url of their own.")
+
 	flag.BoolVar(&config.credentialsAreEncrypted,
 		"encrypted",
 		false,
 		"Whether the credentials file is PGP encrypted.")
 
+	flag.StringVar(&config.passphraseFile,
+		"passphrase-file",
+		"",
+		"The path of a file holding the passphrase of the encrypted \ncredentials file, instead of prompting for it.")
+
+	flag.BoolVar(&config.useGHAuth,
+		"use-gh-auth",
+		false,
+		"Whether to read the tokens of the GitHub CLI's accounts, \nlike -credentials gh:, instead of the credentials file.")
+
+	// Without a user cache directory, -cache-dir or -no-cache is needed
+	defaultCacheDir, _ := reporting.DefaultCacheDir()
+	flag.StringVar(&config.cacheDir,
+		"cache-dir",
+		defaultCacheDir,
+		"The directory caching the query results of past years.")
+
+	flag.DurationVar(&config.cacheTTL,
+		"cache-ttl",
+		0,
+		"How long cached query results are used, like 720h, \nor forever if zero.")
+
+	flag.IntVar(&config.concurrency,
+		"concurrency",
+		defaultConcurrency,
+		"The number of users to collect at once, each with its own \ntoken and rate limit.")
+
 	flag.StringVar(&config.credentialsFilePath,
 		"credentials",
 		"gh-tokens.json",
-		"The name of the file containing Github usernames \nand API token values")
+		"The name of the file containing Github usernames \nand API token values, keyring: for the OS keychain, \nvault://path for a Vault secret, or gh: for the GitHub CLI")
+
+	flag.StringVar(&config.user,
+		"user",
+		"",
+		"A GitHub username to report on with the GITHUB_TOKEN \nvariable, instead of the credentials file.")
+
+	flag.StringVar(&config.users,
+		"users",
+		"",
+		"Comma separated GitHub usernames to report on with one \nshared token, GITHUB_TOKEN or the credentials file's first.")
+
+	flag.StringVar(&config.usersFilePath,
+		"users-file",
+		"",
+		"The path of a file listing a GitHub username on each line, \nreported on like -users.")
+
+	flag.BoolVar(&config.daemon,
+		"daemon",
+		false,
+		"Whether to keep running, collecting again at each -interval \nand writing the latest -report, until interrupted.")
+
+	flag.BoolVar(&config.demo,
+		"demo",
+		false,
+		"Whether to report on synthetic demo users instead of \nthe credentials, without any tokens.")
+
+	flag.StringVar(&config.userAgent,
+		"user-agent",
+		"",
+		"The User-Agent header sent with provider API requests.")
+
+	flag.StringVar(&config.headers,
+		"headers",
+		"",
+		"Comma separated Name=Value headers added to \nprovider API requests.")
+
+	flag.StringVar(&config.outputPath,
+		"o",
+		"",
+		"The path of a file to write the printed results to, \ninstead of standard output.")
+
+	flag.StringVar(&config.output,
+		"output",
+		"json",
+		"The format of the printed results, json, yaml, csv, \nmarkdown, or html.")
 
 	flag.IntVar(&config.firstReportingYear,
 		"firstyear",
-		2000,
-		"The first year to summarize.")
+		0,
+		"The first year to summarize, by default the year each \nGitHub account was created, or 2000 for other providers.")
 
 	year := time.Now().Year()
 	flag.IntVar(&config.lastReportingYear,
@@ -108,6 +1015,382 @@ func Configure() (config Configuration, err error) {
 		year,
 		"The last year to summarize")
 
+	flag.IntVar(&config.fiscalYearStart,
+		"fiscal-year-start",
+		0,
+		"The month the years start in, from 1 to 12, for fiscal years \nnamed like the calendar year they end in.")
+
+	flag.StringVar(&config.granularity,
+		"granularity",
+		reporting.GranularityYear,
+		"The periods the report breaks the contributions down by, \nyear, quarter, or month.")
+
+	flag.StringVar(&config.from,
+		"from",
+		"",
+		"The first month to summarize, like 2021-07, or a date, \ninstead of -firstyear.")
+
+	flag.StringVar(&config.to,
+		"to",
+		"",
+		"The last month to summarize, like 2022-06, or a date, \ninstead of -lastyear.")
+
+	flag.StringVar(&config.pushgatewayURL,
+		"pushgateway",
+		"",
+		"The URL of a Prometheus Pushgateway to push \nthe run's metrics to after collection.")
+
+	flag.StringVar(&config.textfilePath,
+		"textfile",
+		"",
+		"The path of a .prom file in the node_exporter textfile \ncollector directory to write the run's metrics to.")
+
+	flag.StringVar(&config.statsdAddress,
+		"statsd",
+		"",
+		"The host:port of a StatsD endpoint to send \ncollection metrics and totals to.")
+
+	flag.BoolVar(&config.statsdTagged,
+		"statsd-tags",
+		false,
+		"Whether to send DogStatsD tags for the user and year.")
+
+	flag.StringVar(&config.cloudWatchNamespace,
+		"cloudwatch-namespace",
+		"",
+		"The CloudWatch namespace to put the aggregate metrics into.")
+
+	flag.StringVar(&config.cloudWatchDimensions,
+		"cloudwatch-dimensions",
+		"",
+		"Comma separated Name=Value dimensions added \nto the CloudWatch metrics.")
+
+	flag.StringVar(&config.awsRegion,
+		"aws-region",
+		reporting.LoadAWSRegion(),
+		"The AWS region for CloudWatch metrics and S3 uploads.")
+
+	flag.StringVar(&config.s3Bucket,
+		"s3-bucket",
+		"",
+		"The S3 bucket to upload the report artifacts to.")
+
+	flag.StringVar(&config.s3Prefix,
+		"s3-prefix",
+		"",
+		"The key prefix for report artifacts uploaded to S3.")
+
+	flag.StringVar(&config.s3ServerSideEncryption,
+		"s3-sse",
+		"",
+		"The S3 server-side encryption, either AES256 or aws:kms.")
+
+	flag.StringVar(&config.s3KMSKeyID,
+		"s3-kms-key-id",
+		"",
+		"The KMS key ID used with aws:kms encryption.")
+
+	flag.StringVar(&config.gcsBucket,
+		"gcs-bucket",
+		"",
+		"The Google Cloud Storage bucket to upload the report \nartifacts to, using Application Default Credentials.")
+
+	flag.StringVar(&config.gcsObjectTemplate,
+		"gcs-object",
+		reporting.DefaultGCSObjectTemplate,
+		"The template for GCS object names, with the .Date, \n.Time, .Users, and .Name fields.")
+
+	flag.StringVar(&config.azureContainer,
+		"azure-container",
+		"",
+		"The Azure Blob Storage container to upload the report \nartifacts to, authenticated with the AZURE_STORAGE_CONNECTION_STRING \nvariable or a managed identity.")
+
+	flag.StringVar(&config.azureAccount,
+		"azure-account",
+		"",
+		"The Azure storage account, when using a managed identity.")
+
+	flag.StringVar(&config.azurePrefix,
+		"azure-prefix",
+		"",
+		"The name prefix for report artifacts uploaded to Azure.")
+
+	flag.StringVar(&config.azureClientID,
+		"azure-client-id",
+		"",
+		"The client ID of a user-assigned managed identity.")
+
+	flag.StringVar(&config.sheetsSpreadsheetID,
+		"sheets-id",
+		"",
+		"The ID of a Google Sheet to append the results to, \nusing Application Default Credentials.")
+
+	flag.StringVar(&config.sheetsRange,
+		"sheets-range",
+		"A1",
+		"The A1 notation range of the table to append to.")
+
+	flag.StringVar(&config.sheetsRows,
+		"sheets-rows",
+		reporting.SheetsRowsPerRun,
+		"The rows to append to the Google Sheet, either \n\"run\" or \"user-year\".")
+
+	flag.StringVar(&config.bigQueryTable,
+		"bigquery-table",
+		"",
+		"The BigQuery table, like project.dataset.table, to stream \nper user-year rows into, using Application Default Credentials.")
+
+	flag.BoolVar(&config.tickets,
+		"tickets",
+		false,
+		"Whether to group GitHub pull requests by the issue tracker \nprojects of the keys, like PROJ-123, in their titles and bodies.")
+
+	flag.StringVar(&config.ticketPattern,
+		"ticket-pattern",
+		reporting.DefaultTicketPattern,
+		"The regular expression matching issue keys, where the \nproject is the part before the last hyphen.")
+
+	flag.IntVar(&config.collaborators,
+		"collaborators",
+		0,
+		"The number of most contributed repositories to find the other \nGitHub contributors to, reporting the top collaborators.")
+
+	flag.IntVar(&config.activity,
+		"activity",
+		0,
+		"The number of most contributed repositories to sample GitHub \ncommit times from, reporting the commits in each hour of the day.")
+
+	flag.StringVar(&config.activityTimezone,
+		"activity-timezone",
+		"Local",
+		"The IANA time zone, like America/Denver, of the activity hours.")
+
+	flag.BoolVar(&config.forecast,
+		"forecast",
+		false,
+		"Whether to project the current year's contributions from \nthe GitHub contribution calendar so far.")
+
+	flag.BoolVar(&config.streaks,
+		"streaks",
+		false,
+		"Whether to report the current and longest streaks, busiest day, \nand average per active day from the GitHub contribution calendar.")
+
+	flag.StringVar(&config.heatmapPath,
+		"heatmap",
+		"",
+		"The path of an SVG file to draw the past year's GitHub \ncontribution calendar to, merged across the users.")
+
+	flag.BoolVar(&config.publicOrganizations,
+		"public-orgs",
+		false,
+		"Whether to list only the public organization memberships \nin each GitHub user's profile.")
+
+	flag.BoolVar(&config.repositoryDetails,
+		"repository-details",
+		false,
+		"Whether to add the stars, primary language, and description \nof each GitHub repository, and the totals by language, to the report.")
+
+	flag.StringVar(&config.repositoryCount,
+		"repository-count",
+		reporting.RepositoryCountListed,
+		"How to count the repositories, listed from the contributions \nby repository, or from GitHub's totals of repositories.")
+
+	flag.Func("org",
+		"The organization or user owning the only repositories to count, \nrepeated or comma separated for more.",
+		appendList(&config.orgs))
+
+	flag.Func("exclude-org",
+		"The organization or user owning repositories to leave out, \nrepeated or comma separated for more.",
+		appendList(&config.excludeOrgs))
+
+	flag.Func("include-repo",
+		"A glob pattern of the only repositories to count, like 'myorg/*', \nrepeated or comma separated for more.",
+		appendList(&config.includeRepos))
+
+	flag.Func("exclude-repo",
+		"A glob pattern of repositories to leave out, like '*/dotfiles', \nrepeated or comma separated for more.",
+		appendList(&config.excludeRepos))
+
+	flag.BoolVar(&config.excludeForks,
+		"exclude-forks",
+		false,
+		"Whether to leave out the contributions to forks of other \nrepositories.")
+
+	flag.BoolVar(&config.excludeArchived,
+		"exclude-archived",
+		false,
+		"Whether to leave out the contributions to archived repositories.")
+
+	flag.BoolVar(&config.stopAtInactiveYear,
+		"stop-at-inactive-year",
+		false,
+		"Whether to stop collecting a GitHub user at the first year \nwithout any earlier activity, skipping older years after a gap.")
+
+	flag.IntVar(&config.top,
+		"top",
+		0,
+		"The number of repositories with the most contributions to list \nin the report, or every one if zero.")
+
+	flag.StringVar(&config.goalsPath,
+		"goals",
+		"",
+		"The path of a JSON file listing goals, like {\"metric\": \"commits\", \n\"target\": 500, \"period\": \"year\"}, to report progress toward.")
+
+	flag.BoolVar(&config.failBehind,
+		"fail-behind",
+		false,
+		"Whether to exit with status 3 when any goal is behind pace.")
+
+	flag.StringVar(&config.influxDBFilePath,
+		"influxdb-file",
+		"",
+		"The path of a file to write per user-year metrics \nto as InfluxDB line protocol.")
+
+	flag.StringVar(&config.influxDBWriteURL,
+		"influxdb-url",
+		"",
+		"The InfluxDB HTTP write endpoint URL for per user-year \nmetrics, authenticated with the INFLUXDB_TOKEN variable.")
+
+	flag.StringVar(&config.slackWebhookURL,
+		"slack-webhook",
+		"",
+		"The Slack incoming webhook URL to post a summary to.")
+
+	flag.StringVar(&config.discordWebhookURL,
+		"discord-webhook",
+		"",
+		"The Discord webhook URL to post a summary to.")
+
+	flag.StringVar(&config.teamsWebhookURL,
+		"teams-webhook",
+		"",
+		"The Microsoft Teams webhook URL to post a summary card to.")
+
+	flag.StringVar(&config.smtpHost,
+		"smtp-host",
+		"",
+		"The SMTP server used to email the report.")
+
+	flag.IntVar(&config.smtpPort,
+		"smtp-port",
+		587,
+		"The SMTP server port.")
+
+	flag.StringVar(&config.smtpUsername,
+		"smtp-username",
+		"",
+		"The SMTP username, with the password in the \nSMTP_PASSWORD variable.")
+
+	flag.StringVar(&config.emailFrom,
+		"email-from",
+		"",
+		"The sender address of the report email.")
+
+	flag.StringVar(&config.emailTo,
+		"email-to",
+		"",
+		"Comma separated recipient addresses of the report email.")
+
+	flag.StringVar(&config.matrixHomeserver,
+		"matrix-homeserver",
+		"",
+		"The Matrix homeserver URL used to post a summary, with \nthe access token in the MATRIX_ACCESS_TOKEN variable.")
+
+	flag.StringVar(&config.matrixRoom,
+		"matrix-room",
+		"",
+		"The Matrix room ID or alias to post a summary to.")
+
+	flag.StringVar(&config.reportPath,
+		"report",
+		"",
+		"The path of a JSON file to write the report to, with the \nquery results needed to retry failed collections.")
+
+	flag.DurationVar(&config.interval,
+		"interval",
+		reporting.DefaultPollingInterval,
+		"How long the daemon waits between collections, like 30m.")
+
+	flag.BoolVar(&config.includeRestricted,
+		"include-restricted",
+		false,
+		"Whether to count the anonymized contributions to private \nGitHub repositories among the other contributions.")
+
+	flag.BoolVar(&config.incremental,
+		"incremental",
+		false,
+		"Whether to reuse the past years of the previous -report, \ncollecting only the current year and any years it's missing.")
+
+	flag.StringVar(&config.listen,
+		"listen",
+		":8080",
+		"The address the serve subcommand listens on.")
+
+	flag.IntVar(&config.retries,
+		"retries",
+		reporting.DefaultRetries,
+		"The number of times to retry a GitHub query failing on a network \nerror, rate limit, or server error, with exponential backoff.")
+
+	flag.BoolVar(&config.noCache,
+		"no-cache",
+		false,
+		"Whether to collect every year again instead of reading \nthe past years from the cache.")
+
+	flag.BoolVar(&config.verbose,
+		"v",
+		false,
+		"Whether to log each user-year as it's collected, \nalong with the other debugging details.")
+
+	flag.BoolVar(&config.quiet,
+		"quiet",
+		false,
+		"Whether to log only warnings and errors, leaving out \nthe progress of the run.")
+
+	flag.BoolVar(&config.noProgress,
+		"no-progress",
+		false,
+		"Whether to leave out the progress bars drawn when \nstandard error is a terminal.")
+
+	flag.StringVar(&config.logFormat,
+		"log-format",
+		"text",
+		"The format of the logs on standard error, text or json.")
+
+	flag.BoolVar(&config.showRateLimit,
+		"show-rate-limit",
+		false,
+		"Whether to log the GitHub rate limit cost and points \nremaining after each query, and the total cost.")
+
+	flag.StringVar(&config.scriptPath,
+		"script",
+		"",
+		"The path of a Starlark script with a report function that \nreceives the user-years and returns custom report fields.")
+
+	flag.StringVar(&config.preCollectHook,
+		"pre-collect",
+		"",
+		"A shell command to run before collecting, which stops \nthe run if it fails.")
+
+	flag.StringVar(&config.postReportHook,
+		"post-report",
+		"",
+		"A shell command to run after reporting, with the report path \nand status in GHCONTRIBUTIONS_REPORT and GHCONTRIBUTIONS_STATUS.")
+
+	flag.StringVar(&config.snapshotsPath,
+		"snapshots",
+		"",
+		"The path of a JSON, SQLite (.db), or bbolt (.bolt) file keeping \na history of run snapshots, used to report changes since the last run.")
+
+	flag.StringVar(&config.atomFeedPath,
+		"atom",
+		"",
+		"The path of an Atom feed file to generate with an \nentry per snapshot (requires -snapshots).")
+
+	flag.StringVar(&config.atomFeedURL,
+		"atom-url",
+		"",
+		"The public URL the Atom feed is served from.")
+
 	// Build a sample credentials list for usage display
 	cred := reporting.Credential{}
 	cred.Username = "your-github-username"
@@ -127,7 +1410,9 @@ func Configure() (config Configuration, err error) {
 		fmt.Println("\tcontributed to, and total other contributions, including")
 		fmt.Println("\tpull requests, merges, and issues.")
 		fmt.Println("\nUsage:")
-		fmt.Printf(" %s [options]\n\n", os.Args[0])
+		printSubcommands()
+		fmt.Println("\nThe collect and serve options, with collect the default:")
+		fmt.Println("")
 		flag.PrintDefaults()
 		fmt.Println("")
 		for range 40 {
@@ -139,12 +1424,28 @@ func Configure() (config Configuration, err error) {
 		fmt.Printf("\n%s\n", credsStr)
 		fmt.Println("\n2. Optionally encrypt the file using PGP/GPG,")
 		fmt.Println("   and use the -encrypted flag if it is encrypted.")
+		fmt.Println("   A file encrypted with a passphrase, like with gpg -c, is decrypted")
+		fmt.Println("   without gpg, prompting for the passphrase or reading -passphrase-file.")
+		fmt.Println("   Or write a token as ${NAME} to read it from the NAME variable.")
 		fmt.Println("\n3. Optionally set the -firstyear and -lastyear flags with four digit years.")
+		fmt.Println("   Without -firstyear, each GitHub user starts from the year their account")
+		fmt.Println("   was created, or of their earliest restricted contribution if earlier.")
+		fmt.Println("   Years before a GitHub account was created are skipped either way.")
 		fmt.Println("\n3. Pass the path to the file as the argument to the -credentials flag.")
 	}
 
 	// Read the command line arguments
-	flag.Parse()
+	err = flag.CommandLine.Parse(args)
+	if err != nil {
+		return config, err
+	}
+	config.sources, err = applyDefaults(flag.CommandLine, config.configPath)
+	if err != nil {
+		return config, err
+	}
+	if config.useGHAuth {
+		config.credentialsFilePath = ghAuthCredentials
+	}
 
 	return config, nil
 }
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"bytes"
+	"os"
+
+	"github.com/christopher-s-jones/ghcontributions/reporting"
+)
+
+// printResults prints the aggregated results in the format to standard output,
+// or writes them to the file at the path when there is one, leaving standard
+// error to the logs
+func printResults(format string, path string, queryResults map[string]reporting.QueryResult,
+	aggregatedResults reporting.AggregatedResults) error {
+
+	encoder, err := reporting.NewEncoder(format)
+	if err != nil {
+		return err
+	}
+	if path == "" {
+		return encoder.Encode(os.Stdout, queryResults, aggregatedResults)
+	}
+
+	var results bytes.Buffer
+	err = encoder.Encode(&results, queryResults, aggregatedResults)
+	if err != nil {
+		return err
+	}
+	return reporting.WriteFileAtomically(path, results.Bytes())
+}
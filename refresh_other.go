@@ -0,0 +1,8 @@
+//go:build !unix
+
+package main
+
+import "os"
+
+// The signals that make the daemon collect at once, as there's no SIGUSR1
+var refreshSignals []os.Signal
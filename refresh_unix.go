@@ -0,0 +1,11 @@
+//go:build unix
+
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// The signals that make the daemon collect at once
+var refreshSignals = []os.Signal{syscall.SIGUSR1}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/christopher-s-jones/ghcontributions/reporting"
+)
+
+// reportCommand runs the report subcommand, printing a report written with
+// -report in any output format, without collecting again
+func reportCommand(args []string) {
+
+	flags := flag.NewFlagSet("report", flag.ExitOnError)
+	output := flags.String("output", "json", "The format of the printed results, json, yaml, csv, \nmarkdown, or html.")
+	outputPath := flags.String("o", "", "The path of a file to write the printed results to, \ninstead of standard output.")
+	flags.Usage = func() {
+		fmt.Println("Print a report written with -report, without collecting again")
+		fmt.Println("\nUsage:")
+		fmt.Printf(" %s report report.json [options]\n\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+
+	// Allow the options before or after the report path
+	flags.Parse(args)
+	reportPath := flags.Arg(0)
+	flags.Parse(flags.Args()[min(1, flags.NArg()):])
+	if reportPath == "" {
+		flags.Usage()
+		log.Fatalf("The report subcommand requires a report file")
+	}
+	if _, err := reporting.NewEncoder(*output); err != nil {
+		flags.Usage()
+		log.Fatalf("The -output format must be one of %s", strings.Join(reporting.EncoderFormats(), ", "))
+	}
+
+	report, err := readReport(reportPath)
+	if err != nil {
+		log.Fatalf("Couldn't load the report: %s", err)
+	}
+	err = printResults(*output, *outputPath, report.QueryResults, report.AggregatedResults)
+	if err != nil {
+		log.Fatalf("Couldn't print the report: %s", err)
+	}
+}
@@ -0,0 +1,106 @@
+package reporting
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// The most commits sampled from each repository
+const activitySampleSize = 100
+
+// The hours of the night, from 10pm up to 6am
+const (
+	nightStartHour = 22
+	nightEndHour   = 6
+)
+
+// A CommitTimeSource samples the times a user authored commits to a repository,
+// for providers that can report when in the day contributions happen
+type CommitTimeSource interface {
+	CommitTimes(ctx context.Context, user string, repository Repository, dateRange DateRange) ([]time.Time, error)
+}
+
+// An HourlyActivity holds the distribution of sampled commits over the hours of the day
+type HourlyActivity struct {
+	// The time zone the hours are in
+	Timezone string `json:"timezone"`
+	// The count of sampled commits
+	Commits int `json:"commits"`
+	// The sampled commits in each hour, starting from midnight
+	ByHour [24]int `json:"byHour"`
+	// The percentage of the sampled commits made between 10pm and 6am
+	Night float64 `json:"night"`
+}
+
+// CommitTimes samples the author times of the user's latest commits to the
+// repository in the range, with the REST API's commit listing
+func (g *GitHub) CommitTimes(ctx context.Context, user string, repository Repository, dateRange DateRange) ([]time.Time, error) {
+
+	owner, name, err := gitHubRepositoryPath(repository)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("author", user)
+	query.Set("since", dateRange.From.Format(time.RFC3339))
+	query.Set("until", dateRange.To.Add(-time.Second).Format(time.RFC3339))
+	query.Set("per_page", fmt.Sprint(activitySampleSize))
+	requestURL := fmt.Sprintf("%s/repos/%s/%s/commits?%s", g.RESTURL, url.PathEscape(owner), url.PathEscape(name), query.Encode())
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Accept", "application/vnd.github+json")
+
+	response, err := g.HTTPClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the commits to %s/%s: %w", owner, name, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("failed to list the commits to %s/%s: github returned %s", owner, name, response.Status)
+	}
+
+	var commits []struct {
+		Commit struct {
+			Author struct {
+				Date time.Time `json:"date"`
+			} `json:"author"`
+		} `json:"commit"`
+	}
+	err = json.NewDecoder(response.Body).Decode(&commits)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the github commits: %w", err)
+	}
+
+	var times = make([]time.Time, 0, len(commits))
+	for _, commit := range commits {
+		times = append(times, commit.Commit.Author.Date)
+	}
+	return times, nil
+}
+
+// NewHourlyActivity counts the commit times in each hour of the day in the location
+func NewHourlyActivity(times []time.Time, location *time.Location) HourlyActivity {
+
+	activity := HourlyActivity{Timezone: location.String(), Commits: len(times)}
+	var night int
+	for _, commitTime := range times {
+		hour := commitTime.In(location).Hour()
+		activity.ByHour[hour]++
+		if hour >= nightStartHour || hour < nightEndHour {
+			night++
+		}
+	}
+	if len(times) > 0 {
+		activity.Night = math.Round(float64(night)/float64(len(times))*1000) / 10
+	}
+	return activity
+}
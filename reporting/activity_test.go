@@ -0,0 +1,69 @@
+package reporting_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test sampling commit times with the GitHub REST API
+func TestGitHubCommitTimes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/repos/team/api/commits", r.URL.Path)
+		assert.Equal(t, "user1", r.URL.Query().Get("author"))
+		assert.Equal(t, "2023-01-01T00:00:00Z", r.URL.Query().Get("since"))
+		assert.Equal(t, "2023-12-31T23:59:59Z", r.URL.Query().Get("until"))
+		assert.Equal(t, "100", r.URL.Query().Get("per_page"))
+		fmt.Fprint(w, `[
+			{"commit": {"author": {"date": "2023-05-01T23:15:00Z"}}},
+			{"commit": {"author": {"date": "2023-05-02T09:30:00Z"}}}
+		]`)
+	}))
+	defer server.Close()
+
+	gitHub, err := rpt.NewGitHub(&pagedGraphQLClient{})
+	require.NoError(t, err)
+	gitHub.RESTURL = server.URL
+
+	times, err := gitHub.CommitTimes(context.Background(), "user1",
+		rpt.Repository{Name: "api", URL: "https://github.com/team/api"}, rpt.YearRange(2023, 2023))
+	require.NoError(t, err)
+	assert.Equal(t, []time.Time{
+		time.Date(2023, time.May, 1, 23, 15, 0, 0, time.UTC),
+		time.Date(2023, time.May, 2, 9, 30, 0, 0, time.UTC),
+	}, times)
+}
+
+// Test counting commit times by hour of the day
+func TestNewHourlyActivity(t *testing.T) {
+	times := []time.Time{
+		time.Date(2023, time.May, 1, 23, 15, 0, 0, time.UTC),
+		time.Date(2023, time.May, 2, 4, 0, 0, 0, time.UTC),
+		time.Date(2023, time.May, 2, 15, 30, 0, 0, time.UTC),
+		time.Date(2023, time.May, 3, 15, 45, 0, 0, time.UTC),
+	}
+
+	activity := rpt.NewHourlyActivity(times, time.UTC)
+	assert.Equal(t, "UTC", activity.Timezone)
+	assert.Equal(t, 4, activity.Commits)
+	assert.Equal(t, 1, activity.ByHour[23])
+	assert.Equal(t, 2, activity.ByHour[15])
+	assert.Equal(t, 50.0, activity.Night)
+
+	// Ensure the hours follow the time zone
+	denver, err := time.LoadLocation("America/Denver")
+	require.NoError(t, err)
+	activity = rpt.NewHourlyActivity(times, denver)
+	assert.Equal(t, 1, activity.ByHour[17])
+	assert.Equal(t, 2, activity.ByHour[9])
+	assert.Equal(t, 25.0, activity.Night)
+
+	assert.Zero(t, rpt.NewHourlyActivity(nil, time.UTC).Night)
+}
@@ -0,0 +1,159 @@
+package reporting
+
+import (
+	"math"
+	"slices"
+	"strconv"
+)
+
+// The smallest yearly change, relative to the mean, that counts as a trend
+const trendThreshold = 0.05
+
+// The trend directions
+const (
+	TrendUp   = "up"
+	TrendDown = "down"
+	TrendFlat = "flat"
+)
+
+// An Analysis describes how each headline metric changed from year to year
+type Analysis struct {
+	TotalCommitContributions MetricTrend `json:"totalCommitContributions"`
+	TotalRepositories        MetricTrend `json:"totalRepositories"`
+	TotalOtherContributions  MetricTrend `json:"totalOtherContributions"`
+}
+
+// A MetricTrend holds a metric's value each year, its best and worst years,
+// and the overall direction of the values
+type MetricTrend struct {
+	ByYear    []YearValue `json:"byYear"`
+	BestYear  int         `json:"bestYear"`
+	WorstYear int         `json:"worstYear"`
+	Trend     string      `json:"trend"`
+	// The percentile of the latest year's value among the earlier years,
+	// left out when there are no earlier years
+	Percentile *int `json:"percentile,omitempty"`
+}
+
+// A YearValue holds a metric's value in a year, and its change from the year
+// before. The growth is a percentage, left out when the year before was zero.
+type YearValue struct {
+	Year   int      `json:"year"`
+	Value  int      `json:"value"`
+	Change *int     `json:"change,omitempty"`
+	Growth *float64 `json:"growth,omitempty"`
+}
+
+// Analyze computes the year over year analysis of the query results, combining
+// all users. Years missing between the first and last year count as zero.
+// Returns nil when there are no results.
+func Analyze(queryResults map[string]QueryResult) *Analysis {
+	return (&Reporter{}).Analyze(queryResults)
+}
+
+// Analyze computes the year over year analysis of the query results like the
+// Analyze function, with the totals counted like the Reporter's byYear
+func (r *Reporter) Analyze(queryResults map[string]QueryResult) *Analysis {
+
+	byYear := make(map[int]Totals)
+	for year, yearQueryResults := range groupQueryResults(queryResults, func(user string, year int) string {
+		return strconv.Itoa(year)
+	}) {
+		value, _ := strconv.Atoi(year)
+		byYear[value] = r.totals(yearQueryResults)
+	}
+	if len(byYear) == 0 {
+		return nil
+	}
+
+	var years []int
+	for year := range byYear {
+		years = append(years, year)
+	}
+	firstYear, lastYear := slices.Min(years), slices.Max(years)
+
+	trend := func(metric func(Totals) int) MetricTrend {
+		var values []int
+		for year := firstYear; year <= lastYear; year++ {
+			values = append(values, metric(byYear[year]))
+		}
+		return newMetricTrend(firstYear, values)
+	}
+
+	return &Analysis{
+		TotalCommitContributions: trend(func(t Totals) int { return t.TotalCommitContributions }),
+		TotalRepositories:        trend(func(t Totals) int { return t.TotalRepositories }),
+		TotalOtherContributions:  trend(func(t Totals) int { return t.TotalOtherContributions }),
+	}
+}
+
+// newMetricTrend analyzes a metric's values for consecutive years from the first year
+func newMetricTrend(firstYear int, values []int) MetricTrend {
+
+	metricTrend := MetricTrend{BestYear: firstYear, WorstYear: firstYear, Trend: TrendFlat}
+	for index, value := range values {
+		year := firstYear + index
+		yearValue := YearValue{Year: year, Value: value}
+		if index > 0 {
+			previous := values[index-1]
+			change := value - previous
+			yearValue.Change = &change
+			if previous != 0 {
+				growth := math.Round(float64(change)/float64(previous)*1000) / 10
+				yearValue.Growth = &growth
+			}
+		}
+		metricTrend.ByYear = append(metricTrend.ByYear, yearValue)
+
+		// Ties go to the earlier year
+		if value > values[metricTrend.BestYear-firstYear] {
+			metricTrend.BestYear = year
+		}
+		if value < values[metricTrend.WorstYear-firstYear] {
+			metricTrend.WorstYear = year
+		}
+	}
+
+	// The trend is the sign of the least squares slope, when the slope is
+	// large enough relative to the mean
+	if len(values) < 2 {
+		return metricTrend
+	}
+	percentile := percentileRank(values[len(values)-1], values[:len(values)-1])
+	metricTrend.Percentile = &percentile
+	var count = float64(len(values))
+	var meanX, meanY float64
+	for index, value := range values {
+		meanX += float64(index) / count
+		meanY += float64(value) / count
+	}
+	var covariance, variance float64
+	for index, value := range values {
+		covariance += (float64(index) - meanX) * (float64(value) - meanY)
+		variance += (float64(index) - meanX) * (float64(index) - meanX)
+	}
+	if meanY == 0 {
+		return metricTrend
+	}
+	switch slope := covariance / variance / meanY; {
+	case slope > trendThreshold:
+		metricTrend.Trend = TrendUp
+	case slope < -trendThreshold:
+		metricTrend.Trend = TrendDown
+	}
+	return metricTrend
+}
+
+// percentileRank returns the percentage of the values below the value, counting
+// equal values as half, rounded to a whole percentile
+func percentileRank(value int, values []int) int {
+	var rank float64
+	for _, other := range values {
+		if other < value {
+			rank++
+		} else if other == value {
+			rank += 0.5
+		}
+	}
+	return int(math.Round(rank / float64(len(values)) * 100))
+}
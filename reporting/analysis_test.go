@@ -0,0 +1,139 @@
+package reporting_test
+
+import (
+	"testing"
+	"time"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+)
+
+// commitsByYear builds query results with the given commit counts each year
+func commitsByYear(user string, commits map[int]int) map[string]rpt.QueryResult {
+	var contributions []rpt.Contribution
+	for year, count := range commits {
+		contributions = append(contributions, rpt.Contribution{
+			Kind:       rpt.ContributionCommit,
+			Repository: rpt.Repository{Name: "app"},
+			Date:       time.Date(year, time.June, 1, 0, 0, 0, 0, time.UTC),
+			Count:      count,
+		})
+	}
+	return rpt.QueryResultsFromContributions(user, contributions)
+}
+
+// Test the Analyze function
+func TestAnalyze(t *testing.T) {
+	assert.Nil(t, rpt.Analyze(map[string]rpt.QueryResult{}))
+
+	// Two users, with a gap year counted as zero
+	queryResults := commitsByYear("user1", map[int]int{2020: 10, 2021: 20, 2023: 40})
+	rpt.MergeQueryResults(queryResults, commitsByYear("user2", map[int]int{2023: 10}))
+
+	analysis := rpt.Analyze(queryResults)
+	commits := analysis.TotalCommitContributions
+	assert.Len(t, commits.ByYear, 4)
+
+	assert.Equal(t, 2020, commits.ByYear[0].Year)
+	assert.Nil(t, commits.ByYear[0].Change)
+	assert.Nil(t, commits.ByYear[0].Growth)
+
+	assert.Equal(t, 10, *commits.ByYear[1].Change)
+	assert.Equal(t, 100.0, *commits.ByYear[1].Growth)
+
+	// Ensure growth from a zero year is left out
+	assert.Equal(t, 0, commits.ByYear[2].Value)
+	assert.Equal(t, -100.0, *commits.ByYear[2].Growth)
+	assert.Equal(t, 50, commits.ByYear[3].Value)
+	assert.Equal(t, 50, *commits.ByYear[3].Change)
+	assert.Nil(t, commits.ByYear[3].Growth)
+
+	assert.Equal(t, 2023, commits.BestYear)
+	assert.Equal(t, 2022, commits.WorstYear)
+	assert.Equal(t, rpt.TrendUp, commits.Trend)
+
+	// Ensure ties go to the earlier year
+	repositories := analysis.TotalRepositories
+	assert.Equal(t, 2020, repositories.BestYear)
+	assert.Equal(t, 2022, repositories.WorstYear)
+}
+
+// Test the trend directions
+func TestAnalyzeTrend(t *testing.T) {
+	tests := []struct {
+		name    string
+		commits map[int]int
+		trend   string
+	}{
+		{"single year", map[int]int{2023: 10}, rpt.TrendFlat},
+		{"growing", map[int]int{2021: 10, 2022: 15, 2023: 20}, rpt.TrendUp},
+		{"shrinking", map[int]int{2021: 20, 2022: 15, 2023: 10}, rpt.TrendDown},
+		{"steady", map[int]int{2021: 100, 2022: 101, 2023: 100}, rpt.TrendFlat},
+		{"no commits", map[int]int{2021: 0, 2022: 0}, rpt.TrendFlat},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			analysis := rpt.Analyze(commitsByYear("user1", test.commits))
+			assert.Equal(t, test.trend, analysis.TotalCommitContributions.Trend)
+		})
+	}
+}
+
+// Test the percentile of the latest year among the earlier years
+func TestAnalyzePercentile(t *testing.T) {
+	tests := []struct {
+		name       string
+		commits    map[int]int
+		percentile *int
+	}{
+		{"single year", map[int]int{2023: 10}, nil},
+		{"best year", map[int]int{2020: 5, 2021: 10, 2022: 15, 2023: 20}, intPointer(100)},
+		{"worst year", map[int]int{2021: 10, 2022: 15, 2023: 5}, intPointer(0)},
+		{"ties count as half", map[int]int{2020: 5, 2021: 10, 2022: 20, 2023: 10}, intPointer(50)},
+		{"four of five", map[int]int{2019: 1, 2020: 2, 2021: 3, 2022: 50, 2023: 4, 2024: 20}, intPointer(80)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			analysis := rpt.Analyze(commitsByYear("user1", test.commits))
+			assert.Equal(t, test.percentile, analysis.TotalCommitContributions.Percentile)
+		})
+	}
+
+	// Ensure the summary places the latest year
+	current := rpt.AggregatedResults{Analysis: rpt.Analyze(commitsByYear("user1", map[int]int{2022: 5, 2023: 20}))}
+	assert.Contains(t, rpt.Summarize(current, nil).Text(),
+		"2023 against earlier years: commits in the 100th percentile, repositories in the 50th, other contributions in the 50th")
+}
+
+// intPointer returns a pointer to the value
+func intPointer(value int) *int {
+	return &value
+}
+
+// Test counting the restricted contributions the same way in every section
+func TestReporterIncludeRestricted(t *testing.T) {
+	date := time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)
+	queryResults := rpt.QueryResultsFromContributions("user1", []rpt.Contribution{
+		{Kind: rpt.ContributionCommit, Repository: rpt.Repository{Name: "app"}, Date: date, Count: 10},
+		{Kind: rpt.ContributionIssue, Repository: rpt.Repository{Name: "app"}, Date: date, Count: 2},
+		{Kind: rpt.ContributionRestricted, Date: date, Count: 3},
+	})
+	year := rpt.YearRange(2023, 2023)
+	now := time.Date(2023, time.December, 31, 0, 0, 0, 0, time.UTC)
+	goal := rpt.Goal{Metric: "other", Target: 10, Period: "year"}
+
+	for _, test := range []struct {
+		includeRestricted bool
+		other             int
+	}{{false, 2}, {true, 5}} {
+		reporter := &rpt.Reporter{IncludeRestricted: test.includeRestricted}
+		assert.Equal(t, test.other, reporter.Analyze(queryResults).TotalOtherContributions.ByYear[0].Value)
+		assert.Equal(t, test.other, reporter.GoalProgress(goal, queryResults, now).Actual)
+		assert.Equal(t, test.other, reporter.ComparePeriods(year, queryResults, year, queryResults).A.TotalOtherContributions)
+		entries := reporter.Resume([]rpt.EmploymentPeriod{{Label: "Acme", Range: year}},
+			[]map[string]rpt.QueryResult{queryResults})
+		assert.Equal(t, test.other, entries[0].TotalOtherContributions)
+	}
+}
@@ -0,0 +1,59 @@
+package reporting
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+)
+
+// An Artifact is a rendered report file ready to be attached or uploaded
+type Artifact struct {
+	// The file name, like report.json
+	Name string
+	// The MIME type of the content
+	ContentType string
+	// The rendered report
+	Content []byte
+}
+
+// The file extension and MIME type of the artifacts of each output format
+var artifactTypes = map[string]struct{ extension, contentType string }{
+	"json":     {"json", "application/json"},
+	"yaml":     {"yaml", "application/yaml"},
+	"csv":      {"csv", "text/csv"},
+	"markdown": {"md", "text/markdown"},
+	"html":     {"html", "text/html"},
+}
+
+// ReportArtifacts renders the results with the Encoder of the output format, named
+// like report.yaml with the format's extension, or as report.json without a format
+func ReportArtifacts(queryResults map[string]QueryResult, aggregatedResults AggregatedResults,
+	format string) ([]Artifact, error) {
+
+	if format == "" {
+		format = "json"
+	}
+	encoder, err := NewEncoder(format)
+	if err != nil {
+		return nil, err
+	}
+	artifactType, ok := artifactTypes[format]
+	if !ok {
+		return nil, fmt.Errorf("the output format %q has no artifact type", format)
+	}
+
+	var report bytes.Buffer
+	err = encoder.Encode(&report, queryResults, aggregatedResults)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render the report artifact: %w", err)
+	}
+
+	return []Artifact{
+		{Name: "report." + artifactType.extension, ContentType: artifactType.contentType, Content: report.Bytes()},
+	}, nil
+}
+
+// artifactTimestamp formats the time of the results for use in object names
+func artifactTimestamp(aggregatedResults AggregatedResults) string {
+	return time.Unix(int64(aggregatedResults.Timestamp), 0).UTC().Format("20060102T150405Z")
+}
@@ -0,0 +1,107 @@
+package reporting
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+)
+
+// The maximum number of snapshot entries included in the Atom feed
+const MaxAtomFeedEntries = 50
+
+// atomFeed is the root element of an Atom feed
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  atomAuthor  `xml:"author"`
+	Link    *atomLink   `xml:"link,omitempty"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+// atomAuthor names the author of the feed
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+// atomLink references the feed's own location
+type atomLink struct {
+	Rel  string `xml:"rel,attr"`
+	Href string `xml:"href,attr"`
+}
+
+// atomEntry is a single snapshot in the feed
+type atomEntry struct {
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Content atomContent `xml:"content"`
+}
+
+// atomContent holds the plain text summary of an entry
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// WriteAtomFeed writes an Atom feed with an entry per snapshot, newest first,
+// summarizing the change in each metric since the snapshot before it.
+// The feedURL is the public location of the feed, used for its ID and self link.
+func WriteAtomFeed(w io.Writer, snapshots []Snapshot, feedURL string) error {
+
+	feed := atomFeed{
+		ID:      "urn:ghcontributions:snapshots",
+		Title:   "GitHub contribution snapshots",
+		Updated: time.Unix(0, 0).UTC().Format(time.RFC3339),
+		Author:  atomAuthor{Name: "ghcontributions"},
+	}
+	if feedURL != "" {
+		feed.ID = feedURL
+		feed.Link = &atomLink{Rel: "self", Href: feedURL}
+	}
+
+	for index, snapshot := range snapshots {
+		var previous *AggregatedResults
+		if index > 0 {
+			previous = &snapshots[index-1].Results
+		}
+		summary := Summarize(snapshot.Results, previous)
+		updated := time.Unix(int64(snapshot.Timestamp), 0).UTC()
+
+		title := fmt.Sprintf("%d commits across %d repositories",
+			summary.Totals.TotalCommitContributions, summary.Totals.TotalRepositories)
+		if summary.Delta != nil {
+			title = fmt.Sprintf("%s commits, %s repositories, %s other contributions",
+				formatDelta(summary.Delta.TotalCommitContributions),
+				formatDelta(summary.Delta.TotalRepositories),
+				formatDelta(summary.Delta.TotalOtherContributions))
+		}
+
+		feed.Entries = append(feed.Entries, atomEntry{
+			ID:      fmt.Sprintf("urn:ghcontributions:snapshot:%d", snapshot.Timestamp),
+			Title:   title,
+			Updated: updated.Format(time.RFC3339),
+			Content: atomContent{Type: "text", Body: summary.Text()},
+		})
+	}
+
+	// List the newest entries first
+	slices.Reverse(feed.Entries)
+	if len(feed.Entries) > MaxAtomFeedEntries {
+		feed.Entries = feed.Entries[:MaxAtomFeedEntries]
+	}
+	if len(feed.Entries) > 0 {
+		feed.Updated = feed.Entries[0].Updated
+	}
+
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	return encoder.Encode(feed)
+}
@@ -0,0 +1,44 @@
+package reporting_test
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the WriteAtomFeed function
+func TestWriteAtomFeed(t *testing.T) {
+	snapshots := []rpt.Snapshot{
+		{Timestamp: 1700000000, Results: rpt.AggregatedResults{TotalCommitContributions: 10, TotalRepositories: 2}},
+		{Timestamp: 1700086400, Results: rpt.AggregatedResults{TotalCommitContributions: 15, TotalRepositories: 2}},
+	}
+
+	var output strings.Builder
+	err := rpt.WriteAtomFeed(&output, snapshots, "https://example.com/feed.xml")
+	assert.NoError(t, err)
+
+	var feed struct {
+		ID      string `xml:"id"`
+		Updated string `xml:"updated"`
+		Entries []struct {
+			ID      string `xml:"id"`
+			Title   string `xml:"title"`
+			Content string `xml:"content"`
+		} `xml:"entry"`
+	}
+	err = xml.Unmarshal([]byte(output.String()), &feed)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "https://example.com/feed.xml", feed.ID)
+	assert.Equal(t, "2023-11-15T22:13:20Z", feed.Updated)
+	assert.Len(t, feed.Entries, 2)
+
+	// Ensure the newest entry is first and describes the changes
+	assert.Equal(t, "urn:ghcontributions:snapshot:1700086400", feed.Entries[0].ID)
+	assert.Equal(t, "+5 commits, ±0 repositories, ±0 other contributions", feed.Entries[0].Title)
+	assert.Equal(t, "10 commits across 2 repositories", feed.Entries[1].Title)
+	assert.Contains(t, feed.Entries[0].Content, "Commits: 15 (+5)")
+}
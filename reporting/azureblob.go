@@ -0,0 +1,281 @@
+package reporting
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// The storage service version sent with Azure Blob requests
+const azureStorageVersion = "2021-08-06"
+
+// The resource that managed identity tokens are requested for
+const azureStorageResource = "https://storage.azure.com/"
+
+// The Azure Instance Metadata Service token endpoint
+const azureIMDSTokenEndpoint = "http://169.254.169.254/metadata/identity/oauth2/token"
+
+// An AzureBlob publisher uploads the report artifacts of each run to an
+// Azure Blob Storage container
+type AzureBlob struct {
+	// The storage account name
+	AccountName string
+	// The storage account key, when authenticating with a connection string
+	AccountKey []byte
+	// The managed identity token source, when not using an account key
+	TokenSource oauth2.TokenSource
+	// The blob service endpoint, like https://account.blob.core.windows.net
+	Endpoint string
+	// The container name
+	Container string
+	// The blob name prefix, like reports/
+	Prefix string
+	// The HTTP client used to send requests
+	Client *http.Client
+	// The output format of the report artifact, like yaml, or json if blank
+	Format string
+}
+
+// Constructs a new AzureBlob object authenticated with a storage account connection string
+// The connectionString holds the AccountName, AccountKey, and endpoint settings
+// The container and prefix locate the uploaded blobs
+func NewAzureBlob(connectionString string, container string, prefix string) (azureBlob *AzureBlob, err error) {
+
+	if container == "" {
+		err = fmt.Errorf("the azure blob container cannot be blank")
+		return nil, err
+	}
+
+	// Parse the semicolon separated settings, like AccountName=x;AccountKey=y
+	var settings = make(map[string]string)
+	for _, setting := range strings.Split(connectionString, ";") {
+		key, value, found := strings.Cut(setting, "=")
+		if found {
+			settings[key] = value
+		}
+	}
+	if settings["AccountName"] == "" || settings["AccountKey"] == "" {
+		err = fmt.Errorf("the azure connection string needs an AccountName and AccountKey")
+		return nil, err
+	}
+
+	accountKey, err := base64.StdEncoding.DecodeString(settings["AccountKey"])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the azure account key: %w", err)
+	}
+
+	endpoint := settings["BlobEndpoint"]
+	if endpoint == "" {
+		protocol := settings["DefaultEndpointsProtocol"]
+		if protocol == "" {
+			protocol = "https"
+		}
+		suffix := settings["EndpointSuffix"]
+		if suffix == "" {
+			suffix = "core.windows.net"
+		}
+		endpoint = protocol + "://" + settings["AccountName"] + ".blob." + suffix
+	}
+
+	return &AzureBlob{
+		AccountName: settings["AccountName"],
+		AccountKey:  accountKey,
+		Endpoint:    strings.TrimSuffix(endpoint, "/"),
+		Container:   container,
+		Prefix:      prefix,
+		Client:      http.DefaultClient,
+	}, err
+}
+
+// Constructs a new AzureBlob object authenticated with a managed identity
+// The accountName is the storage account name
+// The container and prefix locate the uploaded blobs
+// The clientID selects a user-assigned identity, or is blank for the system-assigned identity
+func NewAzureBlobWithManagedIdentity(accountName string, container string, prefix string,
+	clientID string) (azureBlob *AzureBlob, err error) {
+
+	if accountName == "" || container == "" {
+		err = fmt.Errorf("the azure storage account and container cannot be blank")
+		return nil, err
+	}
+
+	return &AzureBlob{
+		AccountName: accountName,
+		TokenSource: oauth2.ReuseTokenSource(nil, &managedIdentityTokenSource{clientID: clientID}),
+		Endpoint:    "https://" + accountName + ".blob.core.windows.net",
+		Container:   container,
+		Prefix:      prefix,
+		Client:      http.DefaultClient,
+	}, err
+}
+
+// Export uploads each report artifact as a block blob named with the time of the run,
+// like reports/20241101T120000Z/report.json
+func (a *AzureBlob) Export(queryResults map[string]QueryResult, aggregatedResults AggregatedResults) error {
+
+	artifacts, err := ReportArtifacts(queryResults, aggregatedResults, a.Format)
+	if err != nil {
+		return err
+	}
+
+	for _, artifact := range artifacts {
+		blobName := a.Prefix + artifactTimestamp(aggregatedResults) + "/" + artifact.Name
+		err = a.put(blobName, artifact)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// put uploads a single artifact with the Put Blob operation
+func (a *AzureBlob) put(blobName string, artifact Artifact) error {
+
+	endpoint := a.Endpoint + "/" + a.Container + "/" + blobName
+	request, err := http.NewRequest(http.MethodPut, endpoint, bytes.NewReader(artifact.Content))
+	if err != nil {
+		return fmt.Errorf("failed to build the azure blob request: %w", err)
+	}
+	request.Header.Set("Content-Type", artifact.ContentType)
+	request.Header.Set("X-Ms-Blob-Type", "BlockBlob")
+	request.Header.Set("X-Ms-Date", time.Now().UTC().Format(http.TimeFormat))
+	request.Header.Set("X-Ms-Version", azureStorageVersion)
+
+	if a.TokenSource != nil {
+		token, err := a.TokenSource.Token()
+		if err != nil {
+			return fmt.Errorf("failed to get a managed identity token: %w", err)
+		}
+		request.Header.Set("Authorization", "Bearer "+token.AccessToken)
+	} else {
+		a.signSharedKey(request, len(artifact.Content))
+	}
+
+	response, err := a.Client.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to upload %s to azure: %w", blobName, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode/100 != 2 {
+		return fmt.Errorf("failed to upload %s to azure: azure returned %s", blobName, response.Status)
+	}
+	return nil
+}
+
+// signSharedKey adds a Shared Key authorization header to the request
+func (a *AzureBlob) signSharedKey(request *http.Request, contentLength int) {
+
+	length := ""
+	if contentLength > 0 {
+		length = strconv.Itoa(contentLength)
+	}
+
+	// Canonicalize the x-ms-* headers
+	var msHeaders []string
+	for name, values := range request.Header {
+		name = strings.ToLower(name)
+		if strings.HasPrefix(name, "x-ms-") {
+			msHeaders = append(msHeaders, name+":"+strings.Join(values, ","))
+		}
+	}
+	slices.Sort(msHeaders)
+
+	// Canonicalize the resource, including any query parameters
+	resource := "/" + a.AccountName + request.URL.EscapedPath()
+	query := request.URL.Query()
+	for _, key := range slices.Sorted(maps.Keys(query)) {
+		values := slices.Clone(query[key])
+		slices.Sort(values)
+		resource += "\n" + strings.ToLower(key) + ":" + strings.Join(values, ",")
+	}
+
+	stringToSign := strings.Join([]string{
+		request.Method,
+		request.Header.Get("Content-Encoding"),
+		request.Header.Get("Content-Language"),
+		length,
+		request.Header.Get("Content-MD5"),
+		request.Header.Get("Content-Type"),
+		"", // Date, replaced by x-ms-date
+		request.Header.Get("If-Modified-Since"),
+		request.Header.Get("If-Match"),
+		request.Header.Get("If-None-Match"),
+		request.Header.Get("If-Unmodified-Since"),
+		request.Header.Get("Range"),
+		strings.Join(msHeaders, "\n"),
+		resource,
+	}, "\n")
+
+	signature := base64.StdEncoding.EncodeToString(hmacSHA256(a.AccountKey, stringToSign))
+	request.Header.Set("Authorization", "SharedKey "+a.AccountName+":"+signature)
+}
+
+// managedIdentityTokenSource fetches storage tokens for an Azure managed identity,
+// from the App Service identity endpoint when present, or otherwise from IMDS
+type managedIdentityTokenSource struct {
+	clientID string
+}
+
+// Token requests a new access token for the storage resource
+func (m *managedIdentityTokenSource) Token() (*oauth2.Token, error) {
+
+	query := url.Values{}
+	query.Set("resource", azureStorageResource)
+	if m.clientID != "" {
+		query.Set("client_id", m.clientID)
+	}
+
+	var request *http.Request
+	var err error
+	if identityEndpoint := os.Getenv("IDENTITY_ENDPOINT"); identityEndpoint != "" {
+		query.Set("api-version", "2019-08-01")
+		request, err = http.NewRequest(http.MethodGet, identityEndpoint+"?"+query.Encode(), nil)
+		if err == nil {
+			request.Header.Set("X-Identity-Header", os.Getenv("IDENTITY_HEADER"))
+		}
+	} else {
+		query.Set("api-version", "2018-02-01")
+		request, err = http.NewRequest(http.MethodGet, azureIMDSTokenEndpoint+"?"+query.Encode(), nil)
+		if err == nil {
+			request.Header.Set("Metadata", "true")
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("the identity endpoint returned %s", response.Status)
+	}
+
+	var tokenResponse struct {
+		AccessToken string `json:"access_token"`
+		ExpiresOn   string `json:"expires_on"`
+	}
+	err = json.NewDecoder(response.Body).Decode(&tokenResponse)
+	if err != nil {
+		return nil, err
+	}
+	expiresOn, _ := strconv.ParseInt(tokenResponse.ExpiresOn, 10, 64)
+	return &oauth2.Token{
+		AccessToken: tokenResponse.AccessToken,
+		Expiry:      time.Unix(expiresOn, 0),
+	}, nil
+}
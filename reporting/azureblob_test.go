@@ -0,0 +1,77 @@
+package reporting_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the NewAzureBlob constructor
+func TestNewAzureBlob(t *testing.T) {
+	_, err := rpt.NewAzureBlob("AccountName=account;AccountKey=a2V5", "", "")
+	assert.Error(t, err)
+
+	_, err = rpt.NewAzureBlob("AccountName=account", "reports", "")
+	assert.Error(t, err)
+
+	azureBlob, err := rpt.NewAzureBlob(
+		"DefaultEndpointsProtocol=https;AccountName=account;AccountKey=a2V5;EndpointSuffix=core.windows.net",
+		"reports", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://account.blob.core.windows.net", azureBlob.Endpoint)
+	assert.Equal(t, []byte("key"), azureBlob.AccountKey)
+}
+
+// Test the AzureBlob Export method with a shared key
+func TestAzureBlobExport(t *testing.T) {
+	var request *http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request = r
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	azureBlob, err := rpt.NewAzureBlob("AccountName=account;AccountKey=a2V5;BlobEndpoint="+server.URL,
+		"reports", "ghcontributions/")
+	assert.NoError(t, err)
+
+	err = azureBlob.Export(nil, rpt.AggregatedResults{Timestamp: 1730462400})
+	assert.NoError(t, err)
+
+	assert.Equal(t, http.MethodPut, request.Method)
+	assert.Equal(t, "/reports/ghcontributions/20241101T120000Z/report.json", request.URL.Path)
+	assert.Equal(t, "BlockBlob", request.Header.Get("X-Ms-Blob-Type"))
+	assert.NotEmpty(t, request.Header.Get("X-Ms-Date"))
+	assert.True(t, strings.HasPrefix(request.Header.Get("Authorization"), "SharedKey account:"))
+}
+
+// Test the AzureBlob Export method with a managed identity
+func TestAzureBlobExportWithManagedIdentity(t *testing.T) {
+	identity := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret-header", r.Header.Get("X-Identity-Header"))
+		assert.Equal(t, "https://storage.azure.com/", r.URL.Query().Get("resource"))
+		w.Write([]byte(`{"access_token": "token", "expires_on": "4102444800"}`))
+	}))
+	defer identity.Close()
+	t.Setenv("IDENTITY_ENDPOINT", identity.URL)
+	t.Setenv("IDENTITY_HEADER", "secret-header")
+
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	azureBlob, err := rpt.NewAzureBlobWithManagedIdentity("account", "reports", "", "")
+	assert.NoError(t, err)
+	azureBlob.Endpoint = server.URL
+
+	err = azureBlob.Export(nil, rpt.AggregatedResults{})
+	assert.NoError(t, err)
+	assert.Equal(t, "Bearer token", authorization)
+}
@@ -0,0 +1,276 @@
+package reporting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The Azure DevOps REST API version
+const azureDevOpsAPIVersion = "7.1"
+
+// The most work items fetched in a single request
+const azureDevOpsWorkItemBatchSize = 200
+
+// An AzureDevOps provider collects a user's commits, pull requests, pull request
+// reviews, and authored work items across the repositories of an organization.
+// The user is the email address commits are authored with.
+type AzureDevOps struct {
+	// The organization URL, like https://dev.azure.com/your-organization
+	OrganizationURL string
+	// A personal access token with the Code (Read) and Work Items (Read) scopes
+	Token string
+	// The HTTP client used for the API requests
+	Client *http.Client
+}
+
+// An azureDevOpsRepository holds the fields of an Azure DevOps Git repository
+type azureDevOpsRepository struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	WebURL     string `json:"webUrl"`
+	IsDisabled bool   `json:"isDisabled"`
+	Project    struct {
+		Name string `json:"name"`
+	} `json:"project"`
+}
+
+// Constructs a new AzureDevOps object
+// The organizationURL is the organization, like https://dev.azure.com/your-organization
+// The token is a personal access token
+func NewAzureDevOps(organizationURL string, token string) (azureDevOps *AzureDevOps, err error) {
+
+	if organizationURL == "" {
+		err = fmt.Errorf("the azure devops organization url cannot be blank")
+		return nil, err
+	}
+	if token == "" {
+		err = fmt.Errorf("the azure devops token cannot be blank")
+		return nil, err
+	}
+
+	return &AzureDevOps{
+		OrganizationURL: strings.TrimSuffix(organizationURL, "/"),
+		Token:           token,
+		Client:          http.DefaultClient,
+	}, err
+}
+
+// Collect counts the commits authored with the user's email address, and the
+// pull requests and work items of the token's identity: created pull requests,
+// pull requests it was a reviewer on, and created work items
+func (a *AzureDevOps) Collect(ctx context.Context, user string, dateRange DateRange) ([]Contribution, error) {
+
+	var contributions []Contribution
+
+	slog.Debug("Fetching azure devops repositories", "user", user)
+
+	// The token's identity is used to match pull requests and work items
+	var connection struct {
+		AuthenticatedUser struct {
+			ID string `json:"id"`
+		} `json:"authenticatedUser"`
+	}
+	err := a.request(ctx, http.MethodGet, a.OrganizationURL+"/_apis/connectionData", nil, &connection)
+	if err != nil {
+		return contributions, err
+	}
+	userID := connection.AuthenticatedUser.ID
+
+	var repositories struct {
+		Value []azureDevOpsRepository `json:"value"`
+	}
+	err = a.request(ctx, http.MethodGet, a.OrganizationURL+"/_apis/git/repositories", nil, &repositories)
+	if err != nil {
+		return contributions, err
+	}
+
+	for _, azureRepository := range repositories.Value {
+		repository := Repository{
+			Name:       azureRepository.Project.Name + "/" + azureRepository.Name,
+			URL:        azureRepository.WebURL,
+			IsDisabled: azureRepository.IsDisabled,
+		}
+		repositoryPath := a.OrganizationURL + "/" + url.PathEscape(azureRepository.Project.Name) +
+			"/_apis/git/repositories/" + azureRepository.ID
+
+		// Commits are filtered by author and date on the server
+		query := url.Values{}
+		query.Set("searchCriteria.author", user)
+		query.Set("searchCriteria.fromDate", dateRange.From.Format(time.RFC3339))
+		query.Set("searchCriteria.toDate", dateRange.To.Add(-time.Second).Format(time.RFC3339))
+		err = getAzureDevOpsPages(ctx, a, repositoryPath+"/commits", query,
+			func(commit struct {
+				Author struct {
+					Date time.Time `json:"date"`
+				} `json:"author"`
+			}) {
+				contributions = append(contributions, Contribution{
+					Kind: ContributionCommit, Repository: repository, Date: commit.Author.Date, Count: 1,
+				})
+			})
+		if err != nil {
+			return contributions, err
+		}
+
+		roles := map[string]ContributionKind{
+			"creatorId":  ContributionPullRequest,
+			"reviewerId": ContributionPullRequestReview,
+		}
+		for role, kind := range roles {
+			query := url.Values{}
+			query.Set("searchCriteria."+role, userID)
+			query.Set("searchCriteria.status", "all")
+			err = getAzureDevOpsPages(ctx, a, repositoryPath+"/pullrequests", query,
+				func(pullRequest struct {
+					CreationDate time.Time `json:"creationDate"`
+				}) {
+					if dateRange.Contains(pullRequest.CreationDate) {
+						contributions = append(contributions, Contribution{
+							Kind: kind, Repository: repository, Date: pullRequest.CreationDate, Count: 1,
+						})
+					}
+				})
+			if err != nil {
+				return contributions, err
+			}
+		}
+	}
+
+	// Work items are counted as issues in their project
+	err = a.collectWorkItems(ctx, dateRange, func(project string, createdDate time.Time) {
+		projectURL := a.OrganizationURL + "/" + url.PathEscape(project) + "/_workitems"
+		contributions = append(contributions, Contribution{
+			Kind: ContributionIssue, Repository: Repository{Name: project, URL: projectURL}, Date: createdDate, Count: 1,
+		})
+	})
+	if err != nil {
+		return contributions, err
+	}
+	return contributions, nil
+}
+
+// collectWorkItems visits the project and creation date of each work item the
+// token's identity created in the range
+func (a *AzureDevOps) collectWorkItems(ctx context.Context, dateRange DateRange, visit func(project string, createdDate time.Time)) error {
+
+	wiql := map[string]string{
+		"query": fmt.Sprintf("SELECT [System.Id] FROM WorkItems WHERE [System.CreatedBy] = @Me "+
+			"AND [System.CreatedDate] >= '%s' AND [System.CreatedDate] < '%s'",
+			dateRange.From.Format(time.DateOnly), dateRange.To.Format(time.DateOnly)),
+	}
+	var result struct {
+		WorkItems []struct {
+			ID int `json:"id"`
+		} `json:"workItems"`
+	}
+	err := a.request(ctx, http.MethodPost, a.OrganizationURL+"/_apis/wit/wiql", wiql, &result)
+	if err != nil {
+		return err
+	}
+
+	var ids = make([]string, 0, len(result.WorkItems))
+	for _, workItem := range result.WorkItems {
+		ids = append(ids, strconv.Itoa(workItem.ID))
+	}
+
+	// Fetch the fields of the work items in batches
+	for batch := range slices.Chunk(ids, azureDevOpsWorkItemBatchSize) {
+		var workItems struct {
+			Value []struct {
+				Fields struct {
+					TeamProject string    `json:"System.TeamProject"`
+					CreatedDate time.Time `json:"System.CreatedDate"`
+				} `json:"fields"`
+			} `json:"value"`
+		}
+		query := url.Values{}
+		query.Set("ids", strings.Join(batch, ","))
+		query.Set("fields", "System.TeamProject,System.CreatedDate")
+		err = a.request(ctx, http.MethodGet, a.OrganizationURL+"/_apis/wit/workitems?"+query.Encode(), nil, &workItems)
+		if err != nil {
+			return err
+		}
+		for _, workItem := range workItems.Value {
+			visit(workItem.Fields.TeamProject, workItem.Fields.CreatedDate)
+		}
+	}
+	return nil
+}
+
+// getAzureDevOpsPages visits each value of a list response, requesting pages
+// with the $top and $skip parameters until a page comes back short
+func getAzureDevOpsPages[T any](ctx context.Context, a *AzureDevOps, path string, query url.Values, visit func(value T)) error {
+
+	const pageSize = 100
+	query.Set("$top", strconv.Itoa(pageSize))
+	for skip := 0; ; skip += pageSize {
+		query.Set("$skip", strconv.Itoa(skip))
+		var page struct {
+			Value []T `json:"value"`
+		}
+		err := a.request(ctx, http.MethodGet, path+"?"+query.Encode(), nil, &page)
+		if err != nil {
+			return err
+		}
+		for _, value := range page.Value {
+			visit(value)
+		}
+		if len(page.Value) < pageSize {
+			return nil
+		}
+	}
+}
+
+// request sends an Azure DevOps API request with an optional JSON body and
+// decodes the JSON response
+func (a *AzureDevOps) request(ctx context.Context, method string, requestURL string, body any, value any) error {
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	// Every request names the API version
+	separator := "?"
+	if strings.Contains(requestURL, "?") {
+		separator = "&"
+	}
+	request, err := http.NewRequestWithContext(ctx, method, requestURL+separator+"api-version="+azureDevOpsAPIVersion, reader)
+	if err != nil {
+		return err
+	}
+	request.SetBasicAuth("", a.Token)
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	response, err := a.Client.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to query azure devops: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode/100 != 2 {
+		return fmt.Errorf("failed to query azure devops: azure devops returned %s", response.Status)
+	}
+
+	err = json.NewDecoder(response.Body).Decode(value)
+	if err != nil {
+		return fmt.Errorf("failed to parse the azure devops response: %w", err)
+	}
+	return nil
+}
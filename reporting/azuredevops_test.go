@@ -0,0 +1,89 @@
+package reporting_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the NewAzureDevOps constructor
+func TestNewAzureDevOps(t *testing.T) {
+	_, err := rpt.NewAzureDevOps("", "token")
+	assert.Error(t, err)
+
+	_, err = rpt.NewAzureDevOps("https://dev.azure.com/org", "")
+	assert.Error(t, err)
+}
+
+// Test collecting contributions from an Azure DevOps organization
+func TestAzureDevOpsCollect(t *testing.T) {
+	var wiql string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, token, _ := r.BasicAuth()
+		assert.Equal(t, "token", token)
+		assert.Equal(t, "7.1", r.URL.Query().Get("api-version"))
+
+		query := r.URL.Query()
+		switch r.URL.Path {
+		case "/org/_apis/connectionData":
+			w.Write([]byte(`{"authenticatedUser": {"id": "id1"}}`))
+		case "/org/_apis/git/repositories":
+			w.Write([]byte(`{"value": [{"id": "r1", "name": "app", "webUrl": "https://dev.azure.com/org/Project/_git/app",
+				"project": {"name": "Project"}}]}`))
+		case "/org/Project/_apis/git/repositories/r1/commits":
+			assert.Equal(t, "user1@example.com", query.Get("searchCriteria.author"))
+			assert.Equal(t, "2023-01-01T00:00:00Z", query.Get("searchCriteria.fromDate"))
+			assert.Equal(t, "2024-12-31T23:59:59Z", query.Get("searchCriteria.toDate"))
+			w.Write([]byte(`{"value": [{"author": {"date": "2024-02-01T00:00:00Z"}}, {"author": {"date": "2023-02-01T00:00:00Z"}}]}`))
+		case "/org/Project/_apis/git/repositories/r1/pullrequests":
+			if query.Get("searchCriteria.creatorId") == "id1" {
+				w.Write([]byte(`{"value": [{"creationDate": "2024-03-01T00:00:00Z"}, {"creationDate": "2021-03-01T00:00:00Z"}]}`))
+			} else {
+				assert.Equal(t, "id1", query.Get("searchCriteria.reviewerId"))
+				w.Write([]byte(`{"value": [{"creationDate": "2023-03-01T00:00:00Z"}]}`))
+			}
+		case "/org/_apis/wit/wiql":
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			wiql = body["query"]
+			w.Write([]byte(`{"workItems": [{"id": 7}, {"id": 8}]}`))
+		case "/org/_apis/wit/workitems":
+			assert.Equal(t, "7,8", query.Get("ids"))
+			w.Write([]byte(`{"value": [
+				{"fields": {"System.TeamProject": "Project", "System.CreatedDate": "2024-04-01T00:00:00Z"}},
+				{"fields": {"System.TeamProject": "Project", "System.CreatedDate": "2024-05-01T00:00:00Z"}}
+			]}`))
+		default:
+			t.Errorf("unexpected request for %s", r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	azureDevOps, err := rpt.NewAzureDevOps(server.URL+"/org/", "token")
+	assert.NoError(t, err)
+	collector, err := rpt.NewProviderCollector(azureDevOps, "user1@example.com", 2023, 2024)
+	assert.NoError(t, err)
+	queryResults, err := collector.Collect(context.Background())
+	assert.NoError(t, err)
+
+	assert.Contains(t, wiql, "[System.CreatedBy] = @Me")
+	assert.Contains(t, wiql, "[System.CreatedDate] < '2025-01-01'")
+	assert.Len(t, queryResults, 2)
+
+	collection := queryResults["user1@example.com-2024"].User.ContributionsCollection
+	assert.Equal(t, 1, int(collection.TotalCommitContributions))
+	assert.Equal(t, 1, int(collection.TotalPullRequestContributions))
+	assert.Equal(t, 2, int(collection.TotalIssueContributions))
+	assert.Equal(t, 2, collection.TotalRepositories())
+	assert.Equal(t, "Project/app", string(collection.CommitContributionsByRepository[0].Repository.Name))
+
+	collection = queryResults["user1@example.com-2023"].User.ContributionsCollection
+	assert.Equal(t, 1, int(collection.TotalCommitContributions))
+	assert.Equal(t, 1, int(collection.TotalPullRequestReviewContributions))
+}
@@ -0,0 +1,96 @@
+package reporting
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/url"
+	"regexp"
+	"time"
+)
+
+// The retries of the default retry policy
+const DefaultRetries = 3
+
+// The status codes of GraphQL responses worth retrying, as githubv4 reports
+// them only in the error message
+var retryableStatus = regexp.MustCompile(`non-200 OK status code: (429|5\d\d) `)
+
+// A RetryPolicy retries failed queries that may succeed when sent again, like
+// those failing on a network error or a 5xx response, waiting longer each time
+type RetryPolicy struct {
+	// The most times a query is sent, including the first. Zero sends it once.
+	MaxAttempts int
+	// The wait before the first retry, doubled for each retry after it
+	InitialBackoff time.Duration
+	// The longest wait between retries
+	MaxBackoff time.Duration
+	// The fraction of each wait, from 0 to 1, randomized so clients don't retry in step
+	Jitter float64
+	// Called before each retry, like for counting the retries, when set
+	OnRetry func(attempt int, err error)
+}
+
+// DefaultRetryPolicy returns a policy retrying up to the number of times, waiting
+// from one second up to 30 seconds between them with 20% jitter
+func DefaultRetryPolicy(retries int) RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    retries + 1,
+		InitialBackoff: time.Second,
+		MaxBackoff:     30 * time.Second,
+		Jitter:         0.2,
+	}
+}
+
+// Do calls the operation until it succeeds, fails with an error that isn't worth
+// retrying, or runs out of attempts, returning the last error. Canceling the
+// context stops the waiting between attempts.
+func (p RetryPolicy) Do(ctx context.Context, operation func() error) error {
+
+	for attempt := 1; ; attempt++ {
+		err := operation()
+		if err == nil || attempt >= p.MaxAttempts || ctx.Err() != nil || !Retryable(err) {
+			return err
+		}
+		if p.OnRetry != nil {
+			p.OnRetry(attempt, err)
+		}
+
+		timer := time.NewTimer(p.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns the wait after the attempt, doubling from the initial backoff
+// up to the maximum, with a random part of up to the jitter fraction taken off
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+
+	backoff := p.InitialBackoff
+	for range attempt - 1 {
+		if p.MaxBackoff > 0 && backoff >= p.MaxBackoff {
+			break
+		}
+		backoff *= 2
+	}
+	if p.MaxBackoff > 0 {
+		backoff = min(backoff, p.MaxBackoff)
+	}
+	jitter := min(max(p.Jitter, 0), 1)
+	return backoff - time.Duration(rand.Float64()*jitter*float64(backoff))
+}
+
+// Retryable reports whether a query error may not happen again, like a network
+// error, a rate limit, or a server error, rather than an error in the query itself
+func Retryable(err error) bool {
+
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var urlError *url.Error
+	return errors.As(err, &urlError) || retryableStatus.MatchString(err.Error())
+}
@@ -0,0 +1,97 @@
+package reporting_test
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test telling the errors worth retrying apart
+func TestRetryable(t *testing.T) {
+	assert.True(t, rpt.Retryable(&url.Error{Op: "Post", URL: "https://api.github.com/graphql", Err: errors.New("connection reset")}))
+	assert.True(t, rpt.Retryable(errors.New(`non-200 OK status code: 502 Bad Gateway body: ""`)))
+	assert.True(t, rpt.Retryable(errors.New(`non-200 OK status code: 429 Too Many Requests body: ""`)))
+	assert.False(t, rpt.Retryable(errors.New(`non-200 OK status code: 401 Unauthorized body: ""`)))
+	assert.False(t, rpt.Retryable(errors.New("Could not resolve to a User with the login of 'nobody'.")))
+	assert.False(t, rpt.Retryable(&url.Error{Op: "Post", URL: "https://api.github.com/graphql", Err: context.Canceled}))
+}
+
+// Test retrying an operation by the policy
+func TestRetryPolicyDo(t *testing.T) {
+	serverError := errors.New(`non-200 OK status code: 503 Service Unavailable body: ""`)
+	var retries []int
+	policy := rpt.RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     2 * time.Millisecond,
+		Jitter:         0.5,
+		OnRetry:        func(attempt int, err error) { retries = append(retries, attempt) },
+	}
+
+	// Ensure a query succeeding on a retry returns no error
+	calls := 0
+	err := policy.Do(context.Background(), func() error {
+		calls++
+		if calls < 3 {
+			return serverError
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, []int{1, 2}, retries)
+
+	// Ensure the last error is returned after the last attempt
+	calls = 0
+	err = policy.Do(context.Background(), func() error {
+		calls++
+		return serverError
+	})
+	assert.ErrorIs(t, err, serverError)
+	assert.Equal(t, 3, calls)
+
+	// Ensure errors in the query aren't retried
+	calls = 0
+	err = policy.Do(context.Background(), func() error {
+		calls++
+		return errors.New("field 'nope' doesn't exist")
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+
+	// Ensure a zero policy sends the query once
+	calls = 0
+	err = rpt.RetryPolicy{}.Do(context.Background(), func() error {
+		calls++
+		return serverError
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+
+	// Ensure canceling the context stops the waiting
+	ctx, cancel := context.WithCancel(context.Background())
+	policy.InitialBackoff = time.Hour
+	policy.MaxBackoff = time.Hour
+	policy.OnRetry = func(attempt int, err error) { cancel() }
+	start := time.Now()
+	err = policy.Do(ctx, func() error { return serverError })
+	assert.ErrorIs(t, err, serverError)
+	assert.Less(t, time.Since(start), time.Minute)
+}
+
+// Test setting the Reporter's retry policy
+func TestWithRetryPolicy(t *testing.T) {
+	reporter, err := rpt.NewReporter(nil, "user1", 2023, 2023)
+	assert.NoError(t, err)
+	assert.Equal(t, rpt.DefaultRetries+1, reporter.RetryPolicy.MaxAttempts)
+
+	reporter, err = rpt.NewReporter(nil, "user1", 2023, 2023, rpt.WithRetryPolicy(rpt.DefaultRetryPolicy(0)))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, reporter.RetryPolicy.MaxAttempts)
+	assert.Equal(t, time.Second, reporter.RetryPolicy.InitialBackoff)
+}
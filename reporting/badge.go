@@ -0,0 +1,71 @@
+package reporting
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+)
+
+// The label and color of the contributions badge, with the color's shade in
+// the shields.io badges
+const (
+	badgeLabel = "contributions"
+	badgeColor = "brightgreen"
+	badgeFill  = "#4c1"
+)
+
+// A Badge is a shields.io endpoint badge, described at https://shields.io/badges/endpoint-badge
+type Badge struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+}
+
+// Badge returns a badge with the commits and other contributions of every
+// user and year, like "contributions | 7,866"
+func (a AggregatedResults) Badge() Badge {
+	return Badge{
+		SchemaVersion: 1,
+		Label:         badgeLabel,
+		Message:       formatThousands(a.TotalCommitContributions + a.TotalOtherContributions),
+		Color:         badgeColor,
+	}
+}
+
+// WriteSVG renders the badge as a flat SVG like the shields.io badges, sizing
+// each half by its characters, as a browser's text metrics aren't available
+func (b Badge) WriteSVG(w io.Writer) error {
+
+	labelWidth := 10 + 7*len(b.Label)
+	messageWidth := 10 + 7*len(b.Message)
+	width := labelWidth + messageWidth
+	label := html.EscapeString(b.Label)
+	message := html.EscapeString(b.Message)
+	_, err := fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20" role="img" aria-label="%s: %s">`+
+		`<title>%s: %s</title>`+
+		`<rect width="%d" height="20" fill="#555"/><rect x="%d" width="%d" height="20" fill="%s"/>`+
+		`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">`+
+		`<text x="%d" y="14">%s</text><text x="%d" y="14">%s</text></g></svg>`+"\n",
+		width, label, message, label, message,
+		labelWidth, labelWidth, messageWidth, badgeFill,
+		labelWidth/2, label, labelWidth+messageWidth/2, message)
+	if err != nil {
+		return fmt.Errorf("failed to write the badge: %w", err)
+	}
+	return nil
+}
+
+// formatThousands formats the number with a comma between each group of three digits
+func formatThousands(number int) string {
+	digits := strconv.Itoa(number)
+	start := 0
+	if number < 0 {
+		start = 1
+	}
+	for i := len(digits) - 3; i > start; i -= 3 {
+		digits = digits[:i] + "," + digits[i:]
+	}
+	return digits
+}
@@ -0,0 +1,23 @@
+package reporting_test
+
+import (
+	"bytes"
+	"testing"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test the contributions badge and its SVG
+func TestBadge(t *testing.T) {
+	badge := rpt.AggregatedResults{TotalCommitContributions: 1234567, TotalOtherContributions: 1000}.Badge()
+	assert.Equal(t, rpt.Badge{SchemaVersion: 1, Label: "contributions", Message: "1,235,567", Color: "brightgreen"}, badge)
+	assert.Equal(t, "999", rpt.AggregatedResults{TotalOtherContributions: 999}.Badge().Message)
+	assert.Equal(t, "0", rpt.AggregatedResults{}.Badge().Message)
+
+	var svg bytes.Buffer
+	require.NoError(t, badge.WriteSVG(&svg))
+	assert.Contains(t, svg.String(), `<title>contributions: 1,235,567</title>`)
+	assert.Contains(t, svg.String(), `width="174"`)
+}
@@ -0,0 +1,75 @@
+package reporting
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// The most years of contributions a GitHub query asks for by default
+const DefaultYearsPerQuery = 5
+
+// contributionsBatchQuery returns the type of a query for a user's contributions
+// collection over the number of ranges, each aliased like
+// year0: contributionsCollection(from: $from0, to: $to0), with the rate limit
+func contributionsBatchQuery(ranges int) reflect.Type {
+
+	userFields := []reflect.StructField{
+		{Name: "Login", Type: reflect.TypeFor[githubv4.String]()},
+		{Name: "CreatedAt", Type: reflect.TypeFor[githubv4.DateTime]()},
+	}
+	for i := range ranges {
+		userFields = append(userFields, reflect.StructField{
+			Name: fmt.Sprintf("Year%d", i),
+			Type: reflect.TypeFor[ContributionsCollection](),
+			Tag:  reflect.StructTag(fmt.Sprintf(`graphql:"year%d: contributionsCollection(from: $from%d, to: $to%d)"`, i, i, i)),
+		})
+	}
+	return reflect.StructOf([]reflect.StructField{
+		{Name: "User", Type: reflect.StructOf(userFields), Tag: `graphql:"user(login: $login)"`},
+		{Name: "RateLimit", Type: reflect.TypeFor[RateLimit]()},
+	})
+}
+
+// queryContributionsBatch queries the user's contributions collections over the
+// ranges in one round trip, like queryContributions does for one, returning a
+// query result for each range
+func queryContributionsBatch(ctx context.Context, client GraphQLClient, retryPolicy RetryPolicy,
+	rateLimitPolicy RateLimitPolicy, user string, ranges []DateRange, details bool) ([]QueryResult, error) {
+
+	var variables = map[string]interface{}{
+		"login":   githubv4.String(user),
+		"details": githubv4.Boolean(details),
+	}
+	for i, dateRange := range ranges {
+		variables[fmt.Sprintf("from%d", i)] = githubv4.DateTime{Time: dateRange.From}
+		variables[fmt.Sprintf("to%d", i)] = githubv4.DateTime{Time: dateRange.To.Add(-time.Second)}
+	}
+
+	queryType := contributionsBatchQuery(len(ranges))
+	var query reflect.Value
+	err := retryPolicy.Do(ctx, func() error {
+		query = reflect.New(queryType)
+		return client.Query(ctx, query.Interface(), variables)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query github for %s in %d to %d: %w", user,
+			ranges[len(ranges)-1].From.Year(), ranges[0].From.Year(), err)
+	}
+
+	userField := query.Elem().Field(0)
+	var queryResults = make([]QueryResult, len(ranges))
+	for i := range ranges {
+		queryResults[i].User.Login = userField.Field(0).Interface().(githubv4.String)
+		queryResults[i].User.CreatedAt = userField.Field(1).Interface().(githubv4.DateTime)
+		queryResults[i].User.ContributionsCollection = userField.Field(i + 2).Interface().(ContributionsCollection)
+	}
+	err = rateLimitPolicy.wait(ctx, user, query.Elem().Field(1).Interface().(RateLimit))
+	if err != nil {
+		return queryResults, fmt.Errorf("failed to wait for the github rate limit to reset: %w", err)
+	}
+	return queryResults, nil
+}
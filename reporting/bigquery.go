@@ -0,0 +1,138 @@
+package reporting
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The OAuth scope needed to stream rows into BigQuery
+const BigQueryScope = "https://www.googleapis.com/auth/bigquery.insertdata"
+
+// A BigQuery exporter streams a row per user-year into a BigQuery table, with
+// the schema: run_timestamp TIMESTAMP, user STRING, year INTEGER, commits INTEGER,
+// issues INTEGER, pull_requests INTEGER, pull_request_reviews INTEGER,
+// repositories INTEGER, restricted INTEGER
+type BigQuery struct {
+	// The Google Cloud project ID
+	Project string
+	// The dataset ID
+	Dataset string
+	// The table ID
+	Table string
+	// The BigQuery API endpoint
+	Endpoint string
+	// An HTTP client authenticated for the BigQuery API
+	Client *http.Client
+}
+
+// A BigQueryRow is a single user-year row streamed into the table
+type BigQueryRow struct {
+	RunTimestamp       string `json:"run_timestamp"`
+	User               string `json:"user"`
+	Year               int    `json:"year"`
+	Commits            int    `json:"commits"`
+	Issues             int    `json:"issues"`
+	PullRequests       int    `json:"pull_requests"`
+	PullRequestReviews int    `json:"pull_request_reviews"`
+	Repositories       int    `json:"repositories"`
+	Restricted         int    `json:"restricted"`
+}
+
+// Constructs a new BigQuery object
+// The table is the fully qualified table name, like project.dataset.table
+// The client is authenticated for the BigQuery API, like one from NewGoogleClient
+func NewBigQuery(table string, client *http.Client) (bigQuery *BigQuery, err error) {
+
+	parts := strings.Split(table, ".")
+	if len(parts) != 3 || slices.Contains(parts, "") {
+		err = fmt.Errorf("the bigquery table must be named like project.dataset.table, got %q", table)
+		return nil, err
+	}
+
+	return &BigQuery{
+		Project:  parts[0],
+		Dataset:  parts[1],
+		Table:    parts[2],
+		Endpoint: "https://bigquery.googleapis.com",
+		Client:   client,
+	}, err
+}
+
+// Export streams a row per user-year with the insertAll method. Each row's insert ID
+// combines the user-year and the run time, so retried runs don't duplicate rows.
+func (b *BigQuery) Export(queryResults map[string]QueryResult, aggregatedResults AggregatedResults) error {
+
+	if len(queryResults) == 0 {
+		return nil
+	}
+
+	runTimestamp := time.Unix(int64(aggregatedResults.Timestamp), 0).UTC().Format(time.RFC3339)
+	rows := make([]map[string]any, 0, len(queryResults))
+	for _, userYear := range slices.Sorted(maps.Keys(queryResults)) {
+		user, year := splitUserYear(userYear)
+		collection := queryResults[userYear].User.ContributionsCollection
+		rows = append(rows, map[string]any{
+			"insertId": userYear + "-" + strconv.Itoa(aggregatedResults.Timestamp),
+			"json": BigQueryRow{
+				RunTimestamp:       runTimestamp,
+				User:               user,
+				Year:               year,
+				Commits:            int(collection.TotalCommitContributions),
+				Issues:             int(collection.TotalIssueContributions),
+				PullRequests:       int(collection.TotalPullRequestContributions),
+				PullRequestReviews: int(collection.TotalPullRequestReviewContributions),
+				Repositories:       collection.TotalRepositories(),
+				Restricted:         int(collection.RestrictedContributionsCount),
+			},
+		})
+	}
+
+	body, err := json.Marshal(map[string]any{"rows": rows})
+	if err != nil {
+		return err
+	}
+
+	endpoint := b.Endpoint + "/bigquery/v2/projects/" + url.PathEscape(b.Project) +
+		"/datasets/" + url.PathEscape(b.Dataset) + "/tables/" + url.PathEscape(b.Table) + "/insertAll"
+	response, err := b.Client.Post(endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to stream rows to bigquery: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode/100 != 2 {
+		return fmt.Errorf("failed to stream rows to bigquery: bigquery returned %s", response.Status)
+	}
+
+	// Rows can be rejected individually, even when the request succeeds
+	var insertResponse struct {
+		InsertErrors []struct {
+			Index  int `json:"index"`
+			Errors []struct {
+				Message string `json:"message"`
+			} `json:"errors"`
+		} `json:"insertErrors"`
+	}
+	err = json.NewDecoder(response.Body).Decode(&insertResponse)
+	if err != nil {
+		return fmt.Errorf("failed to parse the bigquery response: %w", err)
+	}
+	if len(insertResponse.InsertErrors) > 0 {
+		insertError := insertResponse.InsertErrors[0]
+		message := "unknown error"
+		if len(insertError.Errors) > 0 {
+			message = insertError.Errors[0].Message
+		}
+		return fmt.Errorf("bigquery rejected %d rows, the first at index %d: %s",
+			len(insertResponse.InsertErrors), insertError.Index, message)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package reporting_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the NewBigQuery constructor
+func TestNewBigQuery(t *testing.T) {
+	_, err := rpt.NewBigQuery("dataset.table", http.DefaultClient)
+	assert.Error(t, err)
+
+	bigQuery, err := rpt.NewBigQuery("project.dataset.table", http.DefaultClient)
+	assert.NoError(t, err)
+	assert.Equal(t, "project", bigQuery.Project)
+	assert.Equal(t, "dataset", bigQuery.Dataset)
+	assert.Equal(t, "table", bigQuery.Table)
+}
+
+// Test the BigQuery Export method
+func TestBigQueryExport(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		wantErr  bool
+	}{
+		{
+			name:     "all rows inserted",
+			response: `{"kind": "bigquery#tableDataInsertAllResponse"}`,
+			wantErr:  false,
+		},
+		{
+			name:     "rows rejected",
+			response: `{"insertErrors": [{"index": 0, "errors": [{"message": "no such field"}]}]}`,
+			wantErr:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var path string
+			var body struct {
+				Rows []struct {
+					InsertID string          `json:"insertId"`
+					JSON     rpt.BigQueryRow `json:"json"`
+				} `json:"rows"`
+			}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				path = r.URL.Path
+				json.NewDecoder(r.Body).Decode(&body)
+				w.Write([]byte(test.response))
+			}))
+			defer server.Close()
+
+			bigQuery, err := rpt.NewBigQuery("project.dataset.table", server.Client())
+			assert.NoError(t, err)
+			bigQuery.Endpoint = server.URL
+
+			queryResults, err := loadQueryResultsMap("single_user_single_year.json")
+			assert.NoError(t, err)
+			err = bigQuery.Export(queryResults, rpt.AggregatedResults{Timestamp: 1730462400})
+
+			if test.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, "/bigquery/v2/projects/project/datasets/dataset/tables/table/insertAll", path)
+			assert.Len(t, body.Rows, 1)
+			assert.Equal(t, "user1-2023-1730462400", body.Rows[0].InsertID)
+			assert.Equal(t, "user1", body.Rows[0].JSON.User)
+			assert.Equal(t, 2023, body.Rows[0].JSON.Year)
+			assert.Equal(t, 10, body.Rows[0].JSON.Commits)
+			assert.Equal(t, "2024-11-01T12:00:00Z", body.Rows[0].JSON.RunTimestamp)
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package reporting
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// The Bitbucket Cloud API, used when a credential doesn't name another URL
+const DefaultBitbucketURL = "https://api.bitbucket.org"
+
+// A Bitbucket provider collects a user's commits, pull requests, and pull request
+// reviews across the Bitbucket Cloud repositories they are a member of
+type Bitbucket struct {
+	// The base URL of the Bitbucket API
+	BaseURL string
+	// The Bitbucket username the app password belongs to
+	Username string
+	// An app password with the repository and pull request read permissions
+	Token string
+	// The HTTP client used for the API requests
+	Client *http.Client
+}
+
+// A bitbucketPage holds a single page of a paginated Bitbucket response
+type bitbucketPage[T any] struct {
+	Values []T    `json:"values"`
+	Next   string `json:"next"`
+}
+
+// A bitbucketRepository holds the fields of a Bitbucket repository
+type bitbucketRepository struct {
+	FullName  string    `json:"full_name"`
+	UpdatedOn time.Time `json:"updated_on"`
+	Links     struct {
+		HTML struct {
+			Href string `json:"href"`
+		} `json:"html"`
+	} `json:"links"`
+}
+
+// A bitbucketCommit holds the fields of a Bitbucket commit
+type bitbucketCommit struct {
+	Date   time.Time `json:"date"`
+	Author struct {
+		User struct {
+			UUID string `json:"uuid"`
+		} `json:"user"`
+	} `json:"author"`
+}
+
+// A bitbucketPullRequest holds the fields of a Bitbucket pull request
+type bitbucketPullRequest struct {
+	CreatedOn time.Time `json:"created_on"`
+}
+
+// Constructs a new Bitbucket object
+// The baseURL is the Bitbucket API, defaulting to Bitbucket Cloud
+// The username is a Bitbucket username
+// The token is an app password for the username
+func NewBitbucket(baseURL string, username string, token string) (bitbucket *Bitbucket, err error) {
+
+	if username == "" {
+		err = fmt.Errorf("the bitbucket username cannot be blank")
+		return nil, err
+	}
+	if token == "" {
+		err = fmt.Errorf("the bitbucket app password cannot be blank")
+		return nil, err
+	}
+	if baseURL == "" {
+		baseURL = DefaultBitbucketURL
+	}
+
+	return &Bitbucket{
+		BaseURL:  strings.TrimSuffix(baseURL, "/"),
+		Username: username,
+		Token:    token,
+		Client:   http.DefaultClient,
+	}, err
+}
+
+// Collect counts the app password owner's commits, authored pull requests, and
+// pull requests they were a reviewer on, in every repository they are a member of
+func (b *Bitbucket) Collect(ctx context.Context, user string, dateRange DateRange) ([]Contribution, error) {
+
+	var contributions []Contribution
+
+	slog.Debug("Fetching bitbucket repositories", "user", user)
+
+	var account struct {
+		UUID string `json:"uuid"`
+	}
+	err := b.get(ctx, b.BaseURL+"/2.0/user", &account)
+	if err != nil {
+		return contributions, err
+	}
+
+	var repositories []bitbucketRepository
+	err = getBitbucketPages(ctx, b, b.BaseURL+"/2.0/repositories?role=member&pagelen=100",
+		func(repository bitbucketRepository) bool {
+			// Skip repositories untouched since before the range
+			if !repository.UpdatedOn.Before(dateRange.From) {
+				repositories = append(repositories, repository)
+			}
+			return true
+		})
+	if err != nil {
+		return contributions, err
+	}
+
+	for _, bitbucketRepository := range repositories {
+		repository := Repository{Name: bitbucketRepository.FullName, URL: bitbucketRepository.Links.HTML.Href}
+		repositoryPath := b.BaseURL + "/2.0/repositories/" + bitbucketRepository.FullName
+
+		// Commits are listed newest first, so stop at the first one before the range
+		err = getBitbucketPages(ctx, b, repositoryPath+"/commits?pagelen=100",
+			func(commit bitbucketCommit) bool {
+				if commit.Author.User.UUID == account.UUID && dateRange.Contains(commit.Date) {
+					contributions = append(contributions, Contribution{
+						Kind: ContributionCommit, Repository: repository, Date: commit.Date, Count: 1,
+					})
+				}
+				return !commit.Date.Before(dateRange.From)
+			})
+		if err != nil {
+			return contributions, err
+		}
+
+		// Filter the pull requests by author and reviewer on the server
+		roles := map[string]ContributionKind{
+			"author":    ContributionPullRequest,
+			"reviewers": ContributionPullRequestReview,
+		}
+		for role, kind := range roles {
+			query := url.Values{}
+			for _, state := range []string{"OPEN", "MERGED", "DECLINED", "SUPERSEDED"} {
+				query.Add("state", state)
+			}
+			query.Set("pagelen", "50")
+			query.Set("q", fmt.Sprintf(`%s.uuid="%s" AND created_on >= %s AND created_on < %s`,
+				role, account.UUID, dateRange.From.Format(time.RFC3339), dateRange.To.Format(time.RFC3339)))
+			err = getBitbucketPages(ctx, b, repositoryPath+"/pullrequests?"+query.Encode(),
+				func(pullRequest bitbucketPullRequest) bool {
+					contributions = append(contributions, Contribution{
+						Kind: kind, Repository: repository, Date: pullRequest.CreatedOn, Count: 1,
+					})
+					return true
+				})
+			if err != nil {
+				return contributions, err
+			}
+		}
+	}
+	return contributions, nil
+}
+
+// getBitbucketPages visits the values on each page of a paginated Bitbucket
+// response, until the last page or until the visit function returns false
+func getBitbucketPages[T any](ctx context.Context, b *Bitbucket, pageURL string, visit func(value T) bool) error {
+	for pageURL != "" {
+		var page bitbucketPage[T]
+		err := b.get(ctx, pageURL, &page)
+		if err != nil {
+			return err
+		}
+		for _, value := range page.Values {
+			if !visit(value) {
+				return nil
+			}
+		}
+		pageURL = page.Next
+	}
+	return nil
+}
+
+// get requests a Bitbucket API URL and decodes the JSON response
+func (b *Bitbucket) get(ctx context.Context, requestURL string, value any) error {
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return err
+	}
+	request.SetBasicAuth(b.Username, b.Token)
+
+	response, err := b.Client.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to query bitbucket: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode/100 != 2 {
+		return fmt.Errorf("failed to query bitbucket: bitbucket returned %s", response.Status)
+	}
+
+	err = json.NewDecoder(response.Body).Decode(value)
+	if err != nil {
+		return fmt.Errorf("failed to parse the bitbucket response: %w", err)
+	}
+	return nil
+}
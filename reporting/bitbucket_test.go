@@ -0,0 +1,96 @@
+package reporting_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the NewBitbucket constructor
+func TestNewBitbucket(t *testing.T) {
+	_, err := rpt.NewBitbucket("", "", "password")
+	assert.Error(t, err)
+
+	_, err = rpt.NewBitbucket("", "user1", "")
+	assert.Error(t, err)
+
+	bitbucket, err := rpt.NewBitbucket("", "user1", "password")
+	assert.NoError(t, err)
+	assert.Equal(t, rpt.DefaultBitbucketURL, bitbucket.BaseURL)
+}
+
+// Test collecting contributions from Bitbucket repositories
+func TestBitbucketCollect(t *testing.T) {
+	var server *httptest.Server
+	var commitPages int
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, _ := r.BasicAuth()
+		assert.Equal(t, "user1", user)
+		assert.Equal(t, "password", password)
+
+		switch r.URL.Path {
+		case "/2.0/user":
+			w.Write([]byte(`{"uuid": "{u1}"}`))
+		case "/2.0/repositories":
+			w.Write([]byte(`{"values": [
+				{"full_name": "team/app", "updated_on": "2024-06-01T00:00:00Z",
+				 "links": {"html": {"href": "https://bitbucket.org/team/app"}}},
+				{"full_name": "team/old", "updated_on": "2019-06-01T00:00:00Z"}
+			]}`))
+		case "/2.0/repositories/team/app/commits":
+			commitPages++
+			if r.URL.Query().Get("page") == "" {
+				w.Write([]byte(`{"values": [
+					{"date": "2025-01-02T00:00:00Z", "author": {"user": {"uuid": "{u1}"}}},
+					{"date": "2024-05-01T00:00:00Z", "author": {"user": {"uuid": "{u1}"}}},
+					{"date": "2024-04-01T00:00:00Z", "author": {"user": {"uuid": "{u2}"}}}
+				], "next": "` + server.URL + `/2.0/repositories/team/app/commits?page=2"}`))
+				return
+			}
+			w.Write([]byte(`{"values": [
+				{"date": "2023-03-01T00:00:00Z", "author": {"user": {"uuid": "{u1}"}}},
+				{"date": "2022-12-01T00:00:00Z", "author": {"user": {"uuid": "{u1}"}}}
+			], "next": "` + server.URL + `/2.0/repositories/team/app/commits?page=3"}`))
+		case "/2.0/repositories/team/app/pullrequests":
+			query := r.URL.Query().Get("q")
+			assert.Contains(t, query, "created_on >= 2023-01-01T00:00:00Z")
+			assert.Len(t, r.URL.Query()["state"], 4)
+			if strings.HasPrefix(query, `author.uuid="{u1}"`) {
+				w.Write([]byte(`{"values": [{"created_on": "2024-05-02T00:00:00Z"}]}`))
+			} else {
+				w.Write([]byte(`{"values": [{"created_on": "2023-05-02T00:00:00Z"}, {"created_on": "2023-06-02T00:00:00Z"}]}`))
+			}
+		default:
+			t.Errorf("unexpected request for %s", r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	bitbucket, err := rpt.NewBitbucket(server.URL, "user1", "password")
+	assert.NoError(t, err)
+	collector, err := rpt.NewProviderCollector(bitbucket, "user1", 2023, 2024)
+	assert.NoError(t, err)
+	queryResults, err := collector.Collect(context.Background())
+	assert.NoError(t, err)
+
+	// Ensure paging stops at the first commit before the range
+	assert.Equal(t, 2, commitPages)
+	assert.Len(t, queryResults, 2)
+
+	collection := queryResults["user1-2024"].User.ContributionsCollection
+	assert.Equal(t, 1, int(collection.TotalCommitContributions))
+	assert.Equal(t, 1, int(collection.TotalPullRequestContributions))
+	assert.Equal(t, "https://bitbucket.org/team/app",
+		string(collection.CommitContributionsByRepository[0].Repository.URL))
+
+	collection = queryResults["user1-2023"].User.ContributionsCollection
+	assert.Equal(t, 1, int(collection.TotalCommitContributions))
+	assert.Equal(t, 2, int(collection.TotalPullRequestReviewContributions))
+	assert.Equal(t, 1, collection.TotalRepositories())
+}
@@ -0,0 +1,97 @@
+package reporting
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// The bucket holding the snapshot history in a bbolt database
+var boltSnapshotsBucket = []byte("snapshots")
+
+// A BoltSnapshotStore keeps the snapshot history in a single bbolt file,
+// an embedded key-value store that needs no CGO, unlike SQLite
+type BoltSnapshotStore struct {
+	// The path of the database file
+	Path string
+	db   *bolt.DB
+}
+
+// Constructs a new BoltSnapshotStore object
+// The path is the bbolt database file, created if needed
+func NewBoltSnapshotStore(path string) (store *BoltSnapshotStore, err error) {
+
+	if path == "" {
+		err = fmt.Errorf("the snapshot database path cannot be blank")
+		return nil, err
+	}
+
+	// Wait briefly rather than forever if another run holds the file lock
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the snapshot database: %w", err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(boltSnapshotsBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create the snapshots bucket: %w", err)
+	}
+
+	return &BoltSnapshotStore{Path: path, db: db}, err
+}
+
+// Close closes the database, releasing the file lock
+func (b *BoltSnapshotStore) Close() error {
+	return b.db.Close()
+}
+
+// Save appends the snapshot under the bucket's next sequence number
+func (b *BoltSnapshotStore) Save(snapshot Snapshot) error {
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	err = b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(boltSnapshotsBucket)
+		sequence, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+		// Big endian keys keep the snapshots in the order they were saved
+		key := binary.BigEndian.AppendUint64(nil, sequence)
+		return bucket.Put(key, data)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save the snapshot: %w", err)
+	}
+	return nil
+}
+
+// Snapshots reads the snapshot history from the database, oldest first
+func (b *BoltSnapshotStore) Snapshots() ([]Snapshot, error) {
+
+	var snapshots = make([]Snapshot, 0)
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltSnapshotsBucket).ForEach(func(key, value []byte) error {
+			var snapshot Snapshot
+			err := json.Unmarshal(value, &snapshot)
+			if err != nil {
+				return fmt.Errorf("failed to parse snapshot %d: %w", binary.BigEndian.Uint64(key), err)
+			}
+			snapshots = append(snapshots, snapshot)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the snapshots: %w", err)
+	}
+	return snapshots, nil
+}
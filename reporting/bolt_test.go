@@ -0,0 +1,59 @@
+package reporting_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the NewBoltSnapshotStore constructor
+func TestNewBoltSnapshotStore(t *testing.T) {
+	_, err := rpt.NewBoltSnapshotStore("")
+	assert.Error(t, err)
+}
+
+// Test saving and listing snapshots in a BoltSnapshotStore
+func TestBoltSnapshotStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.bolt")
+	store, err := rpt.NewBoltSnapshotStore(path)
+	assert.NoError(t, err)
+
+	// Ensure an empty store has no latest snapshot
+	_, found, err := rpt.LatestSnapshot(store)
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	queryResults, err := loadQueryResultsMap("multiple_years_deduplicated.json")
+	assert.NoError(t, err)
+
+	// Save enough snapshots to check the ordering past a single digit
+	for commits := range 12 {
+		snapshot := rpt.Snapshot{
+			Timestamp:    commits,
+			Results:      rpt.AggregatedResults{TotalCommitContributions: commits},
+			QueryResults: queryResults,
+		}
+		assert.NoError(t, store.Save(snapshot))
+	}
+
+	// Ensure the snapshots survive reopening the database
+	assert.NoError(t, store.Close())
+	store, err = rpt.NewBoltSnapshotStore(path)
+	assert.NoError(t, err)
+	defer store.Close()
+
+	snapshots, err := store.Snapshots()
+	assert.NoError(t, err)
+	assert.Len(t, snapshots, 12)
+	for index, snapshot := range snapshots {
+		assert.Equal(t, index, snapshot.Timestamp)
+	}
+
+	latest, found, err := rpt.LatestSnapshot(store)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, 11, latest.Results.TotalCommitContributions)
+	assert.Equal(t, queryResults, latest.QueryResults)
+}
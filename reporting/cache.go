@@ -0,0 +1,294 @@
+package reporting
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A Cache keeps the query results of each user-year on disk, in a file for
+// each provider, user, and year under its directory
+type Cache struct {
+	// The directory holding the cached query results
+	Dir string
+	// How long cached query results stay fresh, or forever if zero
+	TTL time.Duration
+	// Called with whether each user-year looked up was cached, like for counting the hits, when set
+	OnLookup func(hit bool)
+}
+
+// A CacheEntry describes the cached query results of a single user-year
+type CacheEntry struct {
+	Provider string    `json:"provider"`
+	User     string    `json:"user"`
+	Year     int       `json:"year"`
+	Size     int64     `json:"size"`
+	Modified time.Time `json:"modified"`
+	path     string
+}
+
+// CacheStats summarize the entries of a cache
+type CacheStats struct {
+	Dir     string `json:"dir"`
+	Entries int    `json:"entries"`
+	Users   int    `json:"users"`
+	// The total size of the entries in bytes
+	Size int64 `json:"size"`
+	// The modification times of the oldest and newest entries, if any
+	Oldest *time.Time `json:"oldest,omitempty"`
+	Newest *time.Time `json:"newest,omitempty"`
+}
+
+// DefaultCacheDir returns the ghcontributions directory in the user's cache
+// directory, like ~/.cache/ghcontributions on Linux
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find the cache directory: %w", err)
+	}
+	return filepath.Join(dir, MetricsNamespace), nil
+}
+
+// Constructs a new Cache object
+// The dir is the directory holding the cached query results, created if needed
+func NewCache(dir string) (cache *Cache, err error) {
+
+	if dir == "" {
+		err = fmt.Errorf("the cache directory cannot be blank")
+		return nil, err
+	}
+	err = os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the cache directory: %w", err)
+	}
+	return &Cache{Dir: dir}, nil
+}
+
+// path returns the file of a user-year. Usernames are escaped, as some
+// providers use email addresses.
+func (c *Cache) path(provider string, user string, year int) string {
+	if provider == "" {
+		provider = ProviderGitHub
+	}
+	return filepath.Join(c.Dir, url.PathEscape(provider), url.PathEscape(user), strconv.Itoa(year)+".json")
+}
+
+// Get returns the cached query results of a user-year, and false if they
+// aren't cached or are older than the TTL
+func (c *Cache) Get(provider string, user string, year int) (queryResult QueryResult, found bool, err error) {
+
+	path := c.path(provider, user, year)
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return QueryResult{}, false, nil
+	}
+	if err != nil {
+		return QueryResult{}, false, fmt.Errorf("failed to read the cache: %w", err)
+	}
+	if c.TTL > 0 && time.Since(info.ModTime()) > c.TTL {
+		return QueryResult{}, false, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return QueryResult{}, false, fmt.Errorf("failed to read the cache: %w", err)
+	}
+	err = json.Unmarshal(data, &queryResult)
+	if err != nil {
+		return QueryResult{}, false, fmt.Errorf("failed to parse the cached %s-%d: %w", user, year, err)
+	}
+	return queryResult, true, nil
+}
+
+// Put caches the query results of a user-year, replacing any cached before
+func (c *Cache) Put(provider string, user string, year int, queryResult QueryResult) error {
+
+	data, err := json.Marshal(queryResult)
+	if err != nil {
+		return fmt.Errorf("failed to encode the cached %s-%d: %w", user, year, err)
+	}
+	path := c.path(provider, user, year)
+	err = os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return fmt.Errorf("failed to create the cache directory: %w", err)
+	}
+	return WriteFileAtomically(path, data)
+}
+
+// An InactiveYearSkipper is a Provider that can stop collecting at the first year
+// without any activity before it, like GitHub, leaving the older years of the range
+// uncollected
+type InactiveYearSkipper interface {
+	SkipsInactiveYears() bool
+}
+
+// Collect collects the collector's contributions like its Collect method, except
+// that the whole years before the current one are read from the cache. Only the
+// range from the earliest year that isn't cached is collected, and the whole past
+// years collected are cached, including those without any contributions, unless
+// the collection fails. The years older than any collected that a provider skipping
+// inactive years may have left out aren't cached. The provider names the cache
+// entries, like the credential's.
+func (c *Cache) Collect(ctx context.Context, provider string, collector *ProviderCollector,
+	now time.Time) (map[string]QueryResult, error) {
+
+	// Read the cached years, oldest first, up to the first that isn't cached
+	var queryResults = make(map[string]QueryResult)
+	thisYear := now.UTC().Year()
+	lastYear := collector.Range.To.Add(-time.Second).Year()
+	collectFrom := collector.Range.To
+	cacheable := func(year int) bool {
+		yearRange := YearRange(year, year)
+		return year < thisYear && !yearRange.From.Before(collector.Range.From) && !yearRange.To.After(collector.Range.To)
+	}
+	for year := collector.Range.From.Year(); year <= lastYear; year++ {
+		if cacheable(year) {
+			queryResult, found, err := c.Get(provider, collector.User, year)
+			if err != nil {
+				slog.Warn("Couldn't read the cache", "user", collector.User, "year", year, "error", err)
+			}
+			if c.OnLookup != nil {
+				c.OnLookup(found)
+			}
+			if found {
+				if queryResult.User.Login != "" {
+					queryResults[collector.User+"-"+strconv.Itoa(year)] = queryResult
+				}
+				continue
+			}
+		}
+		collectFrom = YearRange(year, year).From
+		if collectFrom.Before(collector.Range.From) {
+			collectFrom = collector.Range.From
+		}
+		break
+	}
+	if !collectFrom.Before(collector.Range.To) {
+		return queryResults, nil
+	}
+
+	uncached := *collector
+	uncached.Range.From = collectFrom
+	collected, err := uncached.Collect(ctx)
+	MergeQueryResults(queryResults, collected)
+	if err != nil {
+		return queryResults, err
+	}
+	skipper, ok := collector.Provider.(InactiveYearSkipper)
+	skipsInactiveYears := ok && skipper.SkipsInactiveYears()
+	oldestCollected := lastYear + 1
+	for userYear := range collected {
+		if year, err := strconv.Atoi(strings.TrimPrefix(userYear, collector.User+"-")); err == nil {
+			oldestCollected = min(oldestCollected, year)
+		}
+	}
+	for year := collectFrom.Year(); year <= lastYear; year++ {
+		if skipsInactiveYears && year < oldestCollected {
+			continue
+		}
+		if cacheable(year) {
+			err = c.Put(provider, collector.User, year, collected[collector.User+"-"+strconv.Itoa(year)])
+			if err != nil {
+				slog.Warn("Couldn't write the cache", "user", collector.User, "year", year, "error", err)
+			}
+		}
+	}
+	return queryResults, nil
+}
+
+// Entries lists the cached user-years, sorted by provider, user, and year
+func (c *Cache) Entries() ([]CacheEntry, error) {
+
+	var entries []CacheEntry
+	err := filepath.WalkDir(c.Dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || filepath.Ext(path) != ".json" {
+			return err
+		}
+		relative, err := filepath.Rel(c.Dir, path)
+		if err != nil {
+			return err
+		}
+		parts := strings.Split(filepath.ToSlash(relative), "/")
+		if len(parts) != 3 {
+			return nil
+		}
+		provider, providerErr := url.PathUnescape(parts[0])
+		user, userErr := url.PathUnescape(parts[1])
+		year, yearErr := strconv.Atoi(strings.TrimSuffix(parts[2], ".json"))
+		if providerErr != nil || userErr != nil || yearErr != nil {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		entries = append(entries, CacheEntry{
+			Provider: provider,
+			User:     user,
+			Year:     year,
+			Size:     info.Size(),
+			Modified: info.ModTime(),
+			path:     path,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the cache: %w", err)
+	}
+	return entries, nil
+}
+
+// Purge removes the cached user-years the filter matches, returning them
+func (c *Cache) Purge(filter func(entry CacheEntry) bool) ([]CacheEntry, error) {
+
+	entries, err := c.Entries()
+	if err != nil {
+		return nil, err
+	}
+	var purged []CacheEntry
+	for _, entry := range entries {
+		if !filter(entry) {
+			continue
+		}
+		err = os.Remove(entry.path)
+		if err != nil {
+			return purged, fmt.Errorf("failed to purge the cached %s-%d: %w", entry.User, entry.Year, err)
+		}
+		purged = append(purged, entry)
+	}
+	return purged, nil
+}
+
+// Stats summarizes the entries of the cache
+func (c *Cache) Stats() (CacheStats, error) {
+
+	entries, err := c.Entries()
+	if err != nil {
+		return CacheStats{}, err
+	}
+	stats := CacheStats{Dir: c.Dir, Entries: len(entries)}
+	var users []string
+	for _, entry := range entries {
+		stats.Size += entry.Size
+		users = append(users, entry.Provider+"/"+entry.User)
+		if stats.Oldest == nil || entry.Modified.Before(*stats.Oldest) {
+			stats.Oldest = &entry.Modified
+		}
+		if stats.Newest == nil || entry.Modified.After(*stats.Newest) {
+			stats.Newest = &entry.Modified
+		}
+	}
+	slices.Sort(users)
+	stats.Users = len(slices.Compact(users))
+	return stats, nil
+}
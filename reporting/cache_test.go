@@ -0,0 +1,233 @@
+package reporting_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test the NewCache constructor
+func TestNewCache(t *testing.T) {
+	_, err := rpt.NewCache("")
+	assert.Error(t, err)
+
+	dir := filepath.Join(t.TempDir(), "cache")
+	cache, err := rpt.NewCache(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, dir, cache.Dir)
+	assert.DirExists(t, dir)
+}
+
+// Test caching query results and reading them back
+func TestCacheGetPut(t *testing.T) {
+	cache, err := rpt.NewCache(t.TempDir())
+	require.NoError(t, err)
+
+	_, found, err := cache.Get("", "user1", 2023)
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	queryResults := commitsByYear("user1", map[int]int{2023: 10})
+	require.NoError(t, cache.Put("", "user1", 2023, queryResults["user1-2023"]))
+	cached, found, err := cache.Get(rpt.ProviderGitHub, "user1", 2023)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, queryResults["user1-2023"], cached)
+
+	// Ensure other providers and email addresses are kept apart
+	require.NoError(t, cache.Put(rpt.ProviderAzureDevOps, "user1@example.com", 2023, queryResults["user1-2023"]))
+	_, found, err = cache.Get(rpt.ProviderGitLab, "user1", 2023)
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	// Ensure entries older than the TTL are stale
+	cache.TTL = time.Hour
+	_, found, _ = cache.Get("", "user1", 2023)
+	assert.True(t, found)
+	old := time.Now().Add(-2 * time.Hour)
+	require.NoError(t, os.Chtimes(filepath.Join(cache.Dir, "github", "user1", "2023.json"), old, old))
+	_, found, _ = cache.Get("", "user1", 2023)
+	assert.False(t, found)
+}
+
+// Test listing, summarizing, and purging the cache
+func TestCacheEntries(t *testing.T) {
+	cache, err := rpt.NewCache(t.TempDir())
+	require.NoError(t, err)
+	queryResult := commitsByYear("user1", map[int]int{2023: 10})["user1-2023"]
+	require.NoError(t, cache.Put("", "user1", 2022, queryResult))
+	require.NoError(t, cache.Put("", "user1", 2023, queryResult))
+	require.NoError(t, cache.Put(rpt.ProviderAzureDevOps, "user2@example.com", 2023, queryResult))
+	require.NoError(t, os.WriteFile(filepath.Join(cache.Dir, "notes.txt"), []byte("not an entry"), 0600))
+
+	entries, err := cache.Entries()
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, rpt.ProviderAzureDevOps, entries[0].Provider)
+	assert.Equal(t, "user2@example.com", entries[0].User)
+	assert.Equal(t, "user1", entries[1].User)
+	assert.Equal(t, 2022, entries[1].Year)
+	assert.NotZero(t, entries[1].Size)
+
+	stats, err := cache.Stats()
+	require.NoError(t, err)
+	assert.Equal(t, 3, stats.Entries)
+	assert.Equal(t, 2, stats.Users)
+	assert.Equal(t, entries[0].Size*3, stats.Size)
+	assert.NotNil(t, stats.Oldest)
+
+	purged, err := cache.Purge(func(entry rpt.CacheEntry) bool { return entry.User == "user1" })
+	require.NoError(t, err)
+	assert.Len(t, purged, 2)
+	entries, err = cache.Entries()
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	stats, err = (&rpt.Cache{Dir: t.TempDir()}).Stats()
+	require.NoError(t, err)
+	assert.Zero(t, stats.Entries)
+	assert.Nil(t, stats.Oldest)
+}
+
+// A rangeProvider records the ranges it collects, with a commit on the first
+// day of each year from 2021
+type rangeProvider struct {
+	Ranges []rpt.DateRange
+	Err    error
+}
+
+// Collect records the range and returns a commit for each year in it from 2021
+func (p *rangeProvider) Collect(ctx context.Context, user string, dateRange rpt.DateRange) ([]rpt.Contribution, error) {
+	p.Ranges = append(p.Ranges, dateRange)
+	var contributions []rpt.Contribution
+	for year := max(dateRange.From.Year(), 2021); year < dateRange.To.Year(); year++ {
+		contributions = append(contributions, rpt.Contribution{Kind: rpt.ContributionCommit,
+			Repository: rpt.Repository{Name: "app"}, Date: rpt.YearRange(year, year).From, Count: year - 2000})
+	}
+	return contributions, p.Err
+}
+
+// skippingProvider is a rangeProvider that may stop at the first inactive year
+type skippingProvider struct {
+	rangeProvider
+	Skips bool
+}
+
+// SkipsInactiveYears reports whether the provider may skip the inactive years
+func (p *skippingProvider) SkipsInactiveYears() bool {
+	return p.Skips
+}
+
+// Test collecting only the years that aren't cached
+func TestCacheCollect(t *testing.T) {
+	cache, err := rpt.NewCache(t.TempDir())
+	require.NoError(t, err)
+	var lookups []bool
+	cache.OnLookup = func(hit bool) { lookups = append(lookups, hit) }
+	now := time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)
+	provider := &rangeProvider{}
+	collector := &rpt.ProviderCollector{Provider: provider, User: "user1", Range: rpt.YearRange(2019, 2024)}
+
+	// Ensure the first run collects and caches every past year, even those without contributions
+	queryResults, err := cache.Collect(context.Background(), "", collector, now)
+	require.NoError(t, err)
+	assert.Len(t, queryResults, 4)
+	assert.Equal(t, []rpt.DateRange{rpt.YearRange(2019, 2024)}, provider.Ranges)
+	assert.Equal(t, []bool{false}, lookups)
+	entries, err := cache.Entries()
+	require.NoError(t, err)
+	assert.Len(t, entries, 5)
+
+	// Ensure the next run collects only the current year, with the same results
+	provider.Ranges = nil
+	lookups = nil
+	cached, err := cache.Collect(context.Background(), "", collector, now)
+	require.NoError(t, err)
+	assert.Equal(t, queryResults, cached)
+	assert.Equal(t, []rpt.DateRange{rpt.YearRange(2024, 2024)}, provider.Ranges)
+	assert.Equal(t, []bool{true, true, true, true, true}, lookups)
+
+	// Ensure the collection restarts from the earliest year that isn't cached
+	_, err = cache.Purge(func(entry rpt.CacheEntry) bool { return entry.Year == 2021 })
+	require.NoError(t, err)
+	provider.Ranges = nil
+	_, err = cache.Collect(context.Background(), "", collector, now)
+	require.NoError(t, err)
+	assert.Equal(t, []rpt.DateRange{rpt.YearRange(2021, 2024)}, provider.Ranges)
+
+	// Ensure nothing is cached from a failed collection
+	_, err = cache.Purge(func(entry rpt.CacheEntry) bool { return true })
+	require.NoError(t, err)
+	provider.Err = errors.New("timeout")
+	queryResults, err = cache.Collect(context.Background(), "", collector, now)
+	assert.Error(t, err)
+	assert.Len(t, queryResults, 4)
+	entries, err = cache.Entries()
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+// Test caching only the years a provider skipping inactive years collected
+func TestCacheCollectSkipsInactiveYears(t *testing.T) {
+	cache, err := rpt.NewCache(t.TempDir())
+	require.NoError(t, err)
+	now := time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)
+	provider := &skippingProvider{Skips: true}
+	collector := &rpt.ProviderCollector{Provider: provider, User: "user1", Range: rpt.YearRange(2019, 2024)}
+
+	// Ensure the years before the first collected, which may have been skipped, aren't cached
+	_, err = cache.Collect(context.Background(), "", collector, now)
+	require.NoError(t, err)
+	entries, err := cache.Entries()
+	require.NoError(t, err)
+	var years []int
+	for _, entry := range entries {
+		years = append(years, entry.Year)
+	}
+	assert.Equal(t, []int{2021, 2022, 2023}, years)
+
+	// Ensure a provider that doesn't skip them caches them empty
+	provider.Skips = false
+	_, err = cache.Collect(context.Background(), "", collector, now)
+	require.NoError(t, err)
+	entries, err = cache.Entries()
+	require.NoError(t, err)
+	assert.Len(t, entries, 5)
+}
+
+// Test caching the same login on different instances apart
+func TestCacheCollectSources(t *testing.T) {
+	cache, err := rpt.NewCache(t.TempDir())
+	require.NoError(t, err)
+	now := time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)
+	public := rpt.Credential{Username: "user1"}
+	enterprise := rpt.Credential{Username: "user1", URL: "https://GHE.example.com/api/graphql"}
+	assert.Equal(t, "github", public.Source())
+	assert.Equal(t, "github", rpt.Credential{Username: "user1", URL: "https://api.github.com"}.Source())
+	assert.Equal(t, "github@ghe.example.com", enterprise.Source())
+	assert.Equal(t, "gitlab@gitlab.example.com",
+		rpt.Credential{Provider: "gitlab", URL: "https://gitlab.example.com"}.Source())
+
+	// Ensure each instance's years are cached, and collected, on their own
+	collector := &rpt.ProviderCollector{Provider: &rangeProvider{}, User: "user1", Range: rpt.YearRange(2022, 2024)}
+	_, err = cache.Collect(context.Background(), public.Source(), collector, now)
+	require.NoError(t, err)
+	provider := &rangeProvider{}
+	collector.Provider = provider
+	_, err = cache.Collect(context.Background(), enterprise.Source(), collector, now)
+	require.NoError(t, err)
+	assert.Equal(t, []rpt.DateRange{rpt.YearRange(2022, 2024)}, provider.Ranges)
+
+	entries, err := cache.Entries()
+	require.NoError(t, err)
+	require.Len(t, entries, 4)
+	assert.Equal(t, "github", entries[0].Provider)
+	assert.Equal(t, "github@ghe.example.com", entries[2].Provider)
+}
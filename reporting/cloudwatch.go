@@ -0,0 +1,107 @@
+package reporting
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A CloudWatch exporter puts the aggregated metrics into Amazon CloudWatch
+type CloudWatch struct {
+	// The CloudWatch namespace for the metrics
+	Namespace string
+	// The dimension names and values added to each metric
+	Dimensions map[string]string
+	// The AWS region, like us-west-2
+	Region string
+	// The monitoring endpoint (defaults to the regional endpoint)
+	Endpoint string
+	// The credentials used to sign requests
+	Credentials AWSCredentials
+	// The HTTP client used to send requests
+	Client *http.Client
+}
+
+// Constructs a new CloudWatch object
+// The namespace is the CloudWatch metrics namespace
+// The region is the AWS region to put the metrics into
+// The dimensions are name/value pairs added to each metric
+// The credentials are used to sign the requests
+func NewCloudWatch(namespace string, region string, dimensions map[string]string,
+	credentials AWSCredentials) (cloudWatch *CloudWatch, err error) {
+
+	if namespace == "" {
+		err = fmt.Errorf("the cloudwatch namespace cannot be blank")
+		return nil, err
+	}
+
+	if region == "" {
+		err = fmt.Errorf("the cloudwatch region cannot be blank")
+		return nil, err
+	}
+
+	return &CloudWatch{
+		Namespace:   namespace,
+		Dimensions:  dimensions,
+		Region:      region,
+		Endpoint:    "https://monitoring." + region + ".amazonaws.com/",
+		Credentials: credentials,
+		Client:      http.DefaultClient,
+	}, err
+}
+
+// Export puts the aggregated totals as CloudWatch metrics using the PutMetricData action
+func (c *CloudWatch) Export(queryResults map[string]QueryResult, aggregatedResults AggregatedResults) error {
+
+	form := url.Values{}
+	form.Set("Action", "PutMetricData")
+	form.Set("Version", "2010-08-01")
+	form.Set("Namespace", c.Namespace)
+
+	var metrics = []struct {
+		name  string
+		value int
+	}{
+		{"TotalCommitContributions", aggregatedResults.TotalCommitContributions},
+		{"TotalRepositories", aggregatedResults.TotalRepositories},
+		{"TotalOtherContributions", aggregatedResults.TotalOtherContributions},
+	}
+
+	timestamp := time.Unix(int64(aggregatedResults.Timestamp), 0).UTC().Format(time.RFC3339)
+	for index, metric := range metrics {
+		member := "MetricData.member." + strconv.Itoa(index+1) + "."
+		form.Set(member+"MetricName", metric.name)
+		form.Set(member+"Value", strconv.Itoa(metric.value))
+		form.Set(member+"Unit", "Count")
+		form.Set(member+"Timestamp", timestamp)
+		for dimensionIndex, name := range slices.Sorted(maps.Keys(c.Dimensions)) {
+			dimension := member + "Dimensions.member." + strconv.Itoa(dimensionIndex+1) + "."
+			form.Set(dimension+"Name", name)
+			form.Set(dimension+"Value", c.Dimensions[name])
+		}
+	}
+
+	body := form.Encode()
+	request, err := http.NewRequest(http.MethodPost, c.Endpoint, strings.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build the cloudwatch request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+	signAWSRequest(request, []byte(body), c.Credentials, c.Region, "monitoring", time.Now())
+
+	response, err := c.Client.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to put cloudwatch metrics: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode/100 != 2 {
+		return fmt.Errorf("failed to put cloudwatch metrics: cloudwatch returned %s", response.Status)
+	}
+	return nil
+}
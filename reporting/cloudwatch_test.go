@@ -0,0 +1,59 @@
+package reporting_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the NewCloudWatch constructor
+func TestNewCloudWatch(t *testing.T) {
+	_, err := rpt.NewCloudWatch("", "us-west-2", nil, rpt.AWSCredentials{})
+	assert.Error(t, err)
+
+	_, err = rpt.NewCloudWatch("GitHub", "", nil, rpt.AWSCredentials{})
+	assert.Error(t, err)
+
+	cloudWatch, err := rpt.NewCloudWatch("GitHub", "us-west-2", nil, rpt.AWSCredentials{})
+	assert.NoError(t, err)
+	assert.Equal(t, "https://monitoring.us-west-2.amazonaws.com/", cloudWatch.Endpoint)
+}
+
+// Test the CloudWatch Export method
+func TestCloudWatchExport(t *testing.T) {
+	var request *http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		request = r
+	}))
+	defer server.Close()
+
+	credentials := rpt.AWSCredentials{
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "secret",
+		SessionToken:    "session",
+	}
+	dimensions := map[string]string{"Team": "platform"}
+	cloudWatch, err := rpt.NewCloudWatch("GitHub/Contributions", "us-west-2", dimensions, credentials)
+	assert.NoError(t, err)
+	cloudWatch.Endpoint = server.URL
+
+	results := rpt.AggregatedResults{TotalCommitContributions: 42, TotalRepositories: 3}
+	err = cloudWatch.Export(nil, results)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "PutMetricData", request.PostForm.Get("Action"))
+	assert.Equal(t, "GitHub/Contributions", request.PostForm.Get("Namespace"))
+	assert.Equal(t, "TotalCommitContributions", request.PostForm.Get("MetricData.member.1.MetricName"))
+	assert.Equal(t, "42", request.PostForm.Get("MetricData.member.1.Value"))
+	assert.Equal(t, "Team", request.PostForm.Get("MetricData.member.2.Dimensions.member.1.Name"))
+	assert.Equal(t, "platform", request.PostForm.Get("MetricData.member.2.Dimensions.member.1.Value"))
+
+	// Ensure the request is signed with the credentials
+	assert.Contains(t, request.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/")
+	assert.Contains(t, request.Header.Get("Authorization"), "/us-west-2/monitoring/aws4_request")
+	assert.Equal(t, "session", request.Header.Get("X-Amz-Security-Token"))
+}
@@ -0,0 +1,156 @@
+package reporting
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// The most collaborators reported
+const maxCollaborators = 10
+
+// A ContributorSource counts the recent commits of each contributor to a
+// repository, for providers that can find a user's collaborators
+type ContributorSource interface {
+	Contributors(ctx context.Context, repository Repository) (map[string]int, error)
+}
+
+// A Collaborator is another contributor to the repositories the users contributed to most
+type Collaborator struct {
+	Login string `json:"login"`
+	// The collaborator's commits among the recent commits of the repositories
+	Commits int `json:"commits"`
+	// The names of the repositories the collaborator contributed to, sorted
+	Repositories []string `json:"repositories"`
+}
+
+// A repositoryContributorsQuery lists the authors of the latest commits on a
+// repository's default branch
+type repositoryContributorsQuery struct {
+	Repository struct {
+		DefaultBranchRef struct {
+			Target struct {
+				Commit struct {
+					History struct {
+						Nodes []struct {
+							Author struct {
+								User struct {
+									Login githubv4.String
+								}
+							}
+						}
+					} `graphql:"history(first: 100)"`
+				} `graphql:"... on Commit"`
+			}
+		}
+	} `graphql:"repository(owner: $owner, name: $name)"`
+}
+
+// Contributors counts the commits by each author among the latest 100 commits on
+// the repository's default branch. Commits by authors without a GitHub account are skipped.
+func (g *GitHub) Contributors(ctx context.Context, repository Repository) (map[string]int, error) {
+
+	owner, name, err := gitHubRepositoryPath(repository)
+	if err != nil {
+		return nil, err
+	}
+
+	var query repositoryContributorsQuery
+	err = g.Client.Query(ctx, &query, map[string]interface{}{
+		"owner": githubv4.String(owner),
+		"name":  githubv4.String(name),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query github for the contributors to %s/%s: %w", owner, name, err)
+	}
+
+	var commits = make(map[string]int)
+	for _, node := range query.Repository.DefaultBranchRef.Target.Commit.History.Nodes {
+		if login := string(node.Author.User.Login); login != "" {
+			commits[login]++
+		}
+	}
+	return commits, nil
+}
+
+// TopRepositories lists the repositories with the most contributions of any
+// kind across all users and years, up to the limit
+func TopRepositories(queryResults map[string]QueryResult, limit int) []Repository {
+
+	counts := repositoryCounts(queryResults)
+	var unique = make(map[string]Repository)
+	for _, queryResult := range queryResults {
+		for _, contribution := range queryResult.User.ContributionsCollection.RepositoryContributions() {
+			if _, found := unique[contribution.key()]; !found {
+				unique[contribution.key()] = contribution.repository()
+			}
+		}
+	}
+
+	var repositories = make([]Repository, 0, len(counts))
+	for _, repository := range unique {
+		repositories = append(repositories, repository)
+	}
+	slices.SortFunc(repositories, func(a, b Repository) int {
+		return cmp.Or(cmp.Compare(counts[b.key()], counts[a.key()]), cmp.Compare(a.Name, b.Name), cmp.Compare(a.URL, b.URL))
+	})
+	return repositories[:min(limit, len(repositories))]
+}
+
+// repositoryCounts counts the contributions of any kind to each repository
+// across all users and years, by its key
+func repositoryCounts(queryResults map[string]QueryResult) map[string]int {
+	var counts = make(map[string]int)
+	for _, queryResult := range queryResults {
+		for _, contribution := range queryResult.User.ContributionsCollection.RepositoryContributions() {
+			counts[contribution.key()] += int(contribution.Contributions.TotalCount)
+		}
+	}
+	return counts
+}
+
+// FindCollaborators finds the other contributors to the repositories, leaving out
+// the users themselves, with those sharing the most repositories first, then those
+// with the most commits. Repositories that can't be queried are skipped, and their
+// errors are returned with the collaborators found.
+func FindCollaborators(ctx context.Context, source ContributorSource, repositories []Repository, users []string) ([]Collaborator, error) {
+
+	var errs []error
+	var collaborators = make(map[string]*Collaborator)
+	for _, repository := range repositories {
+		contributors, err := source.Contributors(ctx, repository)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for login, commits := range contributors {
+			if slices.ContainsFunc(users, func(user string) bool { return strings.EqualFold(user, login) }) {
+				continue
+			}
+			if collaborators[login] == nil {
+				collaborators[login] = &Collaborator{Login: login}
+			}
+			collaborators[login].Commits += commits
+			collaborators[login].Repositories = append(collaborators[login].Repositories, repository.Name)
+		}
+	}
+
+	var sorted = make([]Collaborator, 0, len(collaborators))
+	for _, collaborator := range collaborators {
+		slices.Sort(collaborator.Repositories)
+		sorted = append(sorted, *collaborator)
+	}
+	slices.SortFunc(sorted, func(a, b Collaborator) int {
+		return cmp.Or(
+			cmp.Compare(len(b.Repositories), len(a.Repositories)),
+			cmp.Compare(b.Commits, a.Commits),
+			cmp.Compare(a.Login, b.Login),
+		)
+	})
+	return sorted[:min(maxCollaborators, len(sorted))], errors.Join(errs...)
+}
@@ -0,0 +1,81 @@
+package reporting_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// A fake contributor source with canned commit counts by repository name
+type fakeContributorSource map[string]map[string]int
+
+// Contributors returns the canned counts, or an error for an unknown repository
+func (f fakeContributorSource) Contributors(ctx context.Context, repository rpt.Repository) (map[string]int, error) {
+	contributors, found := f[repository.Name]
+	if !found {
+		return nil, fmt.Errorf("no contributors for %s", repository.Name)
+	}
+	return contributors, nil
+}
+
+// Test counting a repository's contributors with the GitHub provider
+func TestGitHubContributors(t *testing.T) {
+	client := &pagedGraphQLClient{Pages: []string{
+		`{"repository": {"defaultBranchRef": {"target": {"commit": {"history": {"nodes": [
+			{"author": {"user": {"login": "alice"}}},
+			{"author": {"user": {"login": "bob"}}},
+			{"author": {"user": {"login": "alice"}}},
+			{"author": {"user": null}}
+		]}}}}}}`,
+	}}
+	gitHub, err := rpt.NewGitHub(client)
+	require.NoError(t, err)
+
+	contributors, err := gitHub.Contributors(context.Background(),
+		rpt.Repository{Name: "api", URL: "https://github.com/team/api"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"alice": 2, "bob": 1}, contributors)
+	assert.Equal(t, githubv4.String("team"), client.Variables[0]["owner"])
+	assert.Equal(t, githubv4.String("api"), client.Variables[0]["name"])
+
+	// Ensure repositories from other providers are refused
+	_, err = gitHub.Contributors(context.Background(),
+		rpt.Repository{Name: "project", URL: "https://gitlab.com/group/subgroup/project"})
+	assert.ErrorContains(t, err, "is not a github repository")
+}
+
+// Test ranking the repositories with the most contributions
+func TestTopRepositories(t *testing.T) {
+	date := time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)
+	queryResults := rpt.QueryResultsFromContributions("alice", []rpt.Contribution{
+		{Kind: rpt.ContributionCommit, Repository: rpt.Repository{Name: "small", URL: "u1"}, Date: date, Count: 1},
+		{Kind: rpt.ContributionCommit, Repository: rpt.Repository{Name: "large", URL: "u2"}, Date: date, Count: 5},
+		{Kind: rpt.ContributionIssue, Repository: rpt.Repository{Name: "medium", URL: "u3"}, Date: date, Count: 3},
+	})
+
+	assert.Equal(t, []rpt.Repository{{Name: "large", URL: "u2"}, {Name: "medium", URL: "u3"}},
+		rpt.TopRepositories(queryResults, 2))
+	assert.Len(t, rpt.TopRepositories(queryResults, 10), 3)
+}
+
+// Test finding the users' collaborators across repositories
+func TestFindCollaborators(t *testing.T) {
+	source := fakeContributorSource{
+		"api": {"Alice": 40, "carol": 10, "dave": 30},
+		"web": {"alice": 5, "carol": 3},
+	}
+	repositories := []rpt.Repository{{Name: "api"}, {Name: "web"}, {Name: "private"}}
+
+	collaborators, err := rpt.FindCollaborators(context.Background(), source, repositories, []string{"alice"})
+	assert.ErrorContains(t, err, "no contributors for private")
+	assert.Equal(t, []rpt.Collaborator{
+		{Login: "carol", Commits: 13, Repositories: []string{"api", "web"}},
+		{Login: "dave", Commits: 30, Repositories: []string{"api"}},
+	}, collaborators)
+}
@@ -0,0 +1,182 @@
+package reporting
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// The providers a credential can collect contributions from
+const (
+	ProviderGitHub      = "github"
+	ProviderGitLab      = "gitlab"
+	ProviderBitbucket   = "bitbucket"
+	ProviderAzureDevOps = "azuredevops"
+	ProviderGerrit      = "gerrit"
+	ProviderLocalGit    = "git"
+	ProviderDemo        = "demo"
+)
+
+// A Collector collects an account's contributions as query results keyed
+// like user-year, like the GitHub Reporter or a ProviderCollector
+type Collector interface {
+	Collect() (map[string]QueryResult, error)
+}
+
+// MergeQueryResults copies the source results into the destination, adding the
+// contributions together when both hold the same user-year, like a user with
+// the same username on more than one provider
+func MergeQueryResults(destination map[string]QueryResult, source map[string]QueryResult) {
+	for userYear, queryResult := range source {
+		existing, found := destination[userYear]
+		if found {
+			existing.User.ContributionsCollection =
+				existing.User.ContributionsCollection.Add(queryResult.User.ContributionsCollection)
+			queryResult = existing
+		}
+		destination[userYear] = queryResult
+	}
+}
+
+// Add returns the sum of the contributions in both collections
+func (c ContributionsCollection) Add(other ContributionsCollection) ContributionsCollection {
+	sum := ContributionsCollection{
+		HasAnyContributions:                                c.HasAnyContributions || other.HasAnyContributions,
+		HasActivityInThePast:                               c.HasActivityInThePast || other.HasActivityInThePast,
+		RestrictedContributionsCount:                       c.RestrictedContributionsCount + other.RestrictedContributionsCount,
+		TotalCommitContributions:                           c.TotalCommitContributions + other.TotalCommitContributions,
+		TotalIssueContributions:                            c.TotalIssueContributions + other.TotalIssueContributions,
+		TotalPullRequestContributions:                      c.TotalPullRequestContributions + other.TotalPullRequestContributions,
+		TotalPullRequestReviewContributions:                c.TotalPullRequestReviewContributions + other.TotalPullRequestReviewContributions,
+		TotalRepositoriesWithContributedIssues:             c.TotalRepositoriesWithContributedIssues + other.TotalRepositoriesWithContributedIssues,
+		TotalRepositoriesWithContributedCommits:            c.TotalRepositoriesWithContributedCommits + other.TotalRepositoriesWithContributedCommits,
+		TotalRepositoriesWithContributedPullRequests:       c.TotalRepositoriesWithContributedPullRequests + other.TotalRepositoriesWithContributedPullRequests,
+		TotalRepositoriesWithContributedPullRequestReviews: c.TotalRepositoriesWithContributedPullRequestReviews + other.TotalRepositoriesWithContributedPullRequestReviews,
+		CommitContributionsByRepository:                    slices.Concat(c.CommitContributionsByRepository, other.CommitContributionsByRepository),
+		IssueContributionsByRepository:                     slices.Concat(c.IssueContributionsByRepository, other.IssueContributionsByRepository),
+		PullRequestContributionsByRepository:               slices.Concat(c.PullRequestContributionsByRepository, other.PullRequestContributionsByRepository),
+		PullRequestReviewContributionsByRepository:         slices.Concat(c.PullRequestReviewContributionsByRepository, other.PullRequestReviewContributionsByRepository),
+	}
+	sum.ContributionCalendar.TotalContributions = c.ContributionCalendar.TotalContributions + other.ContributionCalendar.TotalContributions
+	return sum
+}
+
+// A repositoryTally counts a single kind of contribution by repository,
+// along with contributions reported without a repository
+type repositoryTally struct {
+	counts       map[string]int
+	repositories map[string]Repository
+	unattributed int
+	// The most repositories a provider reported for a query that listed fewer
+	reported int
+}
+
+// add counts contributions to the repository, telling repositories apart by their
+// URL like Aggregate, so alice/utils and bob/utils are counted separately
+func (t *repositoryTally) add(repository Repository, count int) {
+	if repository.Name == "" {
+		t.unattributed += count
+		return
+	}
+	if t.counts == nil {
+		t.counts = make(map[string]int)
+		t.repositories = make(map[string]Repository)
+	}
+	key := repository.key()
+	t.counts[key] += count
+	if _, found := t.repositories[key]; !found {
+		t.repositories[key] = repository
+	}
+}
+
+// count returns the count of repositories, at least as many as a provider reported
+func (t repositoryTally) count() int {
+	return max(len(t.counts), t.reported)
+}
+
+// total returns the count of contributions, with or without a repository
+func (t repositoryTally) total() (total int) {
+	total = t.unattributed
+	for _, count := range t.counts {
+		total += count
+	}
+	return total
+}
+
+// contributions returns the tally as repository contributions, sorted by name
+func (t repositoryTally) contributions() []RepositoryContribution {
+	var contributions = make([]RepositoryContribution, 0, len(t.counts))
+	for _, key := range slices.Sorted(maps.Keys(t.counts)) {
+		var contribution RepositoryContribution
+		contribution.setRepository(t.repositories[key])
+		contribution.Contributions.TotalCount = githubv4.Int(t.counts[key])
+		contributions = append(contributions, contribution)
+	}
+	slices.SortStableFunc(contributions, func(a, b RepositoryContribution) int {
+		return strings.Compare(string(a.Repository.Name), string(b.Repository.Name))
+	})
+	return contributions
+}
+
+// A contributionTally counts a user's contributions of every kind over a year
+type contributionTally struct {
+	commits            repositoryTally
+	issues             repositoryTally
+	pullRequests       repositoryTally
+	pullRequestReviews repositoryTally
+	restricted         int
+	calendarTotal      int
+}
+
+// add counts a single contribution
+func (t *contributionTally) add(contribution Contribution) {
+	var tally *repositoryTally
+	switch contribution.Kind {
+	case ContributionCommit:
+		tally = &t.commits
+	case ContributionIssue:
+		tally = &t.issues
+	case ContributionPullRequest:
+		tally = &t.pullRequests
+	case ContributionPullRequestReview:
+		tally = &t.pullRequestReviews
+	case ContributionRestricted:
+		t.restricted += contribution.Count
+		return
+	case ContributionCalendarTotal:
+		t.calendarTotal += contribution.Count
+		return
+	default:
+		return
+	}
+	tally.add(contribution.Repository, contribution.Count)
+	tally.reported = max(tally.reported, contribution.Repositories)
+}
+
+// queryResult returns the tally as a query result for the user
+func (t contributionTally) queryResult(user string) QueryResult {
+	total := t.commits.total() + t.issues.total() + t.pullRequests.total() + t.pullRequestReviews.total() + t.restricted
+
+	var queryResult QueryResult
+	queryResult.User.Login = githubv4.String(user)
+	queryResult.User.ContributionsCollection = ContributionsCollection{
+		HasAnyContributions:                                total > 0,
+		RestrictedContributionsCount:                       githubv4.Int(t.restricted),
+		TotalCommitContributions:                           githubv4.Int(t.commits.total()),
+		TotalIssueContributions:                            githubv4.Int(t.issues.total()),
+		TotalPullRequestContributions:                      githubv4.Int(t.pullRequests.total()),
+		TotalPullRequestReviewContributions:                githubv4.Int(t.pullRequestReviews.total()),
+		TotalRepositoriesWithContributedCommits:            githubv4.Int(t.commits.count()),
+		TotalRepositoriesWithContributedIssues:             githubv4.Int(t.issues.count()),
+		TotalRepositoriesWithContributedPullRequests:       githubv4.Int(t.pullRequests.count()),
+		TotalRepositoriesWithContributedPullRequestReviews: githubv4.Int(t.pullRequestReviews.count()),
+		CommitContributionsByRepository:                    t.commits.contributions(),
+		IssueContributionsByRepository:                     t.issues.contributions(),
+		PullRequestContributionsByRepository:               t.pullRequests.contributions(),
+		PullRequestReviewContributionsByRepository:         t.pullRequestReviews.contributions(),
+	}
+	queryResult.User.ContributionsCollection.ContributionCalendar.TotalContributions = githubv4.Int(t.calendarTotal)
+	return queryResult
+}
@@ -0,0 +1,67 @@
+package reporting_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test merging query results from more than one provider
+func TestMergeQueryResults(t *testing.T) {
+	queryResults, err := loadQueryResultsMap("single_user_single_year.json")
+	assert.NoError(t, err)
+
+	merged := make(map[string]rpt.QueryResult)
+	rpt.MergeQueryResults(merged, queryResults)
+	assert.Equal(t, queryResults, merged)
+
+	// Ensure the same user-year from a second provider is added, not replaced
+	rpt.MergeQueryResults(merged, queryResults)
+	assert.Len(t, merged, 1)
+	for userYear, queryResult := range merged {
+		single := queryResults[userYear].User.ContributionsCollection
+		double := queryResult.User.ContributionsCollection
+		assert.Equal(t, 2*single.TotalCommitContributions, double.TotalCommitContributions)
+		assert.Equal(t, 2*single.TotalIssueContributions, double.TotalIssueContributions)
+		assert.Equal(t, single.TotalRepositories(), double.TotalRepositories())
+		assert.Len(t, double.CommitContributionsByRepository, 2*len(single.CommitContributionsByRepository))
+	}
+}
+
+// A fixedProvider returns the same contributions for every collection
+type fixedProvider []rpt.Contribution
+
+// Collect returns the contributions
+func (p fixedProvider) Collect(ctx context.Context, user string, dateRange rpt.DateRange) ([]rpt.Contribution, error) {
+	return p, nil
+}
+
+// Test collecting repositories with the same name and different owners as separate repositories
+func TestProviderCollectorSameName(t *testing.T) {
+	date := time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)
+	alice := rpt.Repository{Name: "utils", URL: "https://github.com/alice/utils"}
+	bob := rpt.Repository{Name: "utils", URL: "https://github.com/bob/utils"}
+	collector := &rpt.ProviderCollector{User: "user1", Range: rpt.YearRange(2023, 2023), Provider: fixedProvider{
+		{Kind: rpt.ContributionCommit, Repository: alice, Date: date, Count: 3},
+		{Kind: rpt.ContributionCommit, Repository: bob, Date: date, Count: 4},
+		{Kind: rpt.ContributionCommit, Repository: alice, Date: date, Count: 1},
+	}}
+	queryResults, err := collector.Collect(context.Background())
+	require.NoError(t, err)
+
+	collection := queryResults["user1-2023"].User.ContributionsCollection
+	assert.Equal(t, 2, int(collection.TotalRepositoriesWithContributedCommits))
+	assert.Equal(t, 2, collection.TotalRepositories())
+
+	aggregatedResults, err := (&rpt.Reporter{}).Aggregate(context.Background(), queryResults)
+	require.NoError(t, err)
+	assert.Equal(t, 2, aggregatedResults.TotalRepositories)
+	assert.Equal(t, []rpt.RepositoryTotals{
+		{Repository: alice, Commits: 4, Contributions: 4},
+		{Repository: bob, Commits: 4, Contributions: 4},
+	}, aggregatedResults.Repositories)
+}
@@ -0,0 +1,186 @@
+package reporting
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParsePeriod parses a period as a year, like 2023, a month, like 2023-06, or an
+// inclusive range of dates, months, or years, like 2023-01-01..2023-06-30 or 2016..2019
+func ParsePeriod(period string) (DateRange, error) {
+
+	if from, to, found := strings.Cut(period, ".."); found {
+		fromRange, err := parsePeriodBound(from)
+		if err != nil {
+			return DateRange{}, fmt.Errorf("failed to parse the period start %q: %w", from, err)
+		}
+		toRange, err := parsePeriodBound(to)
+		if err != nil {
+			return DateRange{}, fmt.Errorf("failed to parse the period end %q: %w", to, err)
+		}
+		if !toRange.To.After(fromRange.From) || toRange.From.Before(fromRange.From) {
+			return DateRange{}, fmt.Errorf("the period %s ends before it starts", period)
+		}
+		return DateRange{From: fromRange.From, To: toRange.To}, nil
+	}
+
+	dateRange, err := parsePeriodBound(period)
+	if err != nil || len(period) == len(time.DateOnly) {
+		return DateRange{}, fmt.Errorf("the period %q must be a year, a month like 2023-06, "+
+			"or a range like 2023-01-01..2023-06-30 or 2016..2019", period)
+	}
+	return dateRange, nil
+}
+
+// ParseDateRange parses the bounds of a range, each a month, like 2021-07, a date, or
+// a year, into the range from the start of the first through the end of the last.
+// A blank bound is left zero, for the caller's default.
+func ParseDateRange(from string, to string) (dateRange DateRange, err error) {
+
+	if from != "" {
+		fromRange, err := parsePeriodBound(from)
+		if err != nil {
+			return DateRange{}, fmt.Errorf("failed to parse the range start %q: %w", from, err)
+		}
+		dateRange.From = fromRange.From
+	}
+	if to != "" {
+		toRange, err := parsePeriodBound(to)
+		if err != nil {
+			return DateRange{}, fmt.Errorf("failed to parse the range end %q: %w", to, err)
+		}
+		dateRange.To = toRange.To
+	}
+	if !dateRange.From.IsZero() && !dateRange.To.IsZero() && !dateRange.To.After(dateRange.From) {
+		return DateRange{}, fmt.Errorf("the range %s..%s ends before it starts", from, to)
+	}
+	return dateRange, nil
+}
+
+// parsePeriodBound parses a date, a month, or a year as the range it covers
+func parsePeriodBound(bound string) (DateRange, error) {
+
+	if date, err := time.Parse(time.DateOnly, bound); err == nil {
+		return DateRange{From: date, To: date.AddDate(0, 0, 1)}, nil
+	}
+	if month, err := time.Parse("2006-01", bound); err == nil {
+		return DateRange{From: month, To: month.AddDate(0, 1, 0)}, nil
+	}
+	year, err := strconv.Atoi(bound)
+	if err != nil || year < 1 {
+		return DateRange{}, fmt.Errorf("%q is not a date, a month, or a year", bound)
+	}
+	return YearRange(year, year), nil
+}
+
+// String returns the range as inclusive dates
+func (d DateRange) String() string {
+	return d.From.Format(time.DateOnly) + ".." + d.To.AddDate(0, 0, -1).Format(time.DateOnly)
+}
+
+// A PeriodComparison holds the change in each metric, and in the contributions
+// to each repository, from period A to period B
+type PeriodComparison struct {
+	A            PeriodTotals       `json:"a"`
+	B            PeriodTotals       `json:"b"`
+	Change       Totals             `json:"change"`
+	Repositories []RepositoryChange `json:"repositories"`
+}
+
+// PeriodTotals holds the totals of a single period
+type PeriodTotals struct {
+	Period string `json:"period"`
+	Totals
+}
+
+// A RepositoryChange holds the contributions to a repository in each period
+type RepositoryChange struct {
+	Repository
+	A      int `json:"a"`
+	B      int `json:"b"`
+	Change int `json:"change"`
+}
+
+// ComparePeriods compares the query results collected for two periods
+func ComparePeriods(periodA DateRange, queryResultsA map[string]QueryResult,
+	periodB DateRange, queryResultsB map[string]QueryResult) PeriodComparison {
+	return (&Reporter{}).ComparePeriods(periodA, queryResultsA, periodB, queryResultsB)
+}
+
+// ComparePeriods compares the query results collected for two periods like the
+// ComparePeriods function, with the totals counted like the Reporter's
+func (r *Reporter) ComparePeriods(periodA DateRange, queryResultsA map[string]QueryResult,
+	periodB DateRange, queryResultsB map[string]QueryResult) PeriodComparison {
+
+	totalsA, totalsB := r.totals(queryResultsA), r.totals(queryResultsB)
+	comparison := PeriodComparison{
+		A:      PeriodTotals{Period: periodA.String(), Totals: totalsA},
+		B:      PeriodTotals{Period: periodB.String(), Totals: totalsB},
+		Change: totalsB.Sub(totalsA),
+	}
+
+	// Count every kind of contribution to each repository in each period
+	var changes = make(map[string]*RepositoryChange)
+	count := func(queryResults map[string]QueryResult, period func(change *RepositoryChange) *int) {
+		for _, queryResult := range queryResults {
+			for _, contribution := range queryResult.User.ContributionsCollection.RepositoryContributions() {
+				key := contribution.key()
+				if changes[key] == nil {
+					changes[key] = &RepositoryChange{
+						Repository: contribution.repository(),
+					}
+				}
+				*period(changes[key]) += int(contribution.Contributions.TotalCount)
+			}
+		}
+	}
+	count(queryResultsA, func(change *RepositoryChange) *int { return &change.A })
+	count(queryResultsB, func(change *RepositoryChange) *int { return &change.B })
+
+	// List the biggest changes first
+	for _, key := range slices.Sorted(maps.Keys(changes)) {
+		change := changes[key]
+		change.Change = change.B - change.A
+		comparison.Repositories = append(comparison.Repositories, *change)
+	}
+	slices.SortStableFunc(comparison.Repositories, func(a, b RepositoryChange) int {
+		return cmp.Compare(abs(b.Change), abs(a.Change))
+	})
+	return comparison
+}
+
+// Text renders the comparison as a plain text table
+func (p PeriodComparison) Text() string {
+
+	var builder strings.Builder
+	row := func(name string, a int, b int) {
+		fmt.Fprintf(&builder, "%-32s %10d %10d %8s\n", name, a, b, formatDelta(b-a))
+	}
+
+	fmt.Fprintf(&builder, "%-32s %10s %10s %8s\n", "", "A", "B", "Change")
+	row("Commits", p.A.TotalCommitContributions, p.B.TotalCommitContributions)
+	row("Repositories", p.A.TotalRepositories, p.B.TotalRepositories)
+	row("Other contributions", p.A.TotalOtherContributions, p.B.TotalOtherContributions)
+	fmt.Fprintf(&builder, "\nA: %s\nB: %s\n", p.A.Period, p.B.Period)
+
+	if len(p.Repositories) > 0 {
+		fmt.Fprintf(&builder, "\n%-32s %10s %10s %8s\n", "Repository", "A", "B", "Change")
+		for _, repository := range p.Repositories {
+			row(repository.Name, repository.A, repository.B)
+		}
+	}
+	return builder.String()
+}
+
+// abs returns the absolute value of an integer
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
@@ -0,0 +1,108 @@
+package reporting_test
+
+import (
+	"testing"
+	"time"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test the ParsePeriod function
+func TestParsePeriod(t *testing.T) {
+	tests := []struct {
+		period  string
+		from    string
+		to      string
+		wantErr bool
+	}{
+		{period: "2023", from: "2023-01-01", to: "2024-01-01"},
+		{period: "2023-06", from: "2023-06-01", to: "2023-07-01"},
+		{period: "2023-01-01..2023-06-30", from: "2023-01-01", to: "2023-07-01"},
+		{period: "2016..2019", from: "2016-01-01", to: "2020-01-01"},
+		{period: "2016-03..2019-06", from: "2016-03-01", to: "2019-07-01"},
+		{period: "2023-06-30..2023-01-01", wantErr: true},
+		{period: "2019..2016", wantErr: true},
+		{period: "2023-01-01", wantErr: true},
+		{period: "2023-01-01..soon", wantErr: true},
+		{period: "", wantErr: true},
+		{period: "last year", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.period, func(t *testing.T) {
+			dateRange, err := rpt.ParsePeriod(test.period)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.from, dateRange.From.Format(time.DateOnly))
+			assert.Equal(t, test.to, dateRange.To.Format(time.DateOnly))
+		})
+	}
+
+	dateRange, _ := rpt.ParsePeriod("2023-06")
+	assert.Equal(t, "2023-06-01..2023-06-30", dateRange.String())
+}
+
+// Test parsing the bounds of a range of months or dates
+func TestParseDateRange(t *testing.T) {
+	dateRange, err := rpt.ParseDateRange("2021-07", "2022-06")
+	require.NoError(t, err)
+	assert.Equal(t, "2021-07-01..2022-06-30", dateRange.String())
+
+	dateRange, err = rpt.ParseDateRange("2021-07-15", "2021")
+	require.NoError(t, err)
+	assert.Equal(t, "2021-07-15..2021-12-31", dateRange.String())
+
+	// Ensure a blank bound is left zero
+	dateRange, err = rpt.ParseDateRange("", "2022-06")
+	require.NoError(t, err)
+	assert.True(t, dateRange.From.IsZero())
+	assert.Equal(t, "2022-07-01", dateRange.To.Format(time.DateOnly))
+	dateRange, err = rpt.ParseDateRange("2021-07", "")
+	require.NoError(t, err)
+	assert.True(t, dateRange.To.IsZero())
+
+	// Ensure a backwards range or an unknown bound is an error
+	_, err = rpt.ParseDateRange("2022-06", "2021-07")
+	assert.Error(t, err)
+	_, err = rpt.ParseDateRange("July", "")
+	assert.Error(t, err)
+}
+
+// Test comparing the contributions of two periods
+func TestComparePeriods(t *testing.T) {
+	date := time.Date(2022, time.May, 1, 0, 0, 0, 0, time.UTC)
+	app := rpt.Repository{Name: "app", URL: "https://example.com/app"}
+	lib := rpt.Repository{Name: "lib", URL: "https://example.com/lib"}
+	resultsA := rpt.QueryResultsFromContributions("user1", []rpt.Contribution{
+		{Kind: rpt.ContributionCommit, Repository: app, Date: date, Count: 10},
+		{Kind: rpt.ContributionIssue, Repository: lib, Date: date, Count: 2},
+	})
+	resultsB := rpt.QueryResultsFromContributions("user1", []rpt.Contribution{
+		{Kind: rpt.ContributionCommit, Repository: app, Date: date.AddDate(1, 0, 0), Count: 12},
+		{Kind: rpt.ContributionPullRequest, Repository: lib, Date: date.AddDate(1, 0, 0), Count: 7},
+		{Kind: rpt.ContributionPullRequestReview, Repository: lib, Date: date.AddDate(1, 0, 0), Count: 1},
+	})
+
+	comparison := rpt.ComparePeriods(rpt.YearRange(2022, 2022), resultsA, rpt.YearRange(2023, 2023), resultsB)
+
+	assert.Equal(t, "2022-01-01..2022-12-31", comparison.A.Period)
+	assert.Equal(t, 10, comparison.A.TotalCommitContributions)
+	assert.Equal(t, 8, comparison.B.TotalOtherContributions)
+	assert.Equal(t, rpt.Totals{TotalCommitContributions: 2, TotalOtherContributions: 6}, comparison.Change)
+
+	// Ensure the repositories with the biggest changes come first
+	assert.Equal(t, []rpt.RepositoryChange{
+		{Repository: lib, A: 2, B: 8, Change: 6},
+		{Repository: app, A: 10, B: 12, Change: 2},
+	}, comparison.Repositories)
+
+	text := comparison.Text()
+	assert.Contains(t, text, "Commits")
+	assert.Contains(t, text, "+2")
+	assert.Contains(t, text, "B: 2023-01-01..2023-12-31")
+}
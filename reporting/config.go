@@ -0,0 +1,55 @@
+package reporting
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultConfigPath returns the path of the config file read when none is given,
+// config.yaml in the ghcontributions directory of the user's config directory,
+// like ~/.config/ghcontributions/config.yaml on Linux
+func DefaultConfigPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find the config directory: %w", err)
+	}
+	return filepath.Join(dir, MetricsNamespace, "config.yaml"), nil
+}
+
+// ParseConfig parses a YAML config file mapping option names, without their
+// dash, to values, like credentials: gh-tokens.json or concurrency: 8. A list
+// is joined with commas, for the options taking comma separated values.
+func ParseConfig(data []byte) (map[string]string, error) {
+
+	var config map[string]any
+	err := yaml.Unmarshal(data, &config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the config file: %w", err)
+	}
+
+	var values = make(map[string]string, len(config))
+	for name, value := range config {
+		switch value := value.(type) {
+		case nil:
+			values[name] = ""
+		case []any:
+			var items []string
+			for _, item := range value {
+				if _, isMap := item.(map[string]any); isMap {
+					return nil, fmt.Errorf("the config option %s must list plain values", name)
+				}
+				items = append(items, fmt.Sprint(item))
+			}
+			values[name] = strings.Join(items, ",")
+		case map[string]any:
+			return nil, fmt.Errorf("the config option %s must be a value or a list", name)
+		default:
+			values[name] = fmt.Sprint(value)
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,35 @@
+package reporting_test
+
+import (
+	"testing"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test parsing the option values of a config file
+func TestParseConfig(t *testing.T) {
+	values, err := rpt.ParseConfig([]byte(`
+credentials: "keyring:"
+firstyear: 2015
+include-restricted: true
+interval: 1h30m
+users: [alice, bob]
+post-report:
+`))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"credentials":        "keyring:",
+		"firstyear":          "2015",
+		"include-restricted": "true",
+		"interval":           "1h30m",
+		"users":              "alice,bob",
+		"post-report":        "",
+	}, values)
+
+	_, err = rpt.ParseConfig([]byte("headers:\n  X-Team: platform\n"))
+	assert.Error(t, err)
+	_, err = rpt.ParseConfig([]byte("credentials: [unclosed"))
+	assert.Error(t, err)
+}
@@ -1,7 +1,19 @@
 package reporting
 
-// The interval used to poll the Github GraphQL API for contributions
-const PollingIntervalInMinutes = 2
+import "time"
+
+// The default interval between the collections of the daemon
+const DefaultPollingInterval = time.Hour
+
+// The most repositories GitHub lists for each kind of contribution in a query,
+// as the maxRepositories of the contributions by repository
+const MaxRepositories = 100
 
 // The default first contribution year
 const DefaultFirstContributionYear = 2000
+
+// The namespace prefixed to all exported metric names
+const MetricsNamespace = "ghcontributions"
+
+// The default job label used when pushing metrics to a Prometheus Pushgateway
+const DefaultPushgatewayJob = "ghcontributions"
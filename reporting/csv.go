@@ -0,0 +1,47 @@
+package reporting
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+)
+
+// WriteCSV writes the totals as CSV, with a row for all users and years,
+// followed by a row for each user, a row for each year, and a row for each
+// quarter or month when the report has them
+func (a AggregatedResults) WriteCSV(w io.Writer) error {
+
+	writer := csv.NewWriter(w)
+	row := func(scope string, name string, totals Totals) []string {
+		return []string{
+			scope,
+			name,
+			strconv.Itoa(totals.TotalCommitContributions),
+			strconv.Itoa(totals.TotalRepositories),
+			strconv.Itoa(totals.TotalOtherContributions),
+		}
+	}
+
+	rows := [][]string{
+		{"scope", "name", "totalCommitContributions", "totalRepositories", "totalOtherContributions"},
+		row("total", "", a.Totals()),
+	}
+	for _, user := range slices.Sorted(maps.Keys(a.ByUser)) {
+		rows = append(rows, row("user", user, a.ByUser[user]))
+	}
+	for _, year := range slices.Sorted(maps.Keys(a.ByYear)) {
+		rows = append(rows, row("year", a.YearLabel(year), a.ByYear[year]))
+	}
+	for _, period := range slices.Sorted(maps.Keys(a.ByPeriod)) {
+		rows = append(rows, row(a.Granularity, period, a.ByPeriod[period]))
+	}
+
+	err := writer.WriteAll(rows)
+	if err != nil {
+		return fmt.Errorf("failed to write the csv: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package reporting_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test writing the totals as CSV
+func TestWriteCSV(t *testing.T) {
+	queryResults := commitsByYear("user1", map[int]int{2022: 10, 2023: 20})
+	rpt.MergeQueryResults(queryResults, commitsByYear("user,2", map[int]int{2023: 5}))
+	reporter := &rpt.Reporter{}
+	results, err := reporter.Aggregate(context.Background(), queryResults)
+	require.NoError(t, err)
+
+	var csv bytes.Buffer
+	require.NoError(t, results.WriteCSV(&csv))
+	assert.Equal(t, "scope,name,totalCommitContributions,totalRepositories,totalOtherContributions\n"+
+		"total,,35,1,0\n"+
+		"user,\"user,2\",5,1,0\n"+
+		"user,user1,30,1,0\n"+
+		"year,2022,10,1,0\n"+
+		"year,2023,25,1,0\n", csv.String())
+
+	// Ensure results without users or years still have the totals
+	csv.Reset()
+	require.NoError(t, rpt.AggregatedResults{TotalCommitContributions: 3}.WriteCSV(&csv))
+	assert.Equal(t, "scope,name,totalCommitContributions,totalRepositories,totalOtherContributions\n"+
+		"total,,3,0,0\n", csv.String())
+}
@@ -0,0 +1,81 @@
+package reporting
+
+import (
+	"context"
+	"hash/fnv"
+	"math/rand/v2"
+	"time"
+)
+
+// The usernames of the demo credentials
+var DemoUsers = []string{"demo-ada", "demo-grace", "demo-linus"}
+
+// The repositories the demo users contribute to
+var demoRepositories = []string{
+	"demo-org/api", "demo-org/web", "demo-org/cli", "demo-org/docs", "demo-org/infra", "demo-org/mobile",
+}
+
+// The number of years of demo activity
+const demoYears = 5
+
+// A Demo provider generates plausible contributions without any API, for
+// screenshots, documentation, and trying the tool before creating tokens.
+// The same seed and user always generate the same contributions.
+type Demo struct {
+	Seed uint64
+}
+
+// DemoCredentials returns credentials for the demo users with the demo provider
+func DemoCredentials() Credentials {
+	var credentials Credentials
+	for _, user := range DemoUsers {
+		credentials = append(credentials, Credential{Username: user, Provider: ProviderDemo})
+	}
+	return credentials
+}
+
+// Collect generates the user's contributions over the last few years of the range,
+// with each user favoring a few repositories and growing a little each year
+func (d *Demo) Collect(ctx context.Context, user string, dateRange DateRange) ([]Contribution, error) {
+
+	hash := fnv.New64a()
+	hash.Write([]byte(user))
+	random := rand.New(rand.NewPCG(d.Seed, hash.Sum64()))
+
+	// Each user has a pace, and repositories they work on most
+	pace := 50 + random.IntN(150)
+	weights := make([]int, len(demoRepositories))
+	for index := range weights {
+		weights[index] = random.IntN(4) * random.IntN(4)
+	}
+
+	var contributions []Contribution
+	lastYear := dateRange.To.Add(-time.Second).Year()
+	for year := max(dateRange.From.Year(), lastYear-demoYears+1); year <= lastYear; year++ {
+		yearRange := YearRange(year, year)
+		from, to := max(yearRange.From.Unix(), dateRange.From.Unix()), min(yearRange.To.Unix(), dateRange.To.Unix())
+		growth := 1 + float64(year-lastYear+demoYears)/10
+		for index, name := range demoRepositories {
+			if weights[index] == 0 {
+				continue
+			}
+			repository := Repository{Name: name, URL: "https://github.com/" + name}
+			for _, kind := range []ContributionKind{ContributionCommit, ContributionIssue, ContributionPullRequest, ContributionPullRequestReview} {
+				count := int(float64(pace*weights[index]) * growth * (0.5 + random.Float64()) / 10)
+				if kind != ContributionCommit {
+					count /= 4
+				}
+				if count == 0 {
+					continue
+				}
+				contributions = append(contributions, Contribution{
+					Kind:       kind,
+					Repository: repository,
+					Date:       time.Unix(from+random.Int64N(to-from), 0).UTC(),
+					Count:      count,
+				})
+			}
+		}
+	}
+	return contributions, nil
+}
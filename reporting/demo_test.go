@@ -0,0 +1,59 @@
+package reporting_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test generating demo contributions
+func TestDemoCollect(t *testing.T) {
+	demo := &rpt.Demo{}
+	dateRange := rpt.YearRange(2000, 2024)
+
+	contributions, err := demo.Collect(context.Background(), "demo-ada", dateRange)
+	require.NoError(t, err)
+	require.NotEmpty(t, contributions)
+
+	// Ensure the contributions cover the last five years of the range
+	var years = make(map[int]bool)
+	for _, contribution := range contributions {
+		assert.True(t, dateRange.Contains(contribution.Date))
+		assert.Positive(t, contribution.Count)
+		years[contribution.Date.Year()] = true
+	}
+	assert.Equal(t, map[int]bool{2020: true, 2021: true, 2022: true, 2023: true, 2024: true}, years)
+
+	// Ensure the same user and seed generate the same contributions, and others differ
+	again, err := demo.Collect(context.Background(), "demo-ada", dateRange)
+	require.NoError(t, err)
+	assert.Equal(t, contributions, again)
+	other, err := demo.Collect(context.Background(), "demo-grace", dateRange)
+	require.NoError(t, err)
+	assert.NotEqual(t, contributions, other)
+
+	// Ensure a short range is respected
+	quarter := rpt.DateRange{
+		From: time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC),
+	}
+	contributions, err = demo.Collect(context.Background(), "demo-ada", quarter)
+	require.NoError(t, err)
+	for _, contribution := range contributions {
+		assert.True(t, quarter.Contains(contribution.Date))
+	}
+}
+
+// Test the demo credentials
+func TestDemoCredentials(t *testing.T) {
+	credentials := rpt.DemoCredentials()
+	assert.Len(t, credentials, len(rpt.DemoUsers))
+	for _, credential := range credentials {
+		assert.Equal(t, rpt.ProviderDemo, credential.Provider)
+		assert.Empty(t, credential.Token)
+	}
+}
@@ -0,0 +1,83 @@
+package reporting
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// The maximum number of fields Discord allows in a single embed
+const discordMaxEmbedFields = 25
+
+// The accent color of the Discord embed, GitHub's contribution green
+const discordEmbedColor = 0x40c463
+
+// A Discord publisher posts an embed summarizing the results to a Discord webhook
+type Discord struct {
+	// The webhook URL
+	WebhookURL string
+	// The results of the previous snapshot, used to report deltas
+	Previous *AggregatedResults
+	// The HTTP client used to post messages
+	Client *http.Client
+}
+
+// Constructs a new Discord object
+// The webhookURL is the Discord channel webhook URL
+func NewDiscord(webhookURL string) (discord *Discord, err error) {
+
+	if webhookURL == "" {
+		err = fmt.Errorf("the discord webhook URL cannot be blank")
+		return nil, err
+	}
+
+	return &Discord{
+		WebhookURL: webhookURL,
+		Client:     http.DefaultClient,
+	}, err
+}
+
+// Export posts the summary embed with a field per user to the webhook
+func (d *Discord) Export(queryResults map[string]QueryResult, aggregatedResults AggregatedResults) error {
+	message := d.message(Summarize(aggregatedResults, d.Previous), aggregatedResults.Timestamp)
+	return postJSON(d.Client, d.WebhookURL, message, "discord")
+}
+
+// message builds a webhook message with a single rich embed
+func (d *Discord) message(summary Summary, timestamp int) map[string]any {
+
+	commits, repositories, other := metricDeltas(summary.Delta)
+	fields := []map[string]any{
+		{"name": "Commits", "value": formatMetric(summary.Totals.TotalCommitContributions, commits), "inline": true},
+		{"name": "Repositories", "value": formatMetric(summary.Totals.TotalRepositories, repositories), "inline": true},
+		{"name": "Other", "value": formatMetric(summary.Totals.TotalOtherContributions, other), "inline": true},
+	}
+
+	for _, user := range summary.Users {
+		if len(fields) == discordMaxEmbedFields {
+			break
+		}
+		commits, repositories, other := metricDeltas(user.Delta)
+		fields = append(fields, map[string]any{
+			"name": user.User,
+			"value": fmt.Sprintf("%s commits\n%s repositories\n%s other",
+				formatMetric(user.Totals.TotalCommitContributions, commits),
+				formatMetric(user.Totals.TotalRepositories, repositories),
+				formatMetric(user.Totals.TotalOtherContributions, other)),
+			"inline": true,
+		})
+	}
+
+	embed := map[string]any{
+		"title":       "GitHub contributions summary",
+		"description": fmt.Sprintf("Combined contributions across %d accounts", len(summary.Users)),
+		"color":       discordEmbedColor,
+		"fields":      fields,
+		"timestamp":   time.Unix(int64(timestamp), 0).UTC().Format(time.RFC3339),
+	}
+
+	return map[string]any{
+		"username": "ghcontributions",
+		"embeds":   []map[string]any{embed},
+	}
+}
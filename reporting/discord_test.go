@@ -0,0 +1,55 @@
+package reporting_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the NewDiscord constructor
+func TestNewDiscord(t *testing.T) {
+	_, err := rpt.NewDiscord("")
+	assert.Error(t, err)
+}
+
+// Test the Discord Export method
+func TestDiscordExport(t *testing.T) {
+	var message struct {
+		Embeds []struct {
+			Title  string
+			Fields []struct {
+				Name   string
+				Value  string
+				Inline bool
+			}
+		}
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&message)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	discord, err := rpt.NewDiscord(server.URL)
+	assert.NoError(t, err)
+	discord.Previous = &rpt.AggregatedResults{TotalCommitContributions: 12}
+
+	results := rpt.AggregatedResults{
+		TotalCommitContributions: 10,
+		ByUser: map[string]rpt.Totals{
+			"user1": {TotalCommitContributions: 10},
+		},
+	}
+	err = discord.Export(nil, results)
+	assert.NoError(t, err)
+
+	assert.Len(t, message.Embeds, 1)
+	fields := message.Embeds[0].Fields
+	assert.Len(t, fields, 4)
+	assert.Equal(t, "10 (-2)", fields[0].Value)
+	assert.Equal(t, "user1", fields[3].Name)
+}
@@ -0,0 +1,213 @@
+package reporting
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The HTML body of the report email
+var emailTemplate = template.Must(template.New("email").Parse(`<html>
+<body style="font-family: sans-serif">
+<h2>GitHub contributions summary</h2>
+<table cellpadding="6" style="border-collapse: collapse">
+<tr><th align="left">Commits</th><td>{{.Commits}}</td></tr>
+<tr><th align="left">Repositories</th><td>{{.Repositories}}</td></tr>
+<tr><th align="left">Other contributions</th><td>{{.Other}}</td></tr>
+</table>
+{{if .Users}}<h3>By user</h3>
+<table cellpadding="6" style="border-collapse: collapse">
+<tr><th align="left">User</th><th align="right">Commits</th><th align="right">Repositories</th><th align="right">Other</th></tr>
+{{range .Users}}<tr><td>{{.User}}</td><td align="right">{{.Commits}}</td><td align="right">{{.Repositories}}</td><td align="right">{{.Other}}</td></tr>
+{{end}}</table>{{end}}
+</body>
+</html>
+`))
+
+// An Email publisher sends the report to a list of recipients over SMTP,
+// with the machine-readable reports attached
+type Email struct {
+	// The SMTP server host name
+	Host string
+	// The SMTP server port (STARTTLS is used when the server offers it)
+	Port int
+	// The SMTP username and password, if the server requires authentication
+	Username string
+	Password string
+	// The sender address
+	From string
+	// The recipient addresses
+	To []string
+	// The results of the previous snapshot, used to report deltas
+	Previous *AggregatedResults
+	// The output format of the attached report, like yaml, or json if blank
+	Format string
+}
+
+// Constructs a new Email object
+// The host and port locate the SMTP server
+// The from address is the sender, and the to addresses are the recipients
+func NewEmail(host string, port int, from string, to []string) (email *Email, err error) {
+
+	if host == "" {
+		err = fmt.Errorf("the smtp host cannot be blank")
+		return nil, err
+	}
+
+	if from == "" || len(to) == 0 {
+		err = fmt.Errorf("the email sender and recipients cannot be blank")
+		return nil, err
+	}
+
+	return &Email{
+		Host: host,
+		Port: port,
+		From: from,
+		To:   to,
+	}, err
+}
+
+// Export emails the summary as text and HTML, attaching the report artifacts
+func (e *Email) Export(queryResults map[string]QueryResult, aggregatedResults AggregatedResults) error {
+
+	message, err := e.message(queryResults, aggregatedResults)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if e.Username != "" {
+		auth = smtp.PlainAuth("", e.Username, e.Password, e.Host)
+	}
+
+	address := net.JoinHostPort(e.Host, strconv.Itoa(e.Port))
+	err = smtp.SendMail(address, auth, e.From, e.To, message)
+	if err != nil {
+		return fmt.Errorf("failed to send the report email: %w", err)
+	}
+	return nil
+}
+
+// message builds a multipart MIME message with the summary and the attachments
+func (e *Email) message(queryResults map[string]QueryResult, aggregatedResults AggregatedResults) ([]byte, error) {
+
+	summary := Summarize(aggregatedResults, e.Previous)
+	timestamp := time.Unix(int64(aggregatedResults.Timestamp), 0).UTC()
+
+	var message bytes.Buffer
+	mixed := multipart.NewWriter(&message)
+	headers := []string{
+		"From: " + e.From,
+		"To: " + strings.Join(e.To, ", "),
+		"Subject: " + mime.QEncoding.Encode("utf-8", "GitHub contributions summary for "+timestamp.Format("2006-01-02")),
+		"Date: " + timestamp.Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		"Content-Type: multipart/mixed; boundary=" + mixed.Boundary(),
+	}
+	message.WriteString(strings.Join(headers, "\r\n") + "\r\n\r\n")
+
+	// Add the plain text and HTML versions of the summary
+	var alternative bytes.Buffer
+	alternativeWriter := multipart.NewWriter(&alternative)
+	text, html, err := e.bodies(summary)
+	if err != nil {
+		return nil, err
+	}
+	for _, body := range []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=utf-8", text},
+		{"text/html; charset=utf-8", html},
+	} {
+		part, err := alternativeWriter.CreatePart(textproto.MIMEHeader{"Content-Type": {body.contentType}})
+		if err != nil {
+			return nil, err
+		}
+		part.Write([]byte(body.content))
+	}
+	alternativeWriter.Close()
+
+	part, err := mixed.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {"multipart/alternative; boundary=" + alternativeWriter.Boundary()},
+	})
+	if err != nil {
+		return nil, err
+	}
+	part.Write(alternative.Bytes())
+
+	// Attach the machine-readable reports
+	artifacts, err := ReportArtifacts(queryResults, aggregatedResults, e.Format)
+	if err != nil {
+		return nil, err
+	}
+	for _, artifact := range artifacts {
+		err = attach(mixed, artifact.Name, artifact.ContentType, artifact.Content)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	mixed.Close()
+	return message.Bytes(), nil
+}
+
+// bodies renders the plain text and HTML bodies of the summary
+func (e *Email) bodies(summary Summary) (text string, html string, err error) {
+
+	type row struct{ User, Commits, Repositories, Other string }
+	format := func(totals Totals, delta *Totals) row {
+		commits, repositories, other := metricDeltas(delta)
+		return row{
+			Commits:      formatMetric(totals.TotalCommitContributions, commits),
+			Repositories: formatMetric(totals.TotalRepositories, repositories),
+			Other:        formatMetric(totals.TotalOtherContributions, other),
+		}
+	}
+
+	totals := format(summary.Totals, summary.Delta)
+	users := make([]row, 0, len(summary.Users))
+	for _, user := range summary.Users {
+		userRow := format(user.Totals, user.Delta)
+		userRow.User = user.User
+		users = append(users, userRow)
+	}
+
+	var htmlBody bytes.Buffer
+	err = emailTemplate.Execute(&htmlBody, struct {
+		Commits, Repositories, Other string
+		Users                        []row
+	}{totals.Commits, totals.Repositories, totals.Other, users})
+	return summary.Text(), htmlBody.String(), err
+}
+
+// attach adds a base64 encoded file attachment to the message
+func attach(writer *multipart.Writer, filename string, contentType string, content []byte) error {
+
+	part, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"base64"},
+		"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": filename})},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Wrap the encoded content at 76 characters per line
+	encoded := base64.StdEncoding.EncodeToString(content)
+	for len(encoded) > 76 {
+		part.Write([]byte(encoded[:76] + "\r\n"))
+		encoded = encoded[76:]
+	}
+	_, err = part.Write([]byte(encoded + "\r\n"))
+	return err
+}
@@ -0,0 +1,109 @@
+package reporting_test
+
+import (
+	"bufio"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+)
+
+// serveSMTP accepts a single SMTP session and returns the message data
+func serveSMTP(t *testing.T, listener net.Listener) <-chan string {
+	messages := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+		reply("220 localhost ESMTP")
+		var data strings.Builder
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			command := strings.ToUpper(strings.TrimSpace(line))
+			switch {
+			case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+				reply("250 localhost")
+			case command == "DATA":
+				reply("354 end with .")
+				for {
+					line, err := reader.ReadString('\n')
+					if err != nil || line == ".\r\n" {
+						break
+					}
+					data.WriteString(line)
+				}
+				messages <- data.String()
+				reply("250 ok")
+			case command == "QUIT":
+				reply("221 bye")
+				return
+			default:
+				reply("250 ok")
+			}
+		}
+	}()
+	return messages
+}
+
+// Test the NewEmail constructor
+func TestNewEmail(t *testing.T) {
+	_, err := rpt.NewEmail("", 25, "from@example.com", []string{"to@example.com"})
+	assert.Error(t, err)
+
+	_, err = rpt.NewEmail("localhost", 25, "from@example.com", nil)
+	assert.Error(t, err)
+}
+
+// Test the Email Export method
+func TestEmailExport(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+	messages := serveSMTP(t, listener)
+
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	portNumber, _ := strconv.Atoi(port)
+	email, err := rpt.NewEmail(host, portNumber, "reports@example.com", []string{"lead@example.com"})
+	assert.NoError(t, err)
+
+	results := rpt.AggregatedResults{
+		TotalCommitContributions: 10,
+		ByUser: map[string]rpt.Totals{
+			"user1": {TotalCommitContributions: 10},
+		},
+	}
+	err = email.Export(nil, results)
+	assert.NoError(t, err)
+
+	message := <-messages
+	assert.Contains(t, message, "To: lead@example.com")
+	assert.Contains(t, message, "Content-Type: multipart/mixed")
+	assert.Contains(t, message, "Commits: 10")
+	assert.Contains(t, message, "<td>user1</td>")
+	assert.Contains(t, message, `filename=report.json`)
+
+	// Ensure the report is attached in the output format
+	listener, err = net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+	messages = serveSMTP(t, listener)
+	_, port, _ = net.SplitHostPort(listener.Addr().String())
+	email.Port, _ = strconv.Atoi(port)
+	email.Format = "yaml"
+	err = email.Export(nil, results)
+	assert.NoError(t, err)
+	message = <-messages
+	assert.Contains(t, message, `filename=report.yaml`)
+	assert.Contains(t, message, "Content-Type: application/yaml")
+}
@@ -0,0 +1,117 @@
+package reporting
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// An Encoder writes the results of a run in an output format
+type Encoder interface {
+	Encode(w io.Writer, queryResults map[string]QueryResult, aggregatedResults AggregatedResults) error
+}
+
+// An EncoderFunc is a function used as an Encoder
+type EncoderFunc func(w io.Writer, queryResults map[string]QueryResult, aggregatedResults AggregatedResults) error
+
+// Encode calls the function
+func (f EncoderFunc) Encode(w io.Writer, queryResults map[string]QueryResult, aggregatedResults AggregatedResults) error {
+	return f(w, queryResults, aggregatedResults)
+}
+
+// The encoders of each output format. Add a format here to offer it as an output.
+var encoders = map[string]Encoder{
+	"json": EncoderFunc(func(w io.Writer, _ map[string]QueryResult, aggregatedResults AggregatedResults) error {
+		return aggregatedResults.WriteJSON(w)
+	}),
+	"yaml": EncoderFunc(func(w io.Writer, _ map[string]QueryResult, aggregatedResults AggregatedResults) error {
+		return aggregatedResults.WriteYAML(w)
+	}),
+	"csv": EncoderFunc(func(w io.Writer, _ map[string]QueryResult, aggregatedResults AggregatedResults) error {
+		return aggregatedResults.WriteCSV(w)
+	}),
+	"markdown": EncoderFunc(func(w io.Writer, _ map[string]QueryResult, aggregatedResults AggregatedResults) error {
+		return aggregatedResults.WriteMarkdown(w)
+	}),
+	"html": EncoderFunc(WriteHTML),
+}
+
+// EncoderFormats lists the output formats with an Encoder, sorted
+func EncoderFormats() []string {
+	var formats []string
+	for format := range encoders {
+		formats = append(formats, format)
+	}
+	slices.Sort(formats)
+	return formats
+}
+
+// NewEncoder returns the Encoder of the output format
+func NewEncoder(format string) (encoder Encoder, err error) {
+	encoder, ok := encoders[format]
+	if !ok {
+		return nil, fmt.Errorf("unknown output format %q", format)
+	}
+	return encoder, nil
+}
+
+// WriteJSON writes the aggregated results as indented JSON
+func (a AggregatedResults) WriteJSON(w io.Writer) error {
+
+	data, err := json.MarshalIndent(a, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode the json: %w", err)
+	}
+	_, err = w.Write(append(data, '\n'))
+	if err != nil {
+		return fmt.Errorf("failed to write the json: %w", err)
+	}
+	return nil
+}
+
+// WriteYAML writes the aggregated results as YAML, with the same keys in the
+// same order as the JSON
+func (a AggregatedResults) WriteYAML(w io.Writer) error {
+
+	// Go through the JSON, which is also YAML, to keep its field names
+	data, err := json.Marshal(a)
+	if err != nil {
+		return fmt.Errorf("failed to encode the yaml: %w", err)
+	}
+	var node yaml.Node
+	err = yaml.Unmarshal(data, &node)
+	if err != nil {
+		return fmt.Errorf("failed to encode the yaml: %w", err)
+	}
+	blockStyle(&node)
+
+	var yamlBuffer bytes.Buffer
+	encoder := yaml.NewEncoder(&yamlBuffer)
+	encoder.SetIndent(2)
+	err = encoder.Encode(&node)
+	if err != nil {
+		return fmt.Errorf("failed to encode the yaml: %w", err)
+	}
+	err = encoder.Close()
+	if err != nil {
+		return fmt.Errorf("failed to encode the yaml: %w", err)
+	}
+	_, err = w.Write(yamlBuffer.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to write the yaml: %w", err)
+	}
+	return nil
+}
+
+// blockStyle clears the JSON flow and quoting styles of the node and its
+// children, so they're encoded as block YAML
+func blockStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
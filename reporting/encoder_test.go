@@ -0,0 +1,55 @@
+package reporting_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// Test finding the encoder of each output format
+func TestNewEncoder(t *testing.T) {
+	assert.Equal(t, []string{"csv", "html", "json", "markdown", "yaml"}, rpt.EncoderFormats())
+	for _, format := range rpt.EncoderFormats() {
+		encoder, err := rpt.NewEncoder(format)
+		assert.NoError(t, err)
+		assert.NotNil(t, encoder)
+	}
+	_, err := rpt.NewEncoder("xml")
+	assert.Error(t, err)
+}
+
+// Test encoding the results as JSON and YAML with the same keys
+func TestEncodeJSONAndYAML(t *testing.T) {
+	queryResults := commitsByYear("user1", map[int]int{2022: 10, 2023: 20})
+	reporter := &rpt.Reporter{}
+	results, err := reporter.Aggregate(context.Background(), queryResults)
+	require.NoError(t, err)
+
+	var encoded bytes.Buffer
+	encoder, err := rpt.NewEncoder("json")
+	require.NoError(t, err)
+	require.NoError(t, encoder.Encode(&encoded, queryResults, results))
+	var fromJSON map[string]any
+	require.NoError(t, json.Unmarshal(encoded.Bytes(), &fromJSON))
+
+	encoded.Reset()
+	encoder, err = rpt.NewEncoder("yaml")
+	require.NoError(t, err)
+	require.NoError(t, encoder.Encode(&encoded, queryResults, results))
+	assert.Contains(t, encoded.String(), "totalCommitContributions: 30\n")
+	assert.Contains(t, encoded.String(), "byYear:\n  \"2022\":\n    totalCommitContributions: 10\n")
+	assert.NotContains(t, encoded.String(), "{")
+
+	var fromYAML map[string]any
+	require.NoError(t, yaml.Unmarshal(encoded.Bytes(), &fromYAML))
+	assert.Equal(t, len(fromJSON), len(fromYAML))
+	for key := range fromJSON {
+		assert.Contains(t, fromYAML, key)
+	}
+}
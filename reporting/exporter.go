@@ -0,0 +1,12 @@
+package reporting
+
+// An Exporter sends the results of a run to an external metrics or notification service
+type Exporter interface {
+	// Export sends the per user-year query results and their aggregated totals
+	Export(queryResults map[string]QueryResult, aggregatedResults AggregatedResults) error
+}
+
+// Export pushes the aggregated results to the Pushgateway
+func (p *Pushgateway) Export(queryResults map[string]QueryResult, aggregatedResults AggregatedResults) error {
+	return p.Push(aggregatedResults)
+}
@@ -0,0 +1,140 @@
+package reporting
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// A RepositoryFilter picks the repositories whose contributions are aggregated,
+// like those of an organization, or those not matching a pattern like */dotfiles
+// or forks. A zero filter picks every repository.
+type RepositoryFilter struct {
+	// The owners, like organizations or users, whose repositories are picked, or
+	// every owner when there are none
+	Owners []string `json:"owners,omitempty"`
+	// The owners whose repositories are left out
+	ExcludedOwners []string `json:"excludedOwners,omitempty"`
+	// The glob patterns of the paths of the repositories picked, like myorg/*, or
+	// every path when there are none
+	Include []string `json:"include,omitempty"`
+	// The glob patterns of the paths of the repositories left out, like */dotfiles
+	Exclude []string `json:"exclude,omitempty"`
+	// Whether to leave out the forks of other repositories, and the archived repositories
+	ExcludeForks    bool `json:"excludeForks,omitempty"`
+	ExcludeArchived bool `json:"excludeArchived,omitempty"`
+}
+
+// IsZero reports whether the filter picks every repository
+func (f RepositoryFilter) IsZero() bool {
+	return len(f.Owners) == 0 && len(f.ExcludedOwners) == 0 && len(f.Include) == 0 && len(f.Exclude) == 0 &&
+		!f.ExcludeForks && !f.ExcludeArchived
+}
+
+// Validate checks that the patterns of the filter are valid globs
+func (f RepositoryFilter) Validate() error {
+	for _, pattern := range slices.Concat(f.Include, f.Exclude) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("the repository pattern %q is invalid: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// Match reports whether the filter picks the repository, matching the owners and
+// the patterns without regard to case, as GitHub does. The patterns match the path
+// of the repository like path.Match, so a * doesn't match a /.
+func (f RepositoryFilter) Match(repository Repository) bool {
+	if (f.ExcludeForks && repository.IsFork) || (f.ExcludeArchived && repository.IsArchived) {
+		return false
+	}
+	owner := repository.Owner()
+	matchesOwner := func(name string) bool { return strings.EqualFold(name, owner) }
+	if len(f.Owners) > 0 && !slices.ContainsFunc(f.Owners, matchesOwner) {
+		return false
+	}
+	if slices.ContainsFunc(f.ExcludedOwners, matchesOwner) {
+		return false
+	}
+	repositoryPath := strings.ToLower(repository.Path())
+	matchesPath := func(pattern string) bool {
+		matched, _ := path.Match(strings.ToLower(pattern), repositoryPath)
+		return matched
+	}
+	if len(f.Include) > 0 && !slices.ContainsFunc(f.Include, matchesPath) {
+		return false
+	}
+	return !slices.ContainsFunc(f.Exclude, matchesPath)
+}
+
+// Path returns the path of the repository, like owner/name from the URL
+// https://github.com/owner/name, or its name without a URL
+func (r Repository) Path() string {
+	repositoryURL, err := url.Parse(r.URL)
+	if err != nil || strings.Trim(repositoryURL.Path, "/") == "" {
+		return r.Name
+	}
+	return strings.Trim(repositoryURL.Path, "/")
+}
+
+// Owner returns the owner of the repository, the first part of the path of its
+// URL, like the organization of https://github.com/owner/name, or blank without one
+func (r Repository) Owner() string {
+	repositoryURL, err := url.Parse(r.URL)
+	if err != nil {
+		return ""
+	}
+	owner, _, _ := strings.Cut(strings.Trim(repositoryURL.Path, "/"), "/")
+	return owner
+}
+
+// FilterQueryResults returns the query results with only the contributions to
+// the repositories the filter picks, with the totals counted again from them.
+// The contributions without a repository, like the restricted contributions, those
+// past the limit of repositories listed, and the contribution calendar total,
+// can't be told to belong to a repository the filter picks, so they're left out.
+func FilterQueryResults(queryResults map[string]QueryResult, filter RepositoryFilter) map[string]QueryResult {
+
+	var filtered = make(map[string]QueryResult, len(queryResults))
+	for userYear, queryResult := range queryResults {
+		collection := queryResult.User.ContributionsCollection
+		var kept ContributionsCollection
+		kept.HasActivityInThePast = collection.HasActivityInThePast
+		kept.CommitContributionsByRepository, kept.TotalCommitContributions =
+			filterRepositoryContributions(collection.CommitContributionsByRepository, filter)
+		kept.IssueContributionsByRepository, kept.TotalIssueContributions =
+			filterRepositoryContributions(collection.IssueContributionsByRepository, filter)
+		kept.PullRequestContributionsByRepository, kept.TotalPullRequestContributions =
+			filterRepositoryContributions(collection.PullRequestContributionsByRepository, filter)
+		kept.PullRequestReviewContributionsByRepository, kept.TotalPullRequestReviewContributions =
+			filterRepositoryContributions(collection.PullRequestReviewContributionsByRepository, filter)
+		kept.TotalRepositoriesWithContributedCommits = githubv4.Int(len(kept.CommitContributionsByRepository))
+		kept.TotalRepositoriesWithContributedIssues = githubv4.Int(len(kept.IssueContributionsByRepository))
+		kept.TotalRepositoriesWithContributedPullRequests = githubv4.Int(len(kept.PullRequestContributionsByRepository))
+		kept.TotalRepositoriesWithContributedPullRequestReviews = githubv4.Int(len(kept.PullRequestReviewContributionsByRepository))
+		kept.HasAnyContributions = kept.TotalCommitContributions+kept.TotalIssueContributions+
+			kept.TotalPullRequestContributions+kept.TotalPullRequestReviewContributions > 0
+
+		queryResult.User.ContributionsCollection = kept
+		filtered[userYear] = queryResult
+	}
+	return filtered
+}
+
+// filterRepositoryContributions returns the contributions to the repositories the
+// filter picks, and their total
+func filterRepositoryContributions(contributions []RepositoryContribution,
+	filter RepositoryFilter) (kept []RepositoryContribution, total githubv4.Int) {
+
+	for _, contribution := range contributions {
+		if filter.Match(contribution.repository()) {
+			kept = append(kept, contribution)
+			total += contribution.Contributions.TotalCount
+		}
+	}
+	return kept, total
+}
@@ -0,0 +1,103 @@
+package reporting_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// filterContributions returns query results with contributions to repositories of two owners
+func filterContributions() map[string]rpt.QueryResult {
+	date := time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)
+	return rpt.QueryResultsFromContributions("user1", []rpt.Contribution{
+		{Kind: rpt.ContributionCommit, Repository: rpt.Repository{Name: "app", URL: "https://github.com/NCEAS/app"},
+			Date: date, Count: 10},
+		{Kind: rpt.ContributionCommit, Repository: rpt.Repository{Name: "dotfiles", URL: "https://github.com/csjx/dotfiles"},
+			Date: date, Count: 4},
+		{Kind: rpt.ContributionIssue, Repository: rpt.Repository{Name: "app", URL: "https://github.com/NCEAS/app"},
+			Date: date, Count: 2},
+		{Kind: rpt.ContributionRestricted, Date: date, Count: 3},
+	})
+}
+
+// Test finding the owner of a repository from its URL
+func TestRepositoryOwner(t *testing.T) {
+	assert.Equal(t, "NCEAS", rpt.Repository{URL: "https://github.com/NCEAS/app"}.Owner())
+	assert.Equal(t, "group", rpt.Repository{URL: "https://gitlab.com/group/subgroup/app"}.Owner())
+	assert.Empty(t, rpt.Repository{Name: "app"}.Owner())
+}
+
+// Test picking the repositories by their owners and paths
+func TestRepositoryFilterMatch(t *testing.T) {
+	repository := rpt.Repository{URL: "https://github.com/NCEAS/app"}
+	assert.True(t, rpt.RepositoryFilter{}.Match(repository))
+	assert.True(t, rpt.RepositoryFilter{Owners: []string{"csjx", "nceas"}}.Match(repository))
+	assert.False(t, rpt.RepositoryFilter{Owners: []string{"csjx"}}.Match(repository))
+	assert.False(t, rpt.RepositoryFilter{ExcludedOwners: []string{"NCEAS"}}.Match(repository))
+
+	// Ensure the patterns match the path of the repository, and a * doesn't match a /
+	assert.True(t, rpt.RepositoryFilter{Include: []string{"nceas/*"}}.Match(repository))
+	assert.False(t, rpt.RepositoryFilter{Include: []string{"*"}}.Match(repository))
+	assert.False(t, rpt.RepositoryFilter{Exclude: []string{"*/app"}}.Match(repository))
+	assert.True(t, rpt.RepositoryFilter{Exclude: []string{"*/dotfiles"}}.Match(repository))
+	assert.True(t, rpt.RepositoryFilter{Include: []string{"app"}}.Match(rpt.Repository{Name: "app"}))
+	assert.Equal(t, "NCEAS/app", repository.Path())
+
+	// Ensure forks and archived repositories are only left out when asked to
+	fork := rpt.Repository{URL: "https://github.com/csjx/app", IsFork: true}
+	assert.True(t, rpt.RepositoryFilter{ExcludeArchived: true}.Match(fork))
+	assert.False(t, rpt.RepositoryFilter{ExcludeForks: true}.Match(fork))
+	assert.False(t, rpt.RepositoryFilter{ExcludeArchived: true}.Match(rpt.Repository{Name: "old", IsArchived: true}))
+	assert.False(t, rpt.RepositoryFilter{ExcludeForks: true}.IsZero())
+
+	assert.NoError(t, rpt.RepositoryFilter{Include: []string{"myorg/*"}}.Validate())
+	assert.Error(t, rpt.RepositoryFilter{Exclude: []string{"*/["}}.Validate())
+}
+
+// Test aggregating only the contributions to the repositories of an organization
+func TestAggregateFiltered(t *testing.T) {
+	queryResults := filterContributions()
+	filter := rpt.RepositoryFilter{Owners: []string{"NCEAS"}}
+
+	filtered := rpt.FilterQueryResults(queryResults, filter)
+	collection := filtered["user1-2023"].User.ContributionsCollection
+	assert.Equal(t, 10, int(collection.TotalCommitContributions))
+	assert.Equal(t, 2, int(collection.TotalIssueContributions))
+	assert.Zero(t, int(collection.RestrictedContributionsCount))
+	assert.Equal(t, 1, int(collection.TotalRepositoriesWithContributedCommits))
+
+	// Ensure the totals and the repositories listed leave out the other owners
+	reporter := &rpt.Reporter{IncludeRestricted: true, Filter: filter}
+	result, err := reporter.Aggregate(context.Background(), queryResults)
+	require.NoError(t, err)
+	assert.Equal(t, 10, result.TotalCommitContributions)
+	assert.Equal(t, 2, result.TotalOtherContributions)
+	assert.Equal(t, 1, result.TotalRepositories)
+	assert.Equal(t, rpt.Totals{TotalCommitContributions: 10, TotalRepositories: 1, TotalOtherContributions: 2},
+		result.ByUser["user1"])
+	require.Len(t, result.Repositories, 1)
+	assert.Equal(t, "app", result.Repositories[0].Name)
+	assert.Equal(t, &filter, result.RepositoryFilter)
+
+	result, err = (&rpt.Reporter{Filter: rpt.RepositoryFilter{ExcludedOwners: []string{"NCEAS"}}}).
+		Aggregate(context.Background(), queryResults)
+	require.NoError(t, err)
+	assert.Equal(t, 4, result.TotalCommitContributions)
+	assert.Zero(t, result.TotalOtherContributions)
+
+	result, err = (&rpt.Reporter{Filter: rpt.RepositoryFilter{Exclude: []string{"*/dotfiles"}}}).
+		Aggregate(context.Background(), queryResults)
+	require.NoError(t, err)
+	assert.Equal(t, 10, result.TotalCommitContributions)
+	assert.Equal(t, 1, result.TotalRepositories)
+
+	// Ensure an unfiltered report has no filter
+	result, err = (&rpt.Reporter{}).Aggregate(context.Background(), queryResults)
+	require.NoError(t, err)
+	assert.Equal(t, 14, result.TotalCommitContributions)
+	assert.Nil(t, result.RepositoryFilter)
+}
@@ -0,0 +1,55 @@
+package reporting
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// A FirstYearSource finds the first year a user could have contributed in,
+// for providers that can skip the years before it
+type FirstYearSource interface {
+	FirstYear(ctx context.Context, user string) (int, error)
+}
+
+// A firstYearQuery selects when a user's account was created and the date of
+// their earliest restricted contribution, like 2019-03-01, if they have any
+type firstYearQuery struct {
+	User struct {
+		CreatedAt               githubv4.DateTime
+		ContributionsCollection struct {
+			EarliestRestrictedContributionDate *githubv4.String
+		}
+	} `graphql:"user(login: $login)"`
+}
+
+// FirstYear looks up the year the user's account was created, or that of their
+// earliest restricted contribution if it's earlier, retrying by the provider's retry policy
+func (g *GitHub) FirstYear(ctx context.Context, user string) (int, error) {
+
+	var query firstYearQuery
+	err := g.RetryPolicy.Do(ctx, func() error {
+		query = firstYearQuery{}
+		return g.Client.Query(ctx, &query, map[string]interface{}{
+			"login": githubv4.String(user),
+		})
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to query github for the first year of %s: %w", user, err)
+	}
+	if query.User.CreatedAt.IsZero() {
+		return 0, fmt.Errorf("github has no creation date for %s", user)
+	}
+
+	firstYear := query.User.CreatedAt.Year()
+	if earliest := query.User.ContributionsCollection.EarliestRestrictedContributionDate; earliest != nil {
+		date, err := time.Parse(time.DateOnly, string(*earliest))
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse the earliest restricted contribution date of %s: %w", user, err)
+		}
+		firstYear = min(firstYear, date.Year())
+	}
+	return firstYear, nil
+}
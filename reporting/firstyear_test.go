@@ -0,0 +1,74 @@
+package reporting_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyGraphQLClient fails the first queries with a server error before
+// decoding the pages
+type flakyGraphQLClient struct {
+	pagedGraphQLClient
+	Failures int
+}
+
+// Query fails while there are failures left, then decodes the next page
+func (f *flakyGraphQLClient) Query(ctx context.Context, q interface{}, variables map[string]interface{}) error {
+	if f.Failures > 0 {
+		f.Failures--
+		return errors.New(`non-200 OK status code: 502 Bad Gateway body: ""`)
+	}
+	return f.pagedGraphQLClient.Query(ctx, q, variables)
+}
+
+// Test finding the first year of a user with the GitHub provider
+func TestGitHubFirstYear(t *testing.T) {
+	client := &pagedGraphQLClient{Pages: []string{
+		`{"user": {"createdAt": "2019-05-01T00:00:00Z", "contributionsCollection": {"earliestRestrictedContributionDate": null}}}`,
+		`{"user": {"createdAt": "2019-05-01T00:00:00Z", "contributionsCollection": {"earliestRestrictedContributionDate": "2017-03-01"}}}`,
+		`{"user": {"createdAt": "2019-05-01T00:00:00Z", "contributionsCollection": {"earliestRestrictedContributionDate": "March 2017"}}}`,
+		`{"user": {}}`,
+	}}
+	gitHub, err := rpt.NewGitHub(client)
+	require.NoError(t, err)
+
+	// Ensure the account's creation year is used without restricted contributions
+	firstYear, err := gitHub.FirstYear(context.Background(), "alice")
+	require.NoError(t, err)
+	assert.Equal(t, 2019, firstYear)
+	assert.Equal(t, githubv4.String("alice"), client.Variables[0]["login"])
+
+	// Ensure an earlier restricted contribution moves the first year back
+	firstYear, err = gitHub.FirstYear(context.Background(), "alice")
+	require.NoError(t, err)
+	assert.Equal(t, 2017, firstYear)
+
+	_, err = gitHub.FirstYear(context.Background(), "alice")
+	assert.Error(t, err)
+
+	// Ensure a missing user is an error rather than year one
+	_, err = gitHub.FirstYear(context.Background(), "nobody")
+	assert.Error(t, err)
+}
+
+// Test retrying the query for the first year by the provider's retry policy
+func TestGitHubFirstYearRetry(t *testing.T) {
+	client := &flakyGraphQLClient{Failures: 2, pagedGraphQLClient: pagedGraphQLClient{Pages: []string{
+		`{"user": {"createdAt": "2019-05-01T00:00:00Z", "contributionsCollection": {"earliestRestrictedContributionDate": null}}}`,
+	}}}
+	gitHub, err := rpt.NewGitHub(client)
+	require.NoError(t, err)
+	gitHub.RetryPolicy = rpt.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+
+	firstYear, err := gitHub.FirstYear(context.Background(), "alice")
+	require.NoError(t, err)
+	assert.Equal(t, 2019, firstYear)
+	assert.Zero(t, client.Failures)
+}
@@ -0,0 +1,121 @@
+package reporting
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// The z-score of the forecast's 95% uncertainty band
+const forecastZScore = 1.96
+
+// A ContributionDay holds the count of contributions made on a single day
+type ContributionDay struct {
+	Date  time.Time `json:"date"`
+	Count int       `json:"count"`
+}
+
+// A CalendarSource lists a user's daily contribution counts, for providers
+// that can forecast the year from its pace so far
+type CalendarSource interface {
+	ContributionDays(ctx context.Context, user string, dateRange DateRange) ([]ContributionDay, error)
+}
+
+// A Forecast projects the year's contributions from the daily pace so far
+type Forecast struct {
+	Year int `json:"year"`
+	// The days of the year elapsed, including today
+	DaysElapsed int `json:"daysElapsed"`
+	// The contributions made so far this year
+	ToDate int `json:"toDate"`
+	// The contributions projected by the end of the year
+	Projected int `json:"projected"`
+	// The 95% uncertainty band around the projection
+	Low  int `json:"low"`
+	High int `json:"high"`
+}
+
+// A contributionCalendarQuery lists the user's contribution count each day
+type contributionCalendarQuery struct {
+	User struct {
+		ContributionsCollection struct {
+			ContributionCalendar struct {
+				Weeks []struct {
+					ContributionDays []struct {
+						Date              githubv4.String
+						ContributionCount githubv4.Int
+					}
+				}
+			}
+		} `graphql:"contributionsCollection(from: $from, to: $to)"`
+	} `graphql:"user(login: $login)"`
+}
+
+// ContributionDays lists the user's contribution count each day from the contribution
+// calendar. The range can't be longer than a year.
+func (g *GitHub) ContributionDays(ctx context.Context, user string, dateRange DateRange) ([]ContributionDay, error) {
+
+	var query contributionCalendarQuery
+	err := g.Client.Query(ctx, &query, map[string]interface{}{
+		"login": githubv4.String(user),
+		"from":  githubv4.DateTime{Time: dateRange.From},
+		"to":    githubv4.DateTime{Time: dateRange.To.Add(-time.Second)},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query github for the contribution calendar: %w", err)
+	}
+
+	var days []ContributionDay
+	for _, week := range query.User.ContributionsCollection.ContributionCalendar.Weeks {
+		for _, day := range week.ContributionDays {
+			date, err := time.Parse(time.DateOnly, string(day.Date))
+			if err != nil {
+				return days, fmt.Errorf("failed to parse the contribution calendar date %q: %w", day.Date, err)
+			}
+			days = append(days, ContributionDay{Date: date, Count: int(day.ContributionCount)})
+		}
+	}
+	return days, nil
+}
+
+// ForecastYear projects the current year's contributions, summing the days of all
+// users. The projection continues the average daily pace so far, and the band
+// widens with the spread of the daily counts and the days remaining.
+func ForecastYear(days []ContributionDay, now time.Time) Forecast {
+
+	now = now.UTC()
+	year := now.Year()
+	yearRange := YearRange(year, year)
+	daysInYear := int(yearRange.To.Sub(yearRange.From).Hours() / 24)
+	elapsed := now.YearDay()
+
+	// Days without any contributions count as zero
+	var counts = make([]float64, elapsed)
+	forecast := Forecast{Year: year, DaysElapsed: elapsed}
+	for _, day := range days {
+		if day.Date.Year() == year && day.Date.YearDay() <= elapsed {
+			counts[day.Date.YearDay()-1] += float64(day.Count)
+			forecast.ToDate += day.Count
+		}
+	}
+
+	mean := float64(forecast.ToDate) / float64(elapsed)
+	var variance float64
+	if elapsed > 1 {
+		for _, count := range counts {
+			variance += (count - mean) * (count - mean)
+		}
+		variance /= float64(elapsed - 1)
+	}
+
+	remaining := float64(daysInYear - elapsed)
+	projected := float64(forecast.ToDate) + mean*remaining
+	margin := forecastZScore * math.Sqrt(variance*remaining)
+	forecast.Projected = int(math.Round(projected))
+	forecast.Low = max(forecast.ToDate, int(math.Round(projected-margin)))
+	forecast.High = int(math.Round(projected + margin))
+	return forecast
+}
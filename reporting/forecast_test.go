@@ -0,0 +1,59 @@
+package reporting_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test listing the daily contributions with the GitHub provider
+func TestGitHubContributionDays(t *testing.T) {
+	client := &pagedGraphQLClient{Pages: []string{
+		`{"user": {"contributionsCollection": {"contributionCalendar": {"weeks": [
+			{"contributionDays": [{"date": "2024-01-01", "contributionCount": 3}, {"date": "2024-01-02", "contributionCount": 0}]},
+			{"contributionDays": [{"date": "2024-01-08", "contributionCount": 5}]}
+		]}}}}`,
+	}}
+	gitHub, err := rpt.NewGitHub(client)
+	require.NoError(t, err)
+
+	days, err := gitHub.ContributionDays(context.Background(), "user1", rpt.YearRange(2024, 2024))
+	require.NoError(t, err)
+	assert.Equal(t, []rpt.ContributionDay{
+		{Date: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Count: 3},
+		{Date: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), Count: 0},
+		{Date: time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC), Count: 5},
+	}, days)
+	assert.Equal(t, githubv4.String("user1"), client.Variables[0]["login"])
+}
+
+// Test projecting the year's contributions from the pace so far
+func TestForecastYear(t *testing.T) {
+	// A steady 2 contributions a day, summed from two users, is certain
+	var days []rpt.ContributionDay
+	for day := range 100 {
+		date := time.Date(2023, time.January, 1+day, 0, 0, 0, 0, time.UTC)
+		days = append(days, rpt.ContributionDay{Date: date, Count: 1}, rpt.ContributionDay{Date: date, Count: 1})
+	}
+	// Days after today are left out
+	days = append(days, rpt.ContributionDay{Date: time.Date(2023, time.December, 1, 0, 0, 0, 0, time.UTC), Count: 50})
+
+	now := time.Date(2023, time.April, 10, 12, 0, 0, 0, time.UTC)
+	forecast := rpt.ForecastYear(days, now)
+	assert.Equal(t, rpt.Forecast{Year: 2023, DaysElapsed: 100, ToDate: 200, Projected: 730, Low: 730, High: 730}, forecast)
+
+	// An uneven pace widens the band, which never falls below the contributions so far
+	days = []rpt.ContributionDay{
+		{Date: time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC), Count: 200},
+	}
+	forecast = rpt.ForecastYear(days, now)
+	assert.Equal(t, 200, forecast.ToDate)
+	assert.Equal(t, 730, forecast.Projected)
+	assert.Equal(t, 200, forecast.Low)
+	assert.Greater(t, forecast.High, 730)
+}
@@ -0,0 +1,124 @@
+package reporting
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// The OAuth scope needed to upload objects to Google Cloud Storage
+const GCSScope = "https://www.googleapis.com/auth/devstorage.read_write"
+
+// The default template for naming uploaded objects
+const DefaultGCSObjectTemplate = "reports/{{.Time}}/{{.Name}}"
+
+// A GCS publisher uploads the report artifacts of each run to a Google Cloud Storage bucket
+type GCS struct {
+	// The bucket name
+	Bucket string
+	// The template for object names, with the .Date, .Time, .Users, and .Name fields
+	ObjectTemplate *template.Template
+	// The storage API endpoint
+	Endpoint string
+	// An HTTP client authenticated for the storage API
+	Client *http.Client
+	// The output format of the report artifact, like yaml, or json if blank
+	Format string
+}
+
+// ObjectName holds the fields available to GCS object name templates
+type ObjectName struct {
+	// The date of the run, like 2024-11-01
+	Date string
+	// The time of the run, like 20241101T120000Z
+	Time string
+	// The usernames in the report, joined with a +
+	Users string
+	// The artifact file name, like report.json
+	Name string
+}
+
+// Constructs a new GCS object
+// The bucket is the bucket to upload to
+// The objectTemplate names the uploaded objects, like {{.Date}}/{{.Users}}/{{.Name}}
+// The client is authenticated for the storage API, like one from NewGoogleClient
+func NewGCS(bucket string, objectTemplate string, client *http.Client) (gcs *GCS, err error) {
+
+	if bucket == "" {
+		err = fmt.Errorf("the gcs bucket cannot be blank")
+		return nil, err
+	}
+
+	if objectTemplate == "" {
+		objectTemplate = DefaultGCSObjectTemplate
+	}
+	parsedTemplate, err := template.New("object").Option("missingkey=error").Parse(objectTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the gcs object template: %w", err)
+	}
+
+	return &GCS{
+		Bucket:         bucket,
+		ObjectTemplate: parsedTemplate,
+		Endpoint:       "https://storage.googleapis.com",
+		Client:         client,
+	}, err
+}
+
+// Export uploads each report artifact with a name rendered from the object template
+func (g *GCS) Export(queryResults map[string]QueryResult, aggregatedResults AggregatedResults) error {
+
+	artifacts, err := ReportArtifacts(queryResults, aggregatedResults, g.Format)
+	if err != nil {
+		return err
+	}
+
+	timestamp := time.Unix(int64(aggregatedResults.Timestamp), 0).UTC()
+	for _, artifact := range artifacts {
+		var objectName strings.Builder
+		err = g.ObjectTemplate.Execute(&objectName, ObjectName{
+			Date:  timestamp.Format("2006-01-02"),
+			Time:  artifactTimestamp(aggregatedResults),
+			Users: strings.Join(slices.Sorted(maps.Keys(aggregatedResults.ByUser)), "+"),
+			Name:  artifact.Name,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to name the gcs object: %w", err)
+		}
+
+		err = g.upload(objectName.String(), artifact)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// upload sends a single artifact with a simple media upload
+func (g *GCS) upload(objectName string, artifact Artifact) error {
+
+	endpoint := g.Endpoint + "/upload/storage/v1/b/" + url.PathEscape(g.Bucket) +
+		"/o?uploadType=media&name=" + url.QueryEscape(objectName)
+	request, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(artifact.Content))
+	if err != nil {
+		return fmt.Errorf("failed to build the gcs request: %w", err)
+	}
+	request.Header.Set("Content-Type", artifact.ContentType)
+
+	response, err := g.Client.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to upload %s to gcs: %w", objectName, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode/100 != 2 {
+		return fmt.Errorf("failed to upload %s to gcs: gcs returned %s", objectName, response.Status)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package reporting_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the NewGCS constructor
+func TestNewGCS(t *testing.T) {
+	_, err := rpt.NewGCS("", "", http.DefaultClient)
+	assert.Error(t, err)
+
+	_, err = rpt.NewGCS("bucket", "{{.Date", http.DefaultClient)
+	assert.Error(t, err)
+}
+
+// Test the GCS Export method with an object name template
+func TestGCSExport(t *testing.T) {
+	var path, name, contentType, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		name = r.URL.Query().Get("name")
+		contentType = r.Header.Get("Content-Type")
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+	}))
+	defer server.Close()
+
+	gcs, err := rpt.NewGCS("reports", "{{.Date}}/{{.Users}}/{{.Name}}", server.Client())
+	assert.NoError(t, err)
+	gcs.Endpoint = server.URL
+
+	results := rpt.AggregatedResults{
+		Timestamp:                1730462400,
+		TotalCommitContributions: 42,
+		ByUser:                   map[string]rpt.Totals{"bob": {}, "alice": {}},
+	}
+	err = gcs.Export(nil, results)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "/upload/storage/v1/b/reports/o", path)
+	assert.Equal(t, "2024-11-01/alice+bob/report.json", name)
+	assert.Equal(t, "application/json", contentType)
+	assert.Contains(t, body, `"totalCommitContributions": 42`)
+}
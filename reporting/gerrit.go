@@ -0,0 +1,185 @@
+package reporting
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The layout of Gerrit timestamps, which are always in UTC
+const gerritTimeLayout = "2006-01-02 15:04:05.000000000"
+
+// The most changes requested per page
+const gerritPageSize = 500
+
+// A Gerrit provider collects the changes a user owns and reviews on a Gerrit
+// server, like the Android and Chromium code review sites
+type Gerrit struct {
+	// The base URL of the Gerrit server
+	BaseURL string
+	// The username for authenticated requests, if any
+	Username string
+	// The HTTP password generated in the Gerrit settings, if any
+	Password string
+	// The HTTP client used for the API requests
+	Client *http.Client
+}
+
+// A gerritChange holds the fields of a Gerrit change
+type gerritChange struct {
+	Project     string     `json:"project"`
+	Status      string     `json:"status"`
+	Created     gerritTime `json:"created"`
+	Submitted   gerritTime `json:"submitted"`
+	MoreChanges bool       `json:"_more_changes"`
+}
+
+// A gerritTime parses Gerrit timestamps
+type gerritTime struct {
+	time.Time
+}
+
+// UnmarshalJSON parses a quoted Gerrit timestamp
+func (g *gerritTime) UnmarshalJSON(data []byte) error {
+	value, err := strconv.Unquote(string(data))
+	if err != nil {
+		return err
+	}
+	g.Time, err = time.Parse(gerritTimeLayout, value)
+	return err
+}
+
+// Constructs a new Gerrit object
+// The baseURL is the Gerrit server, like https://android-review.googlesource.com
+// The username and password authenticate requests, and may be blank for anonymous access
+func NewGerrit(baseURL string, username string, password string) (gerrit *Gerrit, err error) {
+
+	if baseURL == "" {
+		err = fmt.Errorf("the gerrit url cannot be blank")
+		return nil, err
+	}
+
+	return &Gerrit{
+		BaseURL:  strings.TrimSuffix(baseURL, "/"),
+		Username: username,
+		Password: password,
+		Client:   http.DefaultClient,
+	}, err
+}
+
+// Collect counts the changes the user created as pull requests, their merged
+// changes as commits, and the changes of others they reviewed as reviews
+func (g *Gerrit) Collect(ctx context.Context, user string, dateRange DateRange) ([]Contribution, error) {
+
+	var contributions []Contribution
+
+	slog.Debug("Fetching gerrit changes", "user", user)
+
+	// The after operator matches the time a change was last updated, which is
+	// never before it was created, so the range is checked for each change
+	after := fmt.Sprintf(` after:"%s"`, dateRange.From.Format(time.DateOnly))
+
+	err := g.changes(ctx, "owner:"+strconv.Quote(user)+after, func(change gerritChange) {
+		repository := g.repository(change.Project)
+		if dateRange.Contains(change.Created.Time) {
+			contributions = append(contributions, Contribution{
+				Kind: ContributionPullRequest, Repository: repository, Date: change.Created.Time, Count: 1,
+			})
+		}
+		if change.Status == "MERGED" && dateRange.Contains(change.Submitted.Time) {
+			contributions = append(contributions, Contribution{
+				Kind: ContributionCommit, Repository: repository, Date: change.Submitted.Time, Count: 1,
+			})
+		}
+	})
+	if err != nil {
+		return contributions, err
+	}
+
+	err = g.changes(ctx, "reviewedby:"+strconv.Quote(user)+" -owner:"+strconv.Quote(user)+after, func(change gerritChange) {
+		if dateRange.Contains(change.Created.Time) {
+			contributions = append(contributions, Contribution{
+				Kind: ContributionPullRequestReview, Repository: g.repository(change.Project), Date: change.Created.Time, Count: 1,
+			})
+		}
+	})
+	if err != nil {
+		return contributions, err
+	}
+	return contributions, nil
+}
+
+// repository returns the Gerrit project as a repository
+func (g *Gerrit) repository(project string) Repository {
+	return Repository{Name: project, URL: g.BaseURL + "/q/project:" + url.PathEscape(project)}
+}
+
+// changes visits every change matching the query, a page at a time
+func (g *Gerrit) changes(ctx context.Context, query string, visit func(change gerritChange)) error {
+
+	for start := 0; ; start += gerritPageSize {
+		values := url.Values{}
+		values.Set("q", query)
+		values.Set("n", strconv.Itoa(gerritPageSize))
+		values.Set("S", strconv.Itoa(start))
+
+		var changes []gerritChange
+		err := g.get(ctx, "/changes/?"+values.Encode(), &changes)
+		if err != nil {
+			return err
+		}
+		for _, change := range changes {
+			visit(change)
+		}
+		// The last change on a page is marked when more changes follow
+		if len(changes) == 0 || !changes[len(changes)-1].MoreChanges {
+			return nil
+		}
+	}
+}
+
+// get requests a Gerrit REST API path and decodes the JSON response. Authenticated
+// requests use the /a/ prefix, and every response starts with a line guarding
+// against cross-site script inclusion.
+func (g *Gerrit) get(ctx context.Context, path string, value any) error {
+
+	if g.Password != "" {
+		path = "/a" + path
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, g.BaseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	if g.Password != "" {
+		request.SetBasicAuth(g.Username, g.Password)
+	}
+	request.Header.Set("Accept", "application/json")
+
+	response, err := g.Client.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to query gerrit: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode/100 != 2 {
+		return fmt.Errorf("failed to query gerrit: gerrit returned %s", response.Status)
+	}
+
+	reader := bufio.NewReader(response.Body)
+	prefix, err := reader.ReadString('\n')
+	if err != nil || strings.TrimSpace(prefix) != ")]}'" {
+		return fmt.Errorf("failed to parse the gerrit response: missing the )]}' prefix")
+	}
+	err = json.NewDecoder(reader).Decode(value)
+	if err != nil {
+		return fmt.Errorf("failed to parse the gerrit response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package reporting_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the NewGerrit constructor
+func TestNewGerrit(t *testing.T) {
+	_, err := rpt.NewGerrit("", "user1", "password")
+	assert.Error(t, err)
+
+	gerrit, err := rpt.NewGerrit("https://review.example.com/", "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://review.example.com", gerrit.BaseURL)
+}
+
+// Test collecting owned and reviewed changes from Gerrit
+func TestGerritCollect(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, _ := r.BasicAuth()
+		assert.Equal(t, "user1", user)
+		assert.Equal(t, "password", password)
+		assert.Equal(t, "/a/changes/", r.URL.Path)
+
+		query := r.URL.Query().Get("q")
+		queries = append(queries, query+" S="+r.URL.Query().Get("S"))
+		w.Write([]byte(")]}'\n"))
+		switch {
+		case strings.HasPrefix(query, "owner:") && r.URL.Query().Get("S") == "0":
+			w.Write([]byte(`[
+				{"project": "platform/build", "status": "MERGED",
+				 "created": "2023-12-30 10:00:00.000000000", "submitted": "2024-01-02 10:00:00.000000000"},
+				{"project": "platform/build", "status": "NEW",
+				 "created": "2022-12-30 10:00:00.000000000", "_more_changes": true}
+			]`))
+		case strings.HasPrefix(query, "owner:"):
+			w.Write([]byte(`[{"project": "platform/art", "status": "ABANDONED", "created": "2024-03-01 10:00:00.000000000"}]`))
+		default:
+			w.Write([]byte(`[{"project": "platform/art", "status": "MERGED", "created": "2024-04-01 10:00:00.000000000"}]`))
+		}
+	}))
+	defer server.Close()
+
+	gerrit, err := rpt.NewGerrit(server.URL, "user1", "password")
+	assert.NoError(t, err)
+	collector, err := rpt.NewProviderCollector(gerrit, "user1", 2023, 2024)
+	assert.NoError(t, err)
+	queryResults, err := collector.Collect(context.Background())
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{
+		`owner:"user1" after:"2023-01-01" S=0`,
+		`owner:"user1" after:"2023-01-01" S=500`,
+		`reviewedby:"user1" -owner:"user1" after:"2023-01-01" S=0`,
+	}, queries)
+	assert.Len(t, queryResults, 2)
+
+	// Ensure a merged change counts when created and when submitted
+	collection := queryResults["user1-2023"].User.ContributionsCollection
+	assert.Equal(t, 1, int(collection.TotalPullRequestContributions))
+	assert.Equal(t, 0, int(collection.TotalCommitContributions))
+
+	collection = queryResults["user1-2024"].User.ContributionsCollection
+	assert.Equal(t, 1, int(collection.TotalCommitContributions))
+	assert.Equal(t, 1, int(collection.TotalPullRequestContributions))
+	assert.Equal(t, 1, int(collection.TotalPullRequestReviewContributions))
+	assert.Equal(t, 2, collection.TotalRepositories())
+	assert.Equal(t, server.URL+"/q/project:platform%2Fbuild",
+		string(collection.CommitContributionsByRepository[0].Repository.URL))
+}
@@ -0,0 +1,110 @@
+package reporting
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// The host of GitHub's own accounts in the GitHub CLI's configuration
+const gitHubCLIHost = "github.com"
+
+// A GitHubCLI reads the tokens of the accounts the GitHub CLI is logged in to, so
+// users who already run gh auth login need no credentials file
+type GitHubCLI struct {
+	// The path of the gh executable
+	Path string
+	// The directory of gh's hosts.yml, defaulting to gh's own configuration directory
+	ConfigDir string
+}
+
+// Constructs a new GitHubCLI object with the gh executable on the PATH
+func NewGitHubCLI() *GitHubCLI {
+	return &GitHubCLI{Path: "gh"}
+}
+
+// gitHubCLIConfigDir finds gh's configuration directory the way gh does, from
+// GH_CONFIG_DIR, XDG_CONFIG_HOME, or the platform's default
+func gitHubCLIConfigDir() (string, error) {
+
+	if dir := os.Getenv("GH_CONFIG_DIR"); dir != "" {
+		return dir, nil
+	}
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "gh"), nil
+	}
+	if dir := os.Getenv("AppData"); runtime.GOOS == "windows" && dir != "" {
+		return filepath.Join(dir, "GitHub CLI"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "gh"), nil
+}
+
+// Credentials lists the accounts of every host gh is logged in to, sorted by host
+// and username, and asks gh for each one's token, so tokens kept in the OS keychain
+// by gh are read too. Accounts on hosts other than github.com are given the host's
+// URL, as GitHub Enterprise Servers.
+func (g *GitHubCLI) Credentials(ctx context.Context) (Credentials, error) {
+
+	configDir := g.ConfigDir
+	if configDir == "" {
+		var err error
+		configDir, err = gitHubCLIConfigDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to find the gh configuration: %w", err)
+		}
+	}
+	hostsYAML, err := os.ReadFile(filepath.Join(configDir, "hosts.yml"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the gh hosts, is gh logged in? %w", err)
+	}
+
+	// Each host names its active user, and gh 2.40 and later list every account
+	var hosts map[string]struct {
+		User  string         `yaml:"user"`
+		Users map[string]any `yaml:"users"`
+	}
+	err = yaml.Unmarshal(hostsYAML, &hosts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the gh hosts: %w", err)
+	}
+
+	var credentials Credentials
+	for _, host := range slices.Sorted(maps.Keys(hosts)) {
+		users := slices.Sorted(maps.Keys(hosts[host].Users))
+		if len(users) == 0 && hosts[host].User != "" {
+			users = []string{hosts[host].User}
+		}
+		for _, user := range users {
+			args := []string{"auth", "token", "--hostname", host}
+			// Older versions of gh don't know --user, but only have the active user
+			if user != hosts[host].User {
+				args = append(args, "--user", user)
+			}
+			output, err := exec.CommandContext(ctx, g.Path, args...).Output()
+			if err != nil {
+				return nil, fmt.Errorf("failed to read the gh token of %s on %s: %w", user, host, err)
+			}
+			credential := Credential{Username: user, Token: strings.TrimSpace(string(output))}
+			if host != gitHubCLIHost {
+				credential.URL = "https://" + host
+			}
+			credentials = append(credentials, credential)
+		}
+	}
+	if len(credentials) == 0 {
+		return nil, fmt.Errorf("gh is not logged in to any host")
+	}
+	return credentials, nil
+}
@@ -0,0 +1,59 @@
+package reporting_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test reading the tokens of the GitHub CLI's accounts on several hosts
+func TestGitHubCLICredentials(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not installed")
+	}
+
+	// A fake gh prints a token naming the host and user it was asked for
+	dir := t.TempDir()
+	ghPath := filepath.Join(dir, "gh")
+	require.NoError(t, os.WriteFile(ghPath, []byte(`#!/bin/sh
+[ "$1 $2 $3" = "auth token --hostname" ] || exit 1
+echo "token-$4-${6:-active}"
+`), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "hosts.yml"), []byte(`
+github.com:
+    users:
+        user2:
+        user1:
+    git_protocol: https
+    user: user1
+ghe.example.com:
+    git_protocol: ssh
+    user: user3
+`), 0o600))
+
+	gh := rpt.NewGitHubCLI()
+	gh.Path = ghPath
+	gh.ConfigDir = dir
+	credentials, err := gh.Credentials(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, rpt.Credentials{
+		{Username: "user3", Token: "token-ghe.example.com-active", URL: "https://ghe.example.com"},
+		{Username: "user1", Token: "token-github.com-active"},
+		{Username: "user2", Token: "token-github.com-user2"},
+	}, credentials)
+
+	// Ensure a missing configuration or a failing gh is an error
+	gh.ConfigDir = t.TempDir()
+	_, err = gh.Credentials(context.Background())
+	assert.Error(t, err)
+	gh.ConfigDir = dir
+	gh.Path = filepath.Join(dir, "missing")
+	_, err = gh.Credentials(context.Background())
+	assert.Error(t, err)
+}
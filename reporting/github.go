@@ -0,0 +1,246 @@
+package reporting
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// The GitHub REST API URL
+const gitHubRESTURL = "https://api.github.com"
+
+// A GitHub provider collects a user's contributions with the GitHub GraphQL API
+type GitHub struct {
+	// A client that implements the GraphQLClient interface like
+	// an authenticated Github Client using an OAuth token
+	Client GraphQLClient
+	// The HTTP client used for REST API requests, authenticated like the GraphQL client
+	HTTPClient *http.Client
+	// The REST API URL
+	RESTURL string
+	// Whether profiles list only the organizations the user is a public member of
+	PublicOrganizationsOnly bool
+	// Whether to query the stars, primary language, and description of each repository
+	RepositoryDetails bool
+	// How failed contributions queries are retried
+	RetryPolicy RetryPolicy
+	// When contributions queries pause for the rate limit
+	RateLimitPolicy RateLimitPolicy
+	// The most years of contributions asked for in one query, with an alias for each
+	YearsPerQuery int
+	// Whether to stop at the first year without any earlier activity, skipping
+	// any older years after a gap in the user's activity
+	StopAtInactiveYear bool
+	// The month the years queried start in, for fiscal years, or January for
+	// calendar years
+	FiscalYearStart time.Month
+	// The granularity of the periods queried, like GranularityQuarter, or
+	// years when blank
+	Granularity string
+	// Called after each contributions query with the number of years it
+	// collected and how long it took, like for a progress bar
+	OnQuery func(user string, years int, latency time.Duration)
+}
+
+// GitHubEnterpriseURLs returns the GraphQL and REST API URLs of a GitHub Enterprise
+// Server from its base URL, like https://ghe.example.com, or its GraphQL API URL,
+// like https://ghe.example.com/api/graphql
+func GitHubEnterpriseURLs(baseURL string) (graphQLURL string, restURL string) {
+	baseURL = strings.TrimSuffix(strings.TrimSuffix(baseURL, "/"), "/api/graphql")
+	return baseURL + "/api/graphql", baseURL + "/api/v3"
+}
+
+// Constructs a new GitHub object
+// The client is a pointer to a githubv4.Client object
+func NewGitHub(client GraphQLClient) (gitHub *GitHub, err error) {
+
+	if client == nil {
+		err = fmt.Errorf("the github client cannot be nil")
+		return nil, err
+	}
+
+	return &GitHub{
+		Client:          client,
+		HTTPClient:      http.DefaultClient,
+		RESTURL:         gitHubRESTURL,
+		RetryPolicy:     DefaultRetryPolicy(DefaultRetries),
+		RateLimitPolicy: DefaultRateLimitPolicy(),
+		YearsPerQuery:   DefaultYearsPerQuery,
+	}, err
+}
+
+// Collect queries the user's contributions collection a year at a time, newest
+// first, skipping the years before the user's account was created. Up to
+// YearsPerQuery years are asked for in each query. The years are fiscal years
+// when FiscalYearStart is after January, and are queried a quarter or a month
+// at a time for a finer Granularity, dating each contribution within its period.
+func (g *GitHub) Collect(ctx context.Context, user string, dateRange DateRange) ([]Contribution, error) {
+
+	// Query whole years, or their quarters or months, clipped to the range
+	periods := SplitPeriods(dateRange, g.Granularity, g.FiscalYearStart)
+
+	var contributions []Contribution
+	for len(periods) > 0 {
+		batch := periods[:min(max(g.YearsPerQuery, 1), len(periods))]
+		periods = periods[len(batch):]
+
+		var queryResults []QueryResult
+		var err error
+		start := time.Now()
+		if len(batch) == 1 {
+			var queryResult QueryResult
+			queryResult, err = queryContributions(ctx, g.Client, g.RetryPolicy, g.RateLimitPolicy, user,
+				batch[0].From, batch[0].To.Add(-time.Second), g.RepositoryDetails)
+			queryResults = []QueryResult{queryResult}
+		} else {
+			queryResults, err = queryContributionsBatch(ctx, g.Client, g.RetryPolicy, g.RateLimitPolicy, user,
+				batch, g.RepositoryDetails)
+		}
+		if err != nil {
+			return contributions, err
+		}
+		if g.OnQuery != nil {
+			// A year is done when its first period is, as they're queried newest first
+			years := 0
+			for _, period := range batch {
+				year := FiscalYear(period.From, g.FiscalYearStart)
+				if period.From.Equal(FiscalYearRange(year, year, g.FiscalYearStart).From) || period.From.Equal(dateRange.From) {
+					years++
+				}
+			}
+			g.OnQuery(user, years, time.Since(start))
+		}
+		for i, queryResult := range queryResults {
+			if queryResult.User.Login != "" {
+				if queryResult.User.ContributionsCollection.Truncated() {
+					slog.Warn("GitHub listed only some repositories, counting the rest from its totals",
+						"user", user, "from", batch[i].From.Format(time.DateOnly), "limit", MaxRepositories)
+				}
+				contributions = append(contributions, queryResult.User.ContributionsCollection.contributions(batch[i].From)...)
+			}
+			if g.StopAtInactiveYear && !bool(queryResult.User.ContributionsCollection.HasActivityInThePast) {
+				return contributions, nil
+			}
+			periods = yearsSince(periods, queryResult.User.CreatedAt.Time)
+		}
+	}
+	return contributions, nil
+}
+
+// yearsSince leaves out the years or periods, newest first, that ended before the time,
+// unless the time is zero
+func yearsSince(years []DateRange, t time.Time) []DateRange {
+	if t.IsZero() {
+		return years
+	}
+	for i, year := range years {
+		if !year.To.After(t) {
+			return years[:i]
+		}
+	}
+	return years
+}
+
+// queryContributions queries the user's contributions collection between two
+// times, with the details of each repository when asked, retrying and pausing for
+// the rate limit by the policies
+func queryContributions(ctx context.Context, client GraphQLClient, retryPolicy RetryPolicy,
+	rateLimitPolicy RateLimitPolicy, user string, from time.Time, to time.Time,
+	details bool) (queryResult QueryResult, err error) {
+
+	// Build a map of variable values
+	var variables = map[string]interface{}{
+		"login":   githubv4.String(user),
+		"from":    githubv4.DateTime{Time: from},
+		"to":      githubv4.DateTime{Time: to},
+		"details": githubv4.Boolean(details),
+	}
+
+	err = retryPolicy.Do(ctx, func() error {
+		queryResult = QueryResult{}
+		return client.Query(ctx, &queryResult, variables)
+	})
+	if err != nil {
+		return queryResult, fmt.Errorf("failed to query github for %s in %d: %w", user, from.Year(), err)
+	}
+	err = rateLimitPolicy.wait(ctx, user, queryResult.RateLimit)
+	if err != nil {
+		return queryResult, fmt.Errorf("failed to wait for the github rate limit to reset: %w", err)
+	}
+	return queryResult, nil
+}
+
+// contributions splits the collection into contributions dated at the start of
+// its range. GitHub lists at most MaxRepositories repositories for each kind, so
+// any remainder of a total is reported without a repository, along with the count
+// of repositories of the kind when some aren't listed.
+func (c ContributionsCollection) contributions(date time.Time) []Contribution {
+
+	var contributions []Contribution
+	kinds := []struct {
+		kind              ContributionKind
+		total             githubv4.Int
+		repositories      []RepositoryContribution
+		totalRepositories githubv4.Int
+	}{
+		{ContributionCommit, c.TotalCommitContributions, c.CommitContributionsByRepository,
+			c.TotalRepositoriesWithContributedCommits},
+		{ContributionIssue, c.TotalIssueContributions, c.IssueContributionsByRepository,
+			c.TotalRepositoriesWithContributedIssues},
+		{ContributionPullRequest, c.TotalPullRequestContributions, c.PullRequestContributionsByRepository,
+			c.TotalRepositoriesWithContributedPullRequests},
+		{ContributionPullRequestReview, c.TotalPullRequestReviewContributions, c.PullRequestReviewContributionsByRepository,
+			c.TotalRepositoriesWithContributedPullRequestReviews},
+		{ContributionRestricted, c.RestrictedContributionsCount, nil, 0},
+		{ContributionCalendarTotal, c.ContributionCalendar.TotalContributions, nil, 0},
+	}
+
+	for _, kind := range kinds {
+		remainder := int(kind.total)
+		for _, repository := range kind.repositories {
+			contributions = append(contributions, Contribution{
+				Kind:       kind.kind,
+				Repository: repository.repository(),
+				Date:       date,
+				Count:      int(repository.Contributions.TotalCount),
+			})
+			remainder -= int(repository.Contributions.TotalCount)
+		}
+		unlisted := int(kind.totalRepositories) > len(kind.repositories)
+		if remainder > 0 || unlisted {
+			contribution := Contribution{Kind: kind.kind, Date: date, Count: max(remainder, 0)}
+			if unlisted {
+				contribution.Repositories = int(kind.totalRepositories)
+			}
+			contributions = append(contributions, contribution)
+		}
+	}
+	return contributions
+}
+
+// SkipsInactiveYears reports whether the collection stops at the first year
+// without any activity before it
+func (g *GitHub) SkipsInactiveYears() bool {
+	return g.StopAtInactiveYear
+}
+
+// gitHubRepositoryPath returns the owner and name of a GitHub repository from
+// its URL, like https://github.com/owner/name
+func gitHubRepositoryPath(repository Repository) (owner string, name string, err error) {
+
+	repositoryURL, err := url.Parse(repository.URL)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to parse the repository url %q: %w", repository.URL, err)
+	}
+	owner, name, found := strings.Cut(strings.Trim(repositoryURL.Path, "/"), "/")
+	if !found || owner == "" || name == "" || strings.Contains(name, "/") {
+		return "", "", fmt.Errorf("the repository url %q is not a github repository", repository.URL)
+	}
+	return owner, name, nil
+}
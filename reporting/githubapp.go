@@ -0,0 +1,141 @@
+package reporting
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// A GitHubApp authenticates as a GitHub App installation rather than with a personal
+// access token. It signs a JSON Web Token with the App's private key to request an
+// installation token, which GitHub expires after an hour.
+type GitHubApp struct {
+	// The ID of the GitHub App
+	AppID int64
+	// The ID of the App's installation on the organization or account
+	InstallationID int64
+	// The private key generated for the App
+	Key *rsa.PrivateKey
+	// The REST API base URL, defaulting to api.github.com
+	RESTURL string
+	// The HTTP client used for the installation token requests
+	Client *http.Client
+}
+
+// Constructs a new GitHubApp object
+// The appID and installationID identify the App and its installation
+// The privateKey is the PEM encoded private key downloaded from the App's settings
+func NewGitHubApp(appID int64, installationID int64, privateKey []byte) (app *GitHubApp, err error) {
+
+	if appID == 0 || installationID == 0 {
+		err = fmt.Errorf("the github app and installation ids cannot be blank")
+		return nil, err
+	}
+	block, _ := pem.Decode(privateKey)
+	if block == nil {
+		err = fmt.Errorf("the github app private key is not PEM encoded")
+		return nil, err
+	}
+
+	// GitHub downloads PKCS #1 keys, but converted PKCS #8 keys work too
+	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		parsed, pkcs8Err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if pkcs8Err != nil {
+			return nil, fmt.Errorf("failed to parse the github app private key: %w", err)
+		}
+		var isRSA bool
+		key, isRSA = parsed.(*rsa.PrivateKey)
+		if !isRSA {
+			return nil, fmt.Errorf("the github app private key is not an RSA key")
+		}
+	}
+
+	return &GitHubApp{
+		AppID:          appID,
+		InstallationID: installationID,
+		Key:            key,
+		RESTURL:        gitHubRESTURL,
+		Client:         http.DefaultClient,
+	}, nil
+}
+
+// TokenSource returns a token source that reuses the installation token, requesting
+// a new one shortly before it expires, so long collections keep authenticating
+func (a *GitHubApp) TokenSource() oauth2.TokenSource {
+	return oauth2.ReuseTokenSourceWithExpiry(nil, a, time.Minute)
+}
+
+// Token requests a new installation token
+func (a *GitHubApp) Token() (*oauth2.Token, error) {
+
+	webToken, err := a.webToken(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	requestURL := strings.TrimSuffix(a.RESTURL, "/") + "/app/installations/" +
+		strconv.FormatInt(a.InstallationID, 10) + "/access_tokens"
+	request, err := http.NewRequest(http.MethodPost, requestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Accept", "application/vnd.github+json")
+	request.Header.Set("Authorization", "Bearer "+webToken)
+
+	response, err := a.Client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request the github app installation token: %w", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("failed to request the github app installation token: github returned %s", response.Status)
+	}
+
+	var tokenResponse struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	err = json.NewDecoder(response.Body).Decode(&tokenResponse)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the github app installation token: %w", err)
+	}
+	return &oauth2.Token{
+		AccessToken: tokenResponse.Token,
+		TokenType:   "Bearer",
+		Expiry:      tokenResponse.ExpiresAt,
+	}, nil
+}
+
+// webToken signs the JSON Web Token authenticating as the App, issued a minute in
+// the past to allow for clock drift and expiring before GitHub's ten minute limit
+func (a *GitHubApp) webToken(now time.Time) (string, error) {
+
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`))
+	claims, err := json.Marshal(map[string]any{
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(9 * time.Minute).Unix(),
+		"iss": strconv.FormatInt(a.AppID, 10),
+	})
+	if err != nil {
+		return "", err
+	}
+	signed := header + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, a.Key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign the github app token: %w", err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
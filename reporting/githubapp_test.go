@@ -0,0 +1,103 @@
+package reporting_test
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test the NewGitHubApp constructor with PKCS #1 and PKCS #8 keys
+func TestNewGitHubApp(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	pkcs1 := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	pkcs8Bytes, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	pkcs8 := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8Bytes})
+
+	for _, privateKey := range [][]byte{pkcs1, pkcs8} {
+		app, err := rpt.NewGitHubApp(1, 2, privateKey)
+		require.NoError(t, err)
+		assert.True(t, key.Equal(app.Key))
+		assert.Equal(t, "https://api.github.com", app.RESTURL)
+	}
+
+	_, err = rpt.NewGitHubApp(0, 2, pkcs1)
+	assert.Error(t, err)
+	_, err = rpt.NewGitHubApp(1, 2, []byte("not a key"))
+	assert.Error(t, err)
+}
+
+// Test requesting and reusing installation tokens
+func TestGitHubAppTokenSource(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, http.MethodPost, r.Method)
+		if r.URL.Path != "/app/installations/42/access_tokens" {
+			http.Error(w, `{"message": "Not Found"}`, http.StatusNotFound)
+			return
+		}
+
+		// Ensure the JSON Web Token is signed by the App's key and issued by it
+		webToken, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		require.True(t, found)
+		parts := strings.Split(webToken, ".")
+		require.Len(t, parts, 3)
+		signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+		require.NoError(t, err)
+		digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		assert.NoError(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature))
+		claimBytes, err := base64.RawURLEncoding.DecodeString(parts[1])
+		require.NoError(t, err)
+		var claims struct {
+			Issuer    string `json:"iss"`
+			ExpiresAt int64  `json:"exp"`
+		}
+		require.NoError(t, json.Unmarshal(claimBytes, &claims))
+		assert.Equal(t, "7", claims.Issuer)
+		assert.LessOrEqual(t, claims.ExpiresAt, time.Now().Add(10*time.Minute).Unix())
+
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]any{
+			"token":      "installation-token",
+			"expires_at": time.Now().Add(time.Hour).UTC().Format(time.RFC3339),
+		})
+	}))
+	defer server.Close()
+
+	pemKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	app, err := rpt.NewGitHubApp(7, 42, pemKey)
+	require.NoError(t, err)
+	app.RESTURL = server.URL
+
+	// Ensure the token is reused until it nears its expiry
+	source := app.TokenSource()
+	for range 2 {
+		token, err := source.Token()
+		require.NoError(t, err)
+		assert.Equal(t, "installation-token", token.AccessToken)
+	}
+	assert.Equal(t, 1, requests)
+
+	app.InstallationID = 43
+	_, err = app.Token()
+	assert.Error(t, err)
+}
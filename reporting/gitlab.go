@@ -0,0 +1,209 @@
+package reporting
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The GitLab.com API, used when a credential doesn't name a self-managed instance
+const DefaultGitLabURL = "https://gitlab.com"
+
+// A GitLab provider collects a user's contributions from their GitLab events.
+// GitLab keeps events for three years, so earlier years report nothing.
+type GitLab struct {
+	// The base URL of the GitLab instance
+	BaseURL string
+	// A personal access token with the read_api scope
+	Token string
+	// The HTTP client used for the API requests
+	Client *http.Client
+	// The projects looked up so far, by ID
+	projects map[int]gitLabProject
+}
+
+// A gitLabEvent holds the fields of a GitLab user event used to count contributions
+type gitLabEvent struct {
+	ProjectID  int       `json:"project_id"`
+	ActionName string    `json:"action_name"`
+	TargetType string    `json:"target_type"`
+	CreatedAt  time.Time `json:"created_at"`
+	PushData   *struct {
+		CommitCount int `json:"commit_count"`
+	} `json:"push_data"`
+	Note *struct {
+		NoteableType string `json:"noteable_type"`
+	} `json:"note"`
+}
+
+// A gitLabProject holds the name and URL of a GitLab project
+type gitLabProject struct {
+	PathWithNamespace string `json:"path_with_namespace"`
+	WebURL            string `json:"web_url"`
+	Archived          bool   `json:"archived"`
+	// The project it was forked from, only for forks
+	ForkedFromProject *struct{} `json:"forked_from_project"`
+}
+
+// Constructs a new GitLab object
+// The baseURL is the GitLab instance, defaulting to gitlab.com
+// The token is a personal access token with the read_api scope
+func NewGitLab(baseURL string, token string) (gitLab *GitLab, err error) {
+
+	if token == "" {
+		err = fmt.Errorf("the gitlab token cannot be blank")
+		return nil, err
+	}
+	if baseURL == "" {
+		baseURL = DefaultGitLabURL
+	}
+
+	return &GitLab{
+		BaseURL:  strings.TrimSuffix(baseURL, "/"),
+		Token:    token,
+		Client:   http.DefaultClient,
+		projects: make(map[int]gitLabProject),
+	}, err
+}
+
+// Collect counts the user's pushed commits, opened issues, opened merge requests,
+// and merge request approvals and comments
+func (g *GitLab) Collect(ctx context.Context, user string, dateRange DateRange) ([]Contribution, error) {
+
+	var contributions []Contribution
+
+	slog.Debug("Fetching gitlab events", "user", user)
+
+	var userIDs []struct {
+		ID int `json:"id"`
+	}
+	err := g.get(ctx, "/api/v4/users?username="+url.QueryEscape(user), &userIDs)
+	if err != nil {
+		return contributions, err
+	}
+	if len(userIDs) == 0 {
+		return contributions, fmt.Errorf("the gitlab user %s was not found", user)
+	}
+
+	// The after and before dates are exclusive
+	query := url.Values{}
+	query.Set("after", dateRange.From.AddDate(0, 0, -1).Format(time.DateOnly))
+	query.Set("before", dateRange.To.Format(time.DateOnly))
+	query.Set("per_page", "100")
+
+	for page := "1"; page != ""; {
+		query.Set("page", page)
+		var events []gitLabEvent
+		page, err = g.getPage(ctx, fmt.Sprintf("/api/v4/users/%d/events?%s", userIDs[0].ID, query.Encode()), &events)
+		if err != nil {
+			return contributions, err
+		}
+
+		for _, event := range events {
+			kind := gitLabContributionKind(event)
+			if kind == "" || !dateRange.Contains(event.CreatedAt) {
+				continue
+			}
+			project, err := g.project(ctx, event.ProjectID)
+			if err != nil {
+				return contributions, err
+			}
+			count := 1
+			if event.PushData != nil {
+				count = event.PushData.CommitCount
+			}
+			repository := Repository{Name: project.PathWithNamespace, URL: project.WebURL, IsArchived: project.Archived,
+				IsFork: project.ForkedFromProject != nil}
+			contributions = append(contributions, Contribution{
+				Kind:       kind,
+				Repository: repository,
+				Date:       event.CreatedAt,
+				Count:      count,
+			})
+		}
+	}
+	return contributions, nil
+}
+
+// gitLabContributionKind returns the kind of contribution an event counts as,
+// or a blank kind for events that aren't counted
+func gitLabContributionKind(event gitLabEvent) ContributionKind {
+	switch {
+	case event.PushData != nil:
+		return ContributionCommit
+	case event.ActionName == "opened" && event.TargetType == "Issue":
+		return ContributionIssue
+	case event.ActionName == "opened" && event.TargetType == "MergeRequest":
+		return ContributionPullRequest
+	case event.ActionName == "approved" && event.TargetType == "MergeRequest":
+		return ContributionPullRequestReview
+	case event.Note != nil && event.Note.NoteableType == "MergeRequest":
+		return ContributionPullRequestReview
+	default:
+		return ""
+	}
+}
+
+// project looks up a project's name and URL, once per project. Projects that
+// are no longer visible are named by their ID.
+func (g *GitLab) project(ctx context.Context, id int) (gitLabProject, error) {
+	if project, found := g.projects[id]; found {
+		return project, nil
+	}
+
+	var project gitLabProject
+	err := g.get(ctx, "/api/v4/projects/"+strconv.Itoa(id), &project)
+	if errors.Is(err, errGitLabNotFound) {
+		project = gitLabProject{PathWithNamespace: "project-" + strconv.Itoa(id)}
+	} else if err != nil {
+		return project, err
+	}
+	g.projects[id] = project
+	return project, nil
+}
+
+// get requests a single GitLab API path and decodes the JSON response
+func (g *GitLab) get(ctx context.Context, path string, value any) error {
+	_, err := g.getPage(ctx, path, value)
+	return err
+}
+
+// getPage requests a GitLab API path, decodes the JSON response, and returns
+// the next page number, or a blank string on the last page
+func (g *GitLab) getPage(ctx context.Context, path string, value any) (nextPage string, err error) {
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, g.BaseURL+path, nil)
+	if err != nil {
+		return "", err
+	}
+	request.Header.Set("PRIVATE-TOKEN", g.Token)
+
+	response, err := g.Client.Do(request)
+	if err != nil {
+		return "", fmt.Errorf("failed to query gitlab: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound {
+		return "", errGitLabNotFound
+	}
+	if response.StatusCode/100 != 2 {
+		return "", fmt.Errorf("failed to query gitlab: gitlab returned %s", response.Status)
+	}
+
+	err = json.NewDecoder(response.Body).Decode(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse the gitlab response: %w", err)
+	}
+	return response.Header.Get("X-Next-Page"), nil
+}
+
+// errGitLabNotFound is returned for resources the token can't see
+var errGitLabNotFound = errors.New("the gitlab resource was not found")
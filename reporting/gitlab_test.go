@@ -0,0 +1,91 @@
+package reporting_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the NewGitLab constructor
+func TestNewGitLab(t *testing.T) {
+	_, err := rpt.NewGitLab("", "")
+	assert.Error(t, err)
+
+	gitLab, err := rpt.NewGitLab("", "token")
+	assert.NoError(t, err)
+	assert.Equal(t, rpt.DefaultGitLabURL, gitLab.BaseURL)
+}
+
+// Test collecting contributions from GitLab events
+func TestGitLabCollect(t *testing.T) {
+	pages := map[string]string{
+		"1": `[
+			{"project_id": 1, "action_name": "pushed to", "created_at": "2024-03-01T10:00:00Z", "push_data": {"commit_count": 3}},
+			{"project_id": 1, "action_name": "opened", "target_type": "Issue", "created_at": "2024-03-02T10:00:00Z"},
+			{"project_id": 2, "action_name": "opened", "target_type": "MergeRequest", "created_at": "2024-03-03T10:00:00Z"}
+		]`,
+		"2": `[
+			{"project_id": 2, "action_name": "approved", "target_type": "MergeRequest", "created_at": "2023-05-01T10:00:00Z"},
+			{"project_id": 3, "action_name": "commented on", "target_type": "DiffNote", "created_at": "2023-05-02T10:00:00Z", "note": {"noteable_type": "MergeRequest"}},
+			{"project_id": 1, "action_name": "pushed new", "created_at": "2023-05-03T10:00:00Z", "push_data": {"commit_count": 2}}
+		]`,
+	}
+	var token, after, before string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token = r.Header.Get("PRIVATE-TOKEN")
+		switch r.URL.Path {
+		case "/api/v4/users":
+			assert.Equal(t, "user1", r.URL.Query().Get("username"))
+			w.Write([]byte(`[{"id": 42}]`))
+		case "/api/v4/users/42/events":
+			after, before = r.URL.Query().Get("after"), r.URL.Query().Get("before")
+			page := r.URL.Query().Get("page")
+			if page == "1" {
+				w.Header().Set("X-Next-Page", "2")
+			}
+			w.Write([]byte(pages[page]))
+		case "/api/v4/projects/1", "/api/v4/projects/2":
+			name := "group/project" + r.URL.Path[len(r.URL.Path)-1:]
+			json.NewEncoder(w).Encode(map[string]string{
+				"path_with_namespace": name,
+				"web_url":             "https://gitlab.example.com/" + name,
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	gitLab, err := rpt.NewGitLab(server.URL, "token")
+	assert.NoError(t, err)
+	collector, err := rpt.NewProviderCollector(gitLab, "user1", 2023, 2024)
+	assert.NoError(t, err)
+	queryResults, err := collector.Collect(context.Background())
+	assert.NoError(t, err)
+
+	assert.Equal(t, "token", token)
+	assert.Equal(t, "2022-12-31", after)
+	assert.Equal(t, "2025-01-01", before)
+	assert.Len(t, queryResults, 2)
+
+	collection := queryResults["user1-2024"].User.ContributionsCollection
+	assert.Equal(t, 3, int(collection.TotalCommitContributions))
+	assert.Equal(t, 1, int(collection.TotalIssueContributions))
+	assert.Equal(t, 1, int(collection.TotalPullRequestContributions))
+	assert.Equal(t, 2, collection.TotalRepositories())
+	assert.Equal(t, "https://gitlab.example.com/group/project1",
+		string(collection.CommitContributionsByRepository[0].Repository.URL))
+
+	// Ensure reviews on a project that is no longer visible are still counted
+	collection = queryResults["user1-2023"].User.ContributionsCollection
+	assert.Equal(t, 2, int(collection.TotalCommitContributions))
+	assert.Equal(t, 2, int(collection.TotalPullRequestReviewContributions))
+	assert.Equal(t, 3, collection.TotalRepositories())
+	assert.Equal(t, "project-3",
+		string(collection.PullRequestReviewContributionsByRepository[1].Repository.Name))
+}
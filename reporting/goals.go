@@ -0,0 +1,139 @@
+package reporting
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// The width of a goal's progress bar, in characters
+const goalProgressBarWidth = 20
+
+// A Goal sets a target for a metric over each calendar period
+type Goal struct {
+	// The metric: commits, issues, pull_requests, reviews, repositories, or other
+	Metric string `json:"metric"`
+	// The target for each period
+	Target int `json:"target"`
+	// The period: year, quarter, month, or week
+	Period string `json:"period"`
+}
+
+// A GoalProgress holds the progress toward a goal in the current period
+type GoalProgress struct {
+	Goal
+	// The dates of the current period
+	Dates string `json:"dates"`
+	// The metric's value so far in the period
+	Actual int `json:"actual"`
+	// The value expected by now, at a steady pace toward the target
+	Expected int `json:"expected"`
+	// Whether the value so far is below the expected value
+	Behind bool `json:"behind"`
+}
+
+// The metrics a goal can target, by name
+var goalMetrics = map[string]func(collection ContributionsCollection) int{
+	"commits":       func(c ContributionsCollection) int { return int(c.TotalCommitContributions) },
+	"issues":        func(c ContributionsCollection) int { return int(c.TotalIssueContributions) },
+	"pull_requests": func(c ContributionsCollection) int { return int(c.TotalPullRequestContributions) },
+	"reviews":       func(c ContributionsCollection) int { return int(c.TotalPullRequestReviewContributions) },
+	"other": func(c ContributionsCollection) int {
+		return int(c.TotalIssueContributions + c.TotalPullRequestContributions + c.TotalPullRequestReviewContributions)
+	},
+}
+
+// ParseGoals parses and validates a JSON list of goals
+func ParseGoals(data []byte) ([]Goal, error) {
+
+	var goals []Goal
+	err := json.Unmarshal(data, &goals)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the goals: %w", err)
+	}
+	for _, goal := range goals {
+		if _, found := goalMetrics[goal.Metric]; !found && goal.Metric != "repositories" {
+			return nil, fmt.Errorf("the goal metric %q must be commits, issues, pull_requests, "+
+				"reviews, repositories, or other", goal.Metric)
+		}
+		if goal.Target <= 0 {
+			return nil, fmt.Errorf("the %s goal target must be positive", goal.Metric)
+		}
+		_, err = goal.Range(time.Now())
+		if err != nil {
+			return nil, err
+		}
+	}
+	return goals, nil
+}
+
+// Range returns the calendar period containing the time, in UTC. Weeks start on Monday.
+func (g Goal) Range(now time.Time) (DateRange, error) {
+
+	now = now.UTC()
+	year, month, day := now.Date()
+	switch g.Period {
+	case "year":
+		return YearRange(year, year), nil
+	case "quarter":
+		from := time.Date(year, month-(month-1)%3, 1, 0, 0, 0, 0, time.UTC)
+		return DateRange{From: from, To: from.AddDate(0, 3, 0)}, nil
+	case "month":
+		from := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+		return DateRange{From: from, To: from.AddDate(0, 1, 0)}, nil
+	case "week":
+		from := time.Date(year, month, day-(int(now.Weekday())+6)%7, 0, 0, 0, 0, time.UTC)
+		return DateRange{From: from, To: from.AddDate(0, 0, 7)}, nil
+	default:
+		return DateRange{}, fmt.Errorf("the %s goal period %q must be year, quarter, month, or week", g.Metric, g.Period)
+	}
+}
+
+// Progress measures the progress toward the goal from the query results collected
+// over the goal's current period, comparing it with a steady pace to the target
+func (g Goal) Progress(queryResults map[string]QueryResult, now time.Time) GoalProgress {
+	return (&Reporter{}).GoalProgress(g, queryResults, now)
+}
+
+// GoalProgress measures the progress toward the goal like Goal.Progress, with the
+// repositories and other contributions counted like the Reporter's totals
+func (r *Reporter) GoalProgress(g Goal, queryResults map[string]QueryResult, now time.Time) GoalProgress {
+
+	dateRange, _ := g.Range(now)
+	progress := GoalProgress{Goal: g, Dates: dateRange.String()}
+	switch g.Metric {
+	case "repositories":
+		progress.Actual = r.totals(queryResults).TotalRepositories
+	case "other":
+		progress.Actual = r.totals(queryResults).TotalOtherContributions
+	default:
+		for _, queryResult := range queryResults {
+			progress.Actual += goalMetrics[g.Metric](queryResult.User.ContributionsCollection)
+		}
+	}
+
+	elapsed := now.Sub(dateRange.From).Seconds() / dateRange.To.Sub(dateRange.From).Seconds()
+	progress.Expected = int(math.Floor(float64(g.Target) * min(max(elapsed, 0), 1)))
+	progress.Behind = progress.Actual < progress.Expected
+	return progress
+}
+
+// Bar renders the progress as a text bar, like [#####---------------] 25/100 (25%)
+func (g GoalProgress) Bar() string {
+	ratio := min(float64(g.Actual)/float64(g.Target), 1)
+	filled := int(math.Round(ratio * goalProgressBarWidth))
+	return fmt.Sprintf("[%s%s] %d/%d (%d%%)", strings.Repeat("#", filled),
+		strings.Repeat("-", goalProgressBarWidth-filled), g.Actual, g.Target,
+		int(math.Round(float64(g.Actual)/float64(g.Target)*100)))
+}
+
+// String describes the progress toward the goal on a single line
+func (g GoalProgress) String() string {
+	status := "on track"
+	if g.Behind {
+		status = fmt.Sprintf("behind, %d expected by now", g.Expected)
+	}
+	return fmt.Sprintf("%d %s per %s: %s %s", g.Target, strings.ReplaceAll(g.Metric, "_", " "), g.Period, g.Bar(), status)
+}
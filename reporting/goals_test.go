@@ -0,0 +1,99 @@
+package reporting_test
+
+import (
+	"testing"
+	"time"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test parsing and validating goals
+func TestParseGoals(t *testing.T) {
+	goals, err := rpt.ParseGoals([]byte(`[
+		{"metric": "commits", "target": 500, "period": "year"},
+		{"metric": "reviews", "target": 50, "period": "quarter"}
+	]`))
+	require.NoError(t, err)
+	assert.Equal(t, []rpt.Goal{
+		{Metric: "commits", Target: 500, Period: "year"},
+		{Metric: "reviews", Target: 50, Period: "quarter"},
+	}, goals)
+
+	tests := []struct {
+		name   string
+		data   string
+		errMsg string
+	}{
+		{name: "unknown metric", data: `[{"metric": "stars", "target": 1, "period": "year"}]`, errMsg: "goal metric"},
+		{name: "zero target", data: `[{"metric": "commits", "target": 0, "period": "year"}]`, errMsg: "must be positive"},
+		{name: "unknown period", data: `[{"metric": "commits", "target": 1, "period": "decade"}]`, errMsg: "goal period"},
+		{name: "invalid json", data: `{`, errMsg: "failed to parse the goals"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := rpt.ParseGoals([]byte(test.data))
+			assert.ErrorContains(t, err, test.errMsg)
+		})
+	}
+}
+
+// Test the calendar period of each goal
+func TestGoalRange(t *testing.T) {
+	// A Thursday in the third quarter
+	now := time.Date(2024, time.August, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		period string
+		from   time.Time
+		to     time.Time
+	}{
+		{"year", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"quarter", time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)},
+		{"month", time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)},
+		{"week", time.Date(2024, 8, 12, 0, 0, 0, 0, time.UTC), time.Date(2024, 8, 19, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, test := range tests {
+		t.Run(test.period, func(t *testing.T) {
+			dateRange, err := rpt.Goal{Metric: "commits", Target: 1, Period: test.period}.Range(now)
+			require.NoError(t, err)
+			assert.Equal(t, test.from, dateRange.From)
+			assert.Equal(t, test.to, dateRange.To)
+		})
+	}
+}
+
+// Test measuring the progress toward a goal against a steady pace
+func TestGoalProgress(t *testing.T) {
+	queryResults, err := loadQueryResultsMap("single_user_single_year.json")
+	require.NoError(t, err)
+
+	// Halfway through the year, 10 commits is behind a pace of 100 a year
+	now := time.Date(2023, time.July, 2, 12, 0, 0, 0, time.UTC)
+	progress := rpt.Goal{Metric: "commits", Target: 100, Period: "year"}.Progress(queryResults, now)
+	assert.Equal(t, "2023-01-01..2023-12-31", progress.Dates)
+	assert.Equal(t, 10, progress.Actual)
+	assert.Equal(t, 50, progress.Expected)
+	assert.True(t, progress.Behind)
+	assert.Equal(t, "[##------------------] 10/100 (10%)", progress.Bar())
+	assert.Contains(t, progress.String(), "100 commits per year")
+	assert.Contains(t, progress.String(), "behind, 50 expected by now")
+
+	// A goal already met is on track and the bar stops at full
+	progress = rpt.Goal{Metric: "repositories", Target: 1, Period: "year"}.Progress(queryResults, now)
+	assert.False(t, progress.Behind)
+	assert.Equal(t, "[####################] 1/1 (100%)", progress.Bar())
+	assert.Contains(t, progress.String(), "on track")
+}
+
+// Test the goals in the summary text
+func TestSummaryGoals(t *testing.T) {
+	current := rpt.AggregatedResults{
+		Goals: []rpt.GoalProgress{{
+			Goal:   rpt.Goal{Metric: "pull_requests", Target: 20, Period: "month"},
+			Actual: 5, Expected: 10, Behind: true,
+		}},
+	}
+	text := rpt.Summarize(current, nil).Text()
+	assert.Contains(t, text, "Goals:\n20 pull requests per month: [#####---------------] 5/20 (25%) behind")
+}
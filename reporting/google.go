@@ -0,0 +1,23 @@
+package reporting
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+// NewGoogleClient creates an HTTP client authenticated with Google Application
+// Default Credentials (ADC) for the given OAuth scopes. ADC checks the
+// GOOGLE_APPLICATION_CREDENTIALS file, the gcloud user credentials, and the
+// metadata server when running on Google Cloud.
+func NewGoogleClient(ctx context.Context, scopes ...string) (*http.Client, error) {
+
+	credentials, err := google.FindDefaultCredentials(ctx, scopes...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find google application default credentials: %w", err)
+	}
+	return oauth2.NewClient(ctx, credentials.TokenSource), nil
+}
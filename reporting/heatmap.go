@@ -0,0 +1,129 @@
+package reporting
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"time"
+)
+
+// The size of a heatmap day's square and the gap between squares, in pixels
+const (
+	heatmapCell = 10
+	heatmapGap  = 3
+)
+
+// The heatmap's space for the month and weekday labels, in pixels
+const (
+	heatmapLeft = 30
+	heatmapTop  = 20
+)
+
+// The colors of the heatmap's levels, from no contributions to the most
+var heatmapColors = []string{"#ebedf0", "#9be9a8", "#40c463", "#30a14e", "#216e39"}
+
+// HeatmapRange returns the range of a heatmap ending on the day of the time,
+// starting on the Sunday 52 weeks before, like GitHub's contribution graph
+func HeatmapRange(now time.Time) DateRange {
+	year, month, day := now.UTC().Date()
+	to := time.Date(year, month, day+1, 0, 0, 0, 0, time.UTC)
+	from := to.AddDate(0, 0, -1-52*7-int(now.UTC().Weekday()))
+	return DateRange{From: from, To: to}
+}
+
+// MergeContributionDays adds up the counts of each day, like those of several
+// accounts, sorted by date
+func MergeContributionDays(days []ContributionDay) []ContributionDay {
+
+	var counts = make(map[time.Time]int)
+	for _, day := range days {
+		counts[day.Date.UTC().Truncate(24*time.Hour)] += day.Count
+	}
+	var merged []ContributionDay
+	for _, date := range slices.SortedFunc(maps.Keys(counts), func(a, b time.Time) int { return a.Compare(b) }) {
+		merged = append(merged, ContributionDay{Date: date, Count: counts[date]})
+	}
+	return merged
+}
+
+// WriteHeatmap writes a GitHub-style SVG heatmap of the daily counts over the
+// range, with a column for each week starting on Sunday. The days are shaded in
+// four levels split at the quartiles of the days with any contributions.
+func WriteHeatmap(w io.Writer, days []ContributionDay, dateRange DateRange) error {
+
+	days = MergeContributionDays(days)
+	var counts = make(map[time.Time]int)
+	var nonZero []int
+	total := 0
+	for _, day := range days {
+		if dateRange.Contains(day.Date) {
+			counts[day.Date] = day.Count
+			total += day.Count
+			if day.Count > 0 {
+				nonZero = append(nonZero, day.Count)
+			}
+		}
+	}
+	slices.Sort(nonZero)
+	level := func(count int) int {
+		if count <= 0 {
+			return 0
+		}
+		for i := 1; i < 4; i++ {
+			if count <= nonZero[len(nonZero)*i/4] {
+				return i
+			}
+		}
+		return 4
+	}
+
+	// Start the first column on the Sunday on or before the range
+	start := dateRange.From.UTC().Truncate(24 * time.Hour)
+	start = start.AddDate(0, 0, -int(start.Weekday()))
+	weeks := int(dateRange.To.Sub(start).Hours()/24+6) / 7
+	step := heatmapCell + heatmapGap
+	width := heatmapLeft + weeks*step
+	height := heatmapTop + 7*step
+
+	var svg bytes.Buffer
+	fmt.Fprintf(&svg, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" `+
+		`font-family="sans-serif" font-size="9" fill="#656d76">`+"\n", width, height, width, height)
+	fmt.Fprintf(&svg, "<title>%d contributions from %s</title>\n", total, dateRange)
+	for row, label := range []string{1: "Mon", 3: "Wed", 5: "Fri"} {
+		if label != "" {
+			fmt.Fprintf(&svg, `<text x="0" y="%d">%s</text>`+"\n", heatmapTop+row*step+heatmapCell-1, label)
+		}
+	}
+
+	previousMonth := time.Month(0)
+	for week := range weeks {
+		sunday := start.AddDate(0, 0, week*7)
+		x := heatmapLeft + week*step
+
+		// Label the first week of each month
+		if sunday.Month() != previousMonth && week < weeks-2 {
+			fmt.Fprintf(&svg, `<text x="%d" y="%d">%s</text>`+"\n", x, heatmapTop-8, sunday.Format("Jan"))
+			previousMonth = sunday.Month()
+		}
+		for weekday := range 7 {
+			date := sunday.AddDate(0, 0, weekday)
+			if !dateRange.Contains(date) {
+				continue
+			}
+			count := counts[date]
+			fmt.Fprintf(&svg, `<rect x="%d" y="%d" width="%d" height="%d" rx="2" fill="%s">`+
+				`<title>%d contributions on %s</title></rect>`+"\n",
+				x, heatmapTop+weekday*step, heatmapCell, heatmapCell, heatmapColors[level(count)],
+				count, date.Format(time.DateOnly))
+		}
+	}
+	svg.WriteString("</svg>\n")
+
+	_, err := w.Write(svg.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to write the heatmap: %w", err)
+	}
+	return nil
+}
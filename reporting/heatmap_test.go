@@ -0,0 +1,60 @@
+package reporting_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test the range of a heatmap
+func TestHeatmapRange(t *testing.T) {
+	dateRange := rpt.HeatmapRange(time.Date(2024, time.May, 15, 18, 0, 0, 0, time.UTC))
+	assert.Equal(t, "2023-05-14..2024-05-15", dateRange.String())
+	assert.Equal(t, time.Sunday, dateRange.From.Weekday())
+}
+
+// Test adding up the days of several accounts
+func TestMergeContributionDays(t *testing.T) {
+	day := func(date string, count int) rpt.ContributionDay {
+		parsed, _ := time.Parse(time.DateOnly, date)
+		return rpt.ContributionDay{Date: parsed, Count: count}
+	}
+	merged := rpt.MergeContributionDays([]rpt.ContributionDay{
+		day("2024-01-02", 3), day("2024-01-01", 1), day("2024-01-02", 4),
+	})
+	assert.Equal(t, []rpt.ContributionDay{day("2024-01-01", 1), day("2024-01-02", 7)}, merged)
+}
+
+// Test writing the SVG heatmap
+func TestWriteHeatmap(t *testing.T) {
+	dateRange := rpt.HeatmapRange(time.Date(2024, time.May, 15, 0, 0, 0, 0, time.UTC))
+	var days []rpt.ContributionDay
+	for i, count := range []int{1, 2, 3, 4, 8} {
+		days = append(days, rpt.ContributionDay{Date: time.Date(2024, time.March, 1+i, 0, 0, 0, 0, time.UTC), Count: count})
+	}
+	days = append(days, rpt.ContributionDay{Date: time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC), Count: 2})
+	days = append(days, rpt.ContributionDay{Date: time.Date(2020, time.March, 1, 0, 0, 0, 0, time.UTC), Count: 100})
+
+	var svg bytes.Buffer
+	require.NoError(t, rpt.WriteHeatmap(&svg, days, dateRange))
+	heatmap := svg.String()
+	assert.True(t, strings.HasPrefix(heatmap, `<svg xmlns="http://www.w3.org/2000/svg"`))
+	assert.True(t, strings.HasSuffix(heatmap, "</svg>\n"))
+	assert.Contains(t, heatmap, "<title>20 contributions from 2023-05-14..2024-05-15</title>")
+
+	// Ensure a day for each date in the range, shaded by the quartiles
+	assert.Equal(t, 368, strings.Count(heatmap, "<rect "))
+	assert.Contains(t, heatmap, `fill="#9be9a8"><title>1 contributions on 2024-03-01</title>`)
+	assert.Contains(t, heatmap, `fill="#9be9a8"><title>2 contributions on 2024-03-02</title>`)
+	assert.Contains(t, heatmap, `fill="#40c463"><title>3 contributions on 2024-03-03</title>`)
+	assert.Contains(t, heatmap, `fill="#30a14e"><title>4 contributions on 2024-03-04</title>`)
+	assert.Contains(t, heatmap, `fill="#216e39"><title>10 contributions on 2024-03-05</title>`)
+	assert.Contains(t, heatmap, `fill="#ebedf0"><title>0 contributions on 2024-05-15</title>`)
+	assert.Contains(t, heatmap, ">Mon</text>")
+	assert.Contains(t, heatmap, ">Jun</text>")
+}
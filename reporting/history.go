@@ -0,0 +1,85 @@
+package reporting
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// The periods a history can be summarized by
+var historyPeriods = map[string]string{
+	"run":   time.DateTime,
+	"day":   time.DateOnly,
+	"month": "2006-01",
+	"year":  "2006",
+}
+
+// A HistoryPoint holds the totals of the last run in a period, like a month,
+// and their change since the period before
+type HistoryPoint struct {
+	// The period, like 2024-06 for a month
+	Period string `json:"period"`
+	// The time of the period's last run, in Unix seconds
+	Timestamp int `json:"timestamp"`
+	Totals
+	// The change since the period before, if any
+	Change *Totals `json:"change,omitempty"`
+}
+
+// History summarizes how the totals changed over the snapshots, with a point for
+// the last run in each period, either run, day, month, or year, oldest first. The
+// totals are of a single user when the user isn't blank, and the runs without the
+// user are skipped.
+func History(snapshots []Snapshot, period string, user string) ([]HistoryPoint, error) {
+
+	layout, ok := historyPeriods[period]
+	if !ok {
+		return nil, fmt.Errorf("unknown history period %q, expected run, day, month, or year", period)
+	}
+
+	var points []HistoryPoint
+	for _, snapshot := range snapshots {
+		totals := snapshot.Results.Totals()
+		if user != "" {
+			userTotals, found := snapshot.Results.ByUser[user]
+			if !found {
+				continue
+			}
+			totals = userTotals
+		}
+		point := HistoryPoint{
+			Period:    time.Unix(int64(snapshot.Timestamp), 0).UTC().Format(layout),
+			Timestamp: snapshot.Timestamp,
+			Totals:    totals,
+		}
+
+		// Keep the last run of each period
+		if len(points) > 0 && points[len(points)-1].Period == point.Period {
+			points[len(points)-1] = point
+		} else {
+			points = append(points, point)
+		}
+	}
+
+	for i := 1; i < len(points); i++ {
+		change := points[i].Totals.Sub(points[i-1].Totals)
+		points[i].Change = &change
+	}
+	return points, nil
+}
+
+// HistoryText renders the history as a plain text table, with each total
+// followed by its change since the period before
+func HistoryText(points []HistoryPoint) string {
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "%-19s %16s %16s %20s\n", "Period", "Commits", "Repositories", "Other contributions")
+	for _, point := range points {
+		commits, repositories, other := metricDeltas(point.Change)
+		fmt.Fprintf(&builder, "%-19s %16s %16s %20s\n", point.Period,
+			formatMetric(point.TotalCommitContributions, commits),
+			formatMetric(point.TotalRepositories, repositories),
+			formatMetric(point.TotalOtherContributions, other))
+	}
+	return builder.String()
+}
@@ -0,0 +1,60 @@
+package reporting_test
+
+import (
+	"testing"
+	"time"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test summarizing the snapshot history by period
+func TestHistory(t *testing.T) {
+	snapshot := func(date string, commits int, userCommits map[string]int) rpt.Snapshot {
+		timestamp, err := time.Parse(time.DateTime, date)
+		require.NoError(t, err)
+		results := rpt.AggregatedResults{Timestamp: int(timestamp.Unix()), TotalCommitContributions: commits,
+			ByUser: make(map[string]rpt.Totals)}
+		for user, commits := range userCommits {
+			results.ByUser[user] = rpt.Totals{TotalCommitContributions: commits}
+		}
+		return rpt.Snapshot{Timestamp: results.Timestamp, Results: results}
+	}
+	snapshots := []rpt.Snapshot{
+		snapshot("2024-04-01 09:00:00", 800, map[string]int{"user1": 800}),
+		snapshot("2024-04-30 09:00:00", 812, map[string]int{"user1": 812}),
+		snapshot("2024-05-31 09:00:00", 851, map[string]int{"user1": 820, "user2": 31}),
+		snapshot("2024-06-01 09:00:00", 880, map[string]int{"user1": 840, "user2": 40}),
+	}
+
+	// Ensure the last run of each month is kept, with the change since the month before
+	points, err := rpt.History(snapshots, "month", "")
+	require.NoError(t, err)
+	require.Len(t, points, 3)
+	assert.Equal(t, "2024-04", points[0].Period)
+	assert.Equal(t, 812, points[0].TotalCommitContributions)
+	assert.Nil(t, points[0].Change)
+	assert.Equal(t, 39, points[1].Change.TotalCommitContributions)
+	assert.Equal(t, 29, points[2].Change.TotalCommitContributions)
+	assert.Contains(t, rpt.HistoryText(points), "2024-05                    851 (+39)")
+
+	points, err = rpt.History(snapshots, "run", "")
+	require.NoError(t, err)
+	assert.Len(t, points, 4)
+	assert.Equal(t, "2024-04-01 09:00:00", points[0].Period)
+
+	points, err = rpt.History(snapshots, "year", "")
+	require.NoError(t, err)
+	assert.Len(t, points, 1)
+
+	// Ensure a user's history starts with their first run
+	points, err = rpt.History(snapshots, "month", "user2")
+	require.NoError(t, err)
+	require.Len(t, points, 2)
+	assert.Equal(t, 31, points[0].TotalCommitContributions)
+	assert.Equal(t, 9, points[1].Change.TotalCommitContributions)
+
+	_, err = rpt.History(snapshots, "week", "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,40 @@
+package reporting
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"slices"
+)
+
+// The prefix of the environment variables passed to hook commands
+const hookEnvironmentPrefix = "GHCONTRIBUTIONS_"
+
+// The run statuses passed to hook commands
+const (
+	HookStatusSuccess = "success"
+	HookStatusPartial = "partial"
+)
+
+// RunHook runs a hook command with the shell, adding the variables to its
+// environment with the GHCONTRIBUTIONS_ prefix. The command's output goes to
+// the run's standard error, so it doesn't mix with a report written to standard
+// output, and an error is returned when the command fails.
+func RunHook(ctx context.Context, command string, variables map[string]string) error {
+
+	hook := exec.CommandContext(ctx, "sh", "-c", command)
+	hook.Stdout = os.Stderr
+	hook.Stderr = os.Stderr
+	hook.Env = os.Environ()
+	for _, name := range slices.Sorted(maps.Keys(variables)) {
+		hook.Env = append(hook.Env, hookEnvironmentPrefix+name+"="+variables[name])
+	}
+
+	err := hook.Run()
+	if err != nil {
+		return fmt.Errorf("failed to run the hook %q: %w", command, err)
+	}
+	return nil
+}
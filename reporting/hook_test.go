@@ -0,0 +1,28 @@
+package reporting_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test running a hook command with the run's variables
+func TestRunHook(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "hook.txt")
+	err := rpt.RunHook(context.Background(), `echo "$GHCONTRIBUTIONS_STATUS $GHCONTRIBUTIONS_REPORT" > `+output,
+		map[string]string{"STATUS": rpt.HookStatusPartial, "REPORT": "report.json"})
+	require.NoError(t, err)
+
+	written, err := os.ReadFile(output)
+	require.NoError(t, err)
+	assert.Equal(t, "partial report.json\n", string(written))
+
+	// Ensure a failing command is reported
+	err = rpt.RunHook(context.Background(), "exit 3", nil)
+	assert.ErrorContains(t, err, `failed to run the hook "exit 3"`)
+}
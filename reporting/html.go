@@ -0,0 +1,140 @@
+package reporting
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"maps"
+	"slices"
+	"time"
+)
+
+// The number of top repositories listed on the HTML report
+const htmlTopRepositories = 10
+
+// The height of the tallest bar in the HTML report's yearly chart, in pixels
+const htmlChartHeight = 160
+
+// The self-contained HTML report, with the chart drawn as inline SVG
+var htmlTemplate = template.Must(template.New("html").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>GitHub contributions</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; color: #1f2328; margin: 2rem auto; max-width: 56rem; padding: 0 1rem; }
+.totals { display: flex; gap: 1rem; flex-wrap: wrap; }
+.total { border: 1px solid #d0d7de; border-radius: 6px; padding: 1rem 1.5rem; flex: 1; }
+.total strong { display: block; font-size: 2rem; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border-bottom: 1px solid #d0d7de; padding: 0.4rem; text-align: right; }
+th:first-child, td:first-child { text-align: left; }
+.commits { fill: #2da44e; }
+.other { fill: #8c959f; }
+footer { color: #656d76; font-size: 0.85rem; margin-top: 2rem; }
+</style>
+</head>
+<body>
+<h1>GitHub contributions</h1>
+<div class="totals">
+<div class="total"><strong>{{.Totals.TotalCommitContributions}}</strong>Commits</div>
+<div class="total"><strong>{{.Totals.TotalRepositories}}</strong>Repositories</div>
+<div class="total"><strong>{{.Totals.TotalOtherContributions}}</strong>Other contributions</div>
+</div>
+{{if .Years}}<h2>By year</h2>
+<svg width="{{.ChartWidth}}" height="{{.ChartHeight}}" viewBox="0 0 {{.ChartWidth}} {{.ChartHeight}}" role="img" aria-label="Commits and other contributions by year">
+{{range .Years}}<g>
+<title>{{.Year}}: {{.Totals.TotalCommitContributions}} commits, {{.Totals.TotalOtherContributions}} other contributions</title>
+<rect class="commits" x="{{.X}}" y="{{.CommitsY}}" width="20" height="{{.CommitsHeight}}"></rect>
+<rect class="other" x="{{.OtherX}}" y="{{.OtherY}}" width="20" height="{{.OtherHeight}}"></rect>
+<text x="{{.OtherX}}" y="{{$.LabelY}}" font-size="12" text-anchor="middle">{{.Year}}</text>
+</g>
+{{end}}</svg>
+<p><svg width="10" height="10"><rect class="commits" width="10" height="10"></rect></svg> Commits
+<svg width="10" height="10"><rect class="other" width="10" height="10"></rect></svg> Other contributions</p>
+<table>
+<tr><th>Year</th><th>Commits</th><th>Repositories</th><th>Other contributions</th></tr>
+{{range .Years}}<tr><td>{{.Year}}</td><td>{{.Totals.TotalCommitContributions}}</td><td>{{.Totals.TotalRepositories}}</td><td>{{.Totals.TotalOtherContributions}}</td></tr>
+{{end}}</table>{{end}}
+{{if .Repositories}}<h2>Top repositories</h2>
+<table>
+<tr><th>Repository</th><th>Contributions</th></tr>
+{{range .Repositories}}<tr><td>{{if .URL}}<a href="{{.URL}}">{{.Name}}</a>{{else}}{{.Name}}{{end}}</td><td>{{.Contributions}}</td></tr>
+{{end}}</table>{{end}}
+<footer>Generated by ghcontributions on {{.Date}}</footer>
+</body>
+</html>
+`))
+
+// htmlYear is a year's bars in the HTML report's chart
+type htmlYear struct {
+	Year          string
+	Totals        Totals
+	X             int
+	OtherX        int
+	CommitsY      int
+	CommitsHeight int
+	OtherY        int
+	OtherHeight   int
+}
+
+// htmlRepository is a top repository in the HTML report
+type htmlRepository struct {
+	Name          string
+	URL           string
+	Contributions int
+}
+
+// WriteHTML writes a self-contained HTML page with the totals, a chart and a
+// table of each year, and the repositories with the most contributions
+func WriteHTML(w io.Writer, queryResults map[string]QueryResult, aggregatedResults AggregatedResults) error {
+
+	// Scale the bars to the largest value of any year
+	years := slices.Sorted(maps.Keys(aggregatedResults.ByYear))
+	largest := 1
+	for _, year := range years {
+		totals := aggregatedResults.ByYear[year]
+		largest = max(largest, totals.TotalCommitContributions, totals.TotalOtherContributions)
+	}
+	var htmlYears []htmlYear
+	for i, year := range years {
+		totals := aggregatedResults.ByYear[year]
+		commitsHeight := totals.TotalCommitContributions * htmlChartHeight / largest
+		otherHeight := totals.TotalOtherContributions * htmlChartHeight / largest
+		htmlYears = append(htmlYears, htmlYear{
+			Year:          aggregatedResults.YearLabel(year),
+			Totals:        totals,
+			X:             10 + i*60,
+			OtherX:        30 + i*60,
+			CommitsY:      10 + htmlChartHeight - commitsHeight,
+			CommitsHeight: commitsHeight,
+			OtherY:        10 + htmlChartHeight - otherHeight,
+			OtherHeight:   otherHeight,
+		})
+	}
+
+	// List the aggregated repositories, which the filters and -top already cut down
+	var repositories []htmlRepository
+	for _, repository := range aggregatedResults.Repositories[:min(htmlTopRepositories, len(aggregatedResults.Repositories))] {
+		repositories = append(repositories, htmlRepository{
+			Name:          repository.Name,
+			URL:           repository.URL,
+			Contributions: repository.Contributions,
+		})
+	}
+
+	err := htmlTemplate.Execute(w, map[string]any{
+		"Totals":       aggregatedResults.Totals(),
+		"Years":        htmlYears,
+		"ChartWidth":   20 + len(years)*60,
+		"ChartHeight":  htmlChartHeight + 40,
+		"LabelY":       htmlChartHeight + 30,
+		"Repositories": repositories,
+		"Date":         time.Unix(int64(aggregatedResults.Timestamp), 0).UTC().Format(time.DateOnly),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write the html: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package reporting_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test writing the HTML report
+func TestWriteHTML(t *testing.T) {
+	date := time.Date(2023, time.May, 1, 0, 0, 0, 0, time.UTC)
+	queryResults := rpt.QueryResultsFromContributions("user1", []rpt.Contribution{
+		{Kind: rpt.ContributionCommit, Repository: rpt.Repository{Name: "app", URL: "https://example.com/app"}, Date: date, Count: 40},
+		{Kind: rpt.ContributionIssue, Repository: rpt.Repository{Name: "<lib>", URL: "https://example.com/lib"}, Date: date, Count: 5},
+		{Kind: rpt.ContributionCommit, Repository: rpt.Repository{Name: "app", URL: "https://example.com/app"}, Date: date.AddDate(-1, 0, 0), Count: 20},
+	})
+	reporter := &rpt.Reporter{}
+	results, err := reporter.Aggregate(context.Background(), queryResults)
+	require.NoError(t, err)
+
+	var html bytes.Buffer
+	require.NoError(t, rpt.WriteHTML(&html, queryResults, results))
+	page := html.String()
+	assert.Contains(t, page, "<strong>60</strong>Commits")
+	assert.Contains(t, page, "<title>2023: 40 commits, 5 other contributions</title>")
+
+	// Ensure the bars are scaled to the largest year
+	assert.Contains(t, page, `<rect class="commits" x="70" y="10" width="20" height="160">`)
+	assert.Contains(t, page, `<rect class="commits" x="10" y="90" width="20" height="80">`)
+
+	// Ensure the repositories are listed by contributions and escaped
+	assert.Contains(t, page, `<tr><td><a href="https://example.com/app">app</a></td><td>60</td></tr>`)
+	assert.Contains(t, page, `&lt;lib&gt;`)
+	assert.NotContains(t, page, `<lib>`)
+	assert.Less(t, bytes.Index(html.Bytes(), []byte(">app<")), bytes.Index(html.Bytes(), []byte("&lt;lib&gt;")))
+
+	// Ensure the repositories the filter leaves out aren't listed
+	results, err = (&rpt.Reporter{Filter: rpt.RepositoryFilter{Exclude: []string{"lib"}}}).
+		Aggregate(context.Background(), queryResults)
+	require.NoError(t, err)
+	html.Reset()
+	require.NoError(t, rpt.WriteHTML(&html, queryResults, results))
+	assert.Contains(t, html.String(), `<tr><td><a href="https://example.com/app">app</a></td><td>60</td></tr>`)
+	assert.NotContains(t, html.String(), `&lt;lib&gt;`)
+
+	// Ensure empty results still render
+	html.Reset()
+	require.NoError(t, rpt.WriteHTML(&html, nil, rpt.AggregatedResults{}))
+	assert.NotContains(t, html.String(), "<svg width=\"20\"")
+	assert.NotContains(t, html.String(), "Top repositories")
+}
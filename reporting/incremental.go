@@ -0,0 +1,47 @@
+package reporting
+
+import (
+	"slices"
+	"strconv"
+	"time"
+)
+
+// Reuse finds the user-years of the report that are final, whole years in the
+// user's collected range that had already passed when the report was written,
+// and that weren't left without results by an error. The final years are read
+// oldest first, up to the first that isn't final, and returned with the rest of
+// the range, which still needs to be collected. Final years without any
+// contributions have no query results. Nothing is reused unless the user was
+// collected from the same sources, like github@ghe.example.com, in any order.
+func (r Report) Reuse(user string, sources []string, dateRange DateRange) (map[string]QueryResult, DateRange) {
+
+	var queryResults = make(map[string]QueryResult)
+	distinct := func(sources []string) []string { return slices.Compact(slices.Sorted(slices.Values(sources))) }
+	collected, found := r.Collected[user]
+	if !found || !slices.Equal(distinct(r.Sources[user]), distinct(sources)) {
+		return queryResults, dateRange
+	}
+	var failed []string
+	for _, collectionError := range r.Errors {
+		if collectionError.User == user {
+			failed = append(failed, collectionError.UserYears...)
+		}
+	}
+	reportYear := time.Unix(int64(r.Timestamp), 0).UTC().Year()
+	final := func(year int) bool {
+		yearRange := YearRange(year, year)
+		return year < reportYear && !yearRange.From.Before(collected.From) && !yearRange.To.After(collected.To) &&
+			!yearRange.From.Before(dateRange.From) && !yearRange.To.After(dateRange.To) &&
+			!slices.Contains(failed, user+"-"+strconv.Itoa(year))
+	}
+
+	remaining := dateRange
+	for year := dateRange.From.Year(); final(year); year++ {
+		userYear := user + "-" + strconv.Itoa(year)
+		if queryResult, found := r.QueryResults[userYear]; found {
+			queryResults[userYear] = queryResult
+		}
+		remaining.From = YearRange(year+1, year+1).From
+	}
+	return queryResults, remaining
+}
@@ -0,0 +1,57 @@
+package reporting_test
+
+import (
+	"testing"
+	"time"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test reusing the final years of a previous report
+func TestReportReuse(t *testing.T) {
+	report := rpt.Report{
+		AggregatedResults: rpt.AggregatedResults{
+			Timestamp: int(time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC).Unix()),
+			Errors:    []rpt.CollectionError{{User: "user2", UserYears: []string{"user2-2022"}, Error: "timeout"}},
+		},
+		QueryResults: commitsByYear("user1", map[int]int{2021: 5, 2023: 7, 2024: 2}),
+		Collected: map[string]rpt.DateRange{
+			"user1": rpt.YearRange(2020, 2024),
+			"user2": rpt.YearRange(2020, 2024),
+		},
+		Sources: map[string][]string{
+			"user1": {"github"},
+			"user2": {"github"},
+		},
+	}
+	github := []string{"github"}
+
+	// Ensure the past years are reused, even without contributions, and the current year is collected
+	queryResults, remaining := report.Reuse("user1", github, rpt.YearRange(2020, 2025))
+	assert.Len(t, queryResults, 2)
+	assert.Contains(t, queryResults, "user1-2021")
+	assert.Contains(t, queryResults, "user1-2023")
+	assert.Equal(t, rpt.YearRange(2024, 2025), remaining)
+
+	// Ensure the years before the collected range are collected again
+	queryResults, remaining = report.Reuse("user1", github, rpt.YearRange(2018, 2025))
+	assert.Empty(t, queryResults)
+	assert.Equal(t, rpt.YearRange(2018, 2025), remaining)
+
+	// Ensure the collection restarts from a failed year
+	_, remaining = report.Reuse("user2", github, rpt.YearRange(2020, 2024))
+	assert.Equal(t, rpt.YearRange(2022, 2024), remaining)
+
+	// Ensure users that weren't collected are collected in full
+	queryResults, remaining = report.Reuse("user3", github, rpt.YearRange(2020, 2024))
+	assert.Empty(t, queryResults)
+	assert.Equal(t, rpt.YearRange(2020, 2024), remaining)
+
+	// Ensure the same login on another instance is collected in full
+	queryResults, remaining = report.Reuse("user1", []string{"github@ghe.example.com"}, rpt.YearRange(2020, 2025))
+	assert.Empty(t, queryResults)
+	assert.Equal(t, rpt.YearRange(2020, 2025), remaining)
+	queryResults, _ = report.Reuse("user1", []string{"github", "github@ghe.example.com"}, rpt.YearRange(2020, 2025))
+	assert.Empty(t, queryResults)
+}
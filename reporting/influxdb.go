@@ -0,0 +1,131 @@
+package reporting
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// An InfluxDB exporter writes per user-year metrics as InfluxDB line protocol,
+// either to a file or to an HTTP write endpoint
+type InfluxDB struct {
+	// The path of a file to write the line protocol to
+	FilePath string
+	// The full URL of an HTTP write endpoint, like
+	// http://localhost:8086/api/v2/write?org=my-org&bucket=github
+	WriteURL string
+	// The API token sent with HTTP writes, if any
+	Token string
+	// The HTTP client used to send writes
+	Client *http.Client
+}
+
+// Constructs a new InfluxDB object
+// The filePath is the path of a file to write the line protocol to
+// The writeURL is the URL of an HTTP write endpoint
+// The token is the API token used for HTTP writes
+func NewInfluxDB(filePath string, writeURL string, token string) (influxDB *InfluxDB, err error) {
+
+	if filePath == "" && writeURL == "" {
+		err = fmt.Errorf("either an influxdb file path or write URL is required")
+		return nil, err
+	}
+
+	return &InfluxDB{
+		FilePath: filePath,
+		WriteURL: writeURL,
+		Token:    token,
+		Client:   http.DefaultClient,
+	}, err
+}
+
+// Export writes one point per user-year, timestamped at the start of the year,
+// followed by a point for the aggregated totals at the time of the run
+func (i *InfluxDB) Export(queryResults map[string]QueryResult, aggregatedResults AggregatedResults) error {
+
+	var lines bytes.Buffer
+	err := WriteLineProtocol(&lines, queryResults, aggregatedResults)
+	if err != nil {
+		return err
+	}
+
+	if i.FilePath != "" {
+		err = os.WriteFile(i.FilePath, lines.Bytes(), 0644)
+		if err != nil {
+			return fmt.Errorf("failed to write the influxdb file: %w", err)
+		}
+	}
+
+	if i.WriteURL != "" {
+		err = i.write(lines.Bytes())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// write sends the line protocol to the HTTP write endpoint
+func (i *InfluxDB) write(lines []byte) error {
+
+	request, err := http.NewRequest(http.MethodPost, i.WriteURL, bytes.NewReader(lines))
+	if err != nil {
+		return fmt.Errorf("failed to build the influxdb request: %w", err)
+	}
+	request.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if i.Token != "" {
+		request.Header.Set("Authorization", "Token "+i.Token)
+	}
+
+	response, err := i.Client.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to write to influxdb: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode/100 != 2 {
+		return fmt.Errorf("failed to write to influxdb: influxdb returned %s", response.Status)
+	}
+	return nil
+}
+
+// WriteLineProtocol writes the per user-year results and the aggregated totals
+// as InfluxDB line protocol with nanosecond timestamps
+func WriteLineProtocol(w io.Writer, queryResults map[string]QueryResult, aggregatedResults AggregatedResults) error {
+
+	escaper := strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `)
+
+	for _, userYear := range slices.Sorted(maps.Keys(queryResults)) {
+		user, year := splitUserYear(userYear)
+		collection := queryResults[userYear].User.ContributionsCollection
+		timestamp := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+		_, err := fmt.Fprintf(w,
+			"%s,user=%s,year=%d commits=%di,issues=%di,pull_requests=%di,pull_request_reviews=%di,repositories=%di %d\n",
+			MetricsNamespace, escaper.Replace(user), year,
+			collection.TotalCommitContributions,
+			collection.TotalIssueContributions,
+			collection.TotalPullRequestContributions,
+			collection.TotalPullRequestReviewContributions,
+			collection.TotalRepositories(),
+			timestamp.UnixNano())
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "%s_totals commits=%di,repositories=%di,other=%di %s\n",
+		MetricsNamespace,
+		aggregatedResults.TotalCommitContributions,
+		aggregatedResults.TotalRepositories,
+		aggregatedResults.TotalOtherContributions,
+		strconv.FormatInt(time.Unix(int64(aggregatedResults.Timestamp), 0).UnixNano(), 10))
+	return err
+}
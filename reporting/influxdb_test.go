@@ -0,0 +1,65 @@
+package reporting_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the NewInfluxDB constructor
+func TestNewInfluxDB(t *testing.T) {
+	_, err := rpt.NewInfluxDB("", "", "")
+	assert.Error(t, err)
+}
+
+// Test the WriteLineProtocol function
+func TestWriteLineProtocol(t *testing.T) {
+	queryResults, err := loadQueryResultsMap("single_user_single_year.json")
+	assert.NoError(t, err)
+
+	results := rpt.AggregatedResults{Timestamp: 1730498450, TotalCommitContributions: 10}
+	var lines strings.Builder
+	err = rpt.WriteLineProtocol(&lines, queryResults, results)
+	assert.NoError(t, err)
+
+	assert.Equal(t,
+		"ghcontributions,user=user1,year=2023 commits=10i,issues=5i,pull_requests=3i,"+
+			"pull_request_reviews=2i,repositories=1i 1672531200000000000\n"+
+			"ghcontributions_totals commits=10i,repositories=0i,other=0i 1730498450000000000\n",
+		lines.String())
+}
+
+// Test the InfluxDB Export method
+func TestInfluxDBExport(t *testing.T) {
+	var authorization, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	filePath := filepath.Join(t.TempDir(), "metrics.lp")
+	influxDB, err := rpt.NewInfluxDB(filePath, server.URL+"/api/v2/write?bucket=github", "secret")
+	assert.NoError(t, err)
+
+	queryResults, err := loadQueryResultsMap("single_user_single_year.json")
+	assert.NoError(t, err)
+	err = influxDB.Export(queryResults, rpt.AggregatedResults{})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "Token secret", authorization)
+	assert.Contains(t, body, "ghcontributions,user=user1,year=2023")
+
+	written, err := os.ReadFile(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, body, string(written))
+}
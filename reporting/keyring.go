@@ -0,0 +1,147 @@
+package reporting
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/zalando/go-keyring"
+)
+
+// The keyring service credentials are kept under by default
+const DefaultKeyringService = "ghcontributions"
+
+// The keyring entry listing the accounts of the credentials, as keychains can't be listed
+const keyringIndex = "index"
+
+// A Keyring keeps credentials in the OS keychain, the macOS Keychain, the Windows
+// Credential Manager, or the Secret Service on Linux, so tokens never live on disk.
+// Each credential is an entry of the service, named like github/username.
+type Keyring struct {
+	Service string
+}
+
+// Constructs a new Keyring object
+// The service names the credentials' entries, defaulting to DefaultKeyringService
+func NewKeyring(service string) *Keyring {
+
+	if service == "" {
+		service = DefaultKeyringService
+	}
+	return &Keyring{Service: service}
+}
+
+// keyringAccount names the keyring entry of a credential by its provider, username,
+// and URL if any, so the same username on another provider or server is kept apart
+func keyringAccount(credential Credential) string {
+	provider := credential.Provider
+	if provider == "" {
+		provider = ProviderGitHub
+	}
+	account := provider + "/" + credential.Username
+	if credential.URL != "" {
+		account += " " + credential.URL
+	}
+	return account
+}
+
+// Credentials reads every credential from the keyring, in the order they were added
+func (k *Keyring) Credentials() (Credentials, error) {
+
+	accounts, err := k.accounts()
+	if err != nil {
+		return nil, err
+	}
+	var credentials = make(Credentials, 0, len(accounts))
+	for _, account := range accounts {
+		entry, err := keyring.Get(k.Service, account)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s from the keyring: %w", account, err)
+		}
+		var credential Credential
+		err = json.Unmarshal([]byte(entry), &credential)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s from the keyring: %w", account, err)
+		}
+		credentials = append(credentials, credential)
+	}
+	return credentials, nil
+}
+
+// Add writes the credential to the keyring, replacing any with the same provider,
+// username, and URL
+func (k *Keyring) Add(credential Credential) error {
+
+	if credential.Username == "" {
+		return fmt.Errorf("the username of a keyring credential cannot be blank")
+	}
+	entry, err := json.Marshal(credential)
+	if err != nil {
+		return fmt.Errorf("failed to encode the credential of %s: %w", credential.Username, err)
+	}
+	account := keyringAccount(credential)
+	err = keyring.Set(k.Service, account, string(entry))
+	if err != nil {
+		return fmt.Errorf("failed to write %s to the keyring: %w", account, err)
+	}
+
+	accounts, err := k.accounts()
+	if err != nil {
+		return err
+	}
+	if slices.Contains(accounts, account) {
+		return nil
+	}
+	return k.setAccounts(append(accounts, account))
+}
+
+// Remove deletes the credential with the same provider, username, and URL from the keyring
+func (k *Keyring) Remove(credential Credential) error {
+
+	account := keyringAccount(credential)
+	accounts, err := k.accounts()
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(accounts, account) {
+		return fmt.Errorf("the keyring has no credential for %s", account)
+	}
+	err = keyring.Delete(k.Service, account)
+	if err != nil && !errors.Is(err, keyring.ErrNotFound) {
+		return fmt.Errorf("failed to delete %s from the keyring: %w", account, err)
+	}
+	return k.setAccounts(slices.DeleteFunc(accounts, func(other string) bool { return other == account }))
+}
+
+// accounts reads the index of credential entries, empty if there is none yet
+func (k *Keyring) accounts() ([]string, error) {
+
+	index, err := keyring.Get(k.Service, keyringIndex)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the keyring index: %w", err)
+	}
+	var accounts []string
+	err = json.Unmarshal([]byte(index), &accounts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the keyring index: %w", err)
+	}
+	return accounts, nil
+}
+
+// setAccounts writes the index of credential entries
+func (k *Keyring) setAccounts(accounts []string) error {
+
+	index, err := json.Marshal(accounts)
+	if err != nil {
+		return fmt.Errorf("failed to encode the keyring index: %w", err)
+	}
+	err = keyring.Set(k.Service, keyringIndex, string(index))
+	if err != nil {
+		return fmt.Errorf("failed to write the keyring index: %w", err)
+	}
+	return nil
+}
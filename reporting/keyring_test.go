@@ -0,0 +1,43 @@
+package reporting_test
+
+import (
+	"testing"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zalando/go-keyring"
+)
+
+// Test adding, reading, and removing credentials in the keyring
+func TestKeyring(t *testing.T) {
+	keyring.MockInit()
+	k := rpt.NewKeyring("")
+	assert.Equal(t, rpt.DefaultKeyringService, k.Service)
+
+	credentials, err := k.Credentials()
+	require.NoError(t, err)
+	assert.Empty(t, credentials)
+
+	alice := rpt.Credential{Username: "alice", Token: "token1"}
+	enterprise := rpt.Credential{Username: "alice", Token: "token2", URL: "https://ghe.example.com"}
+	gitLab := rpt.Credential{Username: "bob", Token: "token3", Provider: rpt.ProviderGitLab}
+	for _, credential := range []rpt.Credential{alice, enterprise, gitLab} {
+		require.NoError(t, k.Add(credential))
+	}
+
+	// Ensure adding a credential again replaces its token in place
+	alice.Token = "token4"
+	require.NoError(t, k.Add(alice))
+	credentials, err = k.Credentials()
+	require.NoError(t, err)
+	assert.Equal(t, rpt.Credentials{alice, enterprise, gitLab}, credentials)
+
+	require.NoError(t, k.Remove(rpt.Credential{Username: "alice", URL: "https://ghe.example.com"}))
+	credentials, err = k.Credentials()
+	require.NoError(t, err)
+	assert.Equal(t, rpt.Credentials{alice, gitLab}, credentials)
+
+	assert.Error(t, k.Remove(rpt.Credential{Username: "carol"}))
+	assert.Error(t, k.Add(rpt.Credential{Token: "token5"}))
+}
@@ -0,0 +1,47 @@
+package reporting
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+)
+
+// LanguageTotals holds the contributions to the repositories written mostly in
+// a language, by their primary language
+type LanguageTotals struct {
+	Language     string `json:"language"`
+	Repositories int    `json:"repositories"`
+	Commits      int    `json:"commits"`
+	// The contributions of every kind
+	Contributions int `json:"contributions"`
+}
+
+// Languages adds up the contributions to the repositories by their primary
+// language, with the most contributions first. The repositories without a
+// language, like those whose details weren't queried, are left out, so there
+// are none without any details.
+func Languages(repositories []RepositoryTotals) []LanguageTotals {
+
+	var byLanguage = make(map[string]*LanguageTotals)
+	for _, repository := range repositories {
+		if repository.Language == "" {
+			continue
+		}
+		if byLanguage[repository.Language] == nil {
+			byLanguage[repository.Language] = &LanguageTotals{Language: repository.Language}
+		}
+		totals := byLanguage[repository.Language]
+		totals.Repositories++
+		totals.Commits += repository.Commits
+		totals.Contributions += repository.Contributions
+	}
+
+	var languages []LanguageTotals
+	for _, language := range slices.Sorted(maps.Keys(byLanguage)) {
+		languages = append(languages, *byLanguage[language])
+	}
+	slices.SortStableFunc(languages, func(a, b LanguageTotals) int {
+		return cmp.Compare(b.Contributions, a.Contributions)
+	})
+	return languages
+}
@@ -0,0 +1,39 @@
+package reporting_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test adding up the contributions to the repositories by their primary language
+func TestLanguages(t *testing.T) {
+	assert.Empty(t, rpt.Languages([]rpt.RepositoryTotals{{Repository: rpt.Repository{Name: "app"}, Commits: 3}}))
+
+	date := time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)
+	queryResults := rpt.QueryResultsFromContributions("user1", []rpt.Contribution{
+		{Kind: rpt.ContributionCommit, Repository: rpt.Repository{Name: "api", URL: "u1", Language: "Go"}, Date: date, Count: 10},
+		{Kind: rpt.ContributionCommit, Repository: rpt.Repository{Name: "cli", URL: "u2", Language: "Go"}, Date: date, Count: 5},
+		{Kind: rpt.ContributionIssue, Repository: rpt.Repository{Name: "cli", URL: "u2", Language: "Go"}, Date: date, Count: 2},
+		{Kind: rpt.ContributionCommit, Repository: rpt.Repository{Name: "web", URL: "u3", Language: "TypeScript"}, Date: date, Count: 20},
+		{Kind: rpt.ContributionCommit, Repository: rpt.Repository{Name: "docs", URL: "u4"}, Date: date, Count: 1},
+	})
+
+	// Ensure the languages with the most contributions come first, leaving out the repositories without one
+	result, err := (&rpt.Reporter{}).Aggregate(context.Background(), queryResults)
+	require.NoError(t, err)
+	assert.Equal(t, []rpt.LanguageTotals{
+		{Language: "TypeScript", Repositories: 1, Commits: 20, Contributions: 20},
+		{Language: "Go", Repositories: 2, Commits: 15, Contributions: 17},
+	}, result.Languages)
+
+	var markdown bytes.Buffer
+	require.NoError(t, result.WriteMarkdown(&markdown))
+	assert.Contains(t, markdown.String(), "### By language\n\n| Language | Commits | Repositories | Contributions |")
+	assert.Contains(t, markdown.String(), "| Go | 15 | 2 | 17 |")
+}
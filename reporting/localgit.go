@@ -0,0 +1,158 @@
+package reporting
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// A LocalGit provider counts the commits authored with a user's email addresses
+// in the local clones found under a list of directories, for mirrors and private
+// servers that no provider API reaches
+type LocalGit struct {
+	// The email addresses the user's commits are authored with
+	Emails []string
+	// The directories searched for clones
+	Directories []string
+	// The git command
+	GitPath string
+}
+
+// Constructs a new LocalGit object
+// The emails are the addresses the user's commits are authored with
+// The directories are searched recursively for clones
+func NewLocalGit(emails []string, directories []string) (localGit *LocalGit, err error) {
+
+	if len(emails) == 0 {
+		err = fmt.Errorf("the local git emails cannot be empty")
+		return nil, err
+	}
+	if len(directories) == 0 {
+		err = fmt.Errorf("the local git directories cannot be empty")
+		return nil, err
+	}
+
+	return &LocalGit{
+		Emails:      emails,
+		Directories: directories,
+		GitPath:     "git",
+	}, err
+}
+
+// Collect counts the user's commits on every branch of each clone. A commit
+// found in more than one clone, like a mirror, is counted once.
+func (l *LocalGit) Collect(ctx context.Context, user string, dateRange DateRange) ([]Contribution, error) {
+
+	var contributions []Contribution
+
+	slog.Debug("Scanning local git repositories", "user", user)
+
+	repositories, err := l.repositories()
+	if err != nil {
+		return contributions, err
+	}
+
+	var emails = make(map[string]bool)
+	for _, email := range l.Emails {
+		emails[strings.ToLower(email)] = true
+	}
+
+	var seen = make(map[string]bool)
+	var urls = make(map[string]string)
+	for _, path := range repositories {
+		// Mirrors and work trees of the same repository share a name, so they're
+		// counted as one repository with the URL of the first
+		repository := Repository{
+			Name: strings.TrimSuffix(filepath.Base(path), ".git"),
+			URL:  l.remoteURL(ctx, path),
+		}
+		if url, found := urls[repository.Name]; found {
+			repository.URL = url
+		}
+		urls[repository.Name] = repository.URL
+
+		// List every commit as its hash, author email, and author date
+		output, err := exec.CommandContext(ctx, l.GitPath, "-C", path, "log", "--all", "--no-merges",
+			"--since="+dateRange.From.Format(time.RFC3339),
+			"--until="+dateRange.To.Add(-time.Second).Format(time.RFC3339),
+			"--format=%H%x09%ae%x09%aI").Output()
+		if err != nil {
+			return contributions, fmt.Errorf("failed to read the git log of %s: %w", path, err)
+		}
+
+		scanner := bufio.NewScanner(bytes.NewReader(output))
+		for scanner.Scan() {
+			fields := strings.Split(scanner.Text(), "\t")
+			if len(fields) != 3 || seen[fields[0]] || !emails[strings.ToLower(fields[1])] {
+				continue
+			}
+			seen[fields[0]] = true
+			date, err := time.Parse(time.RFC3339, fields[2])
+			if err != nil {
+				return contributions, fmt.Errorf("failed to parse the commit date %q: %w", fields[2], err)
+			}
+			if dateRange.Contains(date) {
+				contributions = append(contributions, Contribution{
+					Kind: ContributionCommit, Repository: repository, Date: date, Count: 1,
+				})
+			}
+		}
+	}
+	return contributions, nil
+}
+
+// repositories walks the directories in order and returns each clone found,
+// without descending into the clones themselves
+func (l *LocalGit) repositories() ([]string, error) {
+
+	var repositories []string
+	for _, directory := range l.Directories {
+		err := filepath.WalkDir(directory, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !entry.IsDir() {
+				return nil
+			}
+			// Work trees hold a .git directory or file, and bare clones end in .git
+			_, statErr := os.Stat(filepath.Join(path, ".git"))
+			if statErr == nil || (strings.HasSuffix(path, ".git") && isBareRepository(path)) {
+				repositories = append(repositories, path)
+				return filepath.SkipDir
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to search %s for git repositories: %w", directory, err)
+		}
+	}
+	return repositories, nil
+}
+
+// remoteURL returns the clone's origin URL, or its path if it has no origin
+func (l *LocalGit) remoteURL(ctx context.Context, repository string) string {
+	output, err := exec.CommandContext(ctx, l.GitPath, "-C", repository, "config", "--get", "remote.origin.url").Output()
+	if err != nil || len(bytes.TrimSpace(output)) == 0 {
+		return repository
+	}
+	return string(bytes.TrimSpace(output))
+}
+
+// isBareRepository reports whether the directory looks like a bare clone
+func isBareRepository(path string) bool {
+	for _, name := range []string{"HEAD", "objects", "refs"} {
+		_, err := os.Stat(filepath.Join(path, name))
+		if err != nil {
+			return false
+		}
+	}
+	return true
+}
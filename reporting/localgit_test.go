@@ -0,0 +1,81 @@
+package reporting_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test the NewLocalGit constructor
+func TestNewLocalGit(t *testing.T) {
+	_, err := rpt.NewLocalGit(nil, []string{"."})
+	assert.Error(t, err)
+
+	_, err = rpt.NewLocalGit([]string{"user1@example.com"}, nil)
+	assert.Error(t, err)
+}
+
+// Test counting commits in local clones
+func TestLocalGitCollect(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	root := t.TempDir()
+	git := func(directory string, env []string, args ...string) {
+		command := exec.Command("git", append([]string{"-C", directory}, args...)...)
+		command.Env = append(os.Environ(), env...)
+		output, err := command.CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+	commit := func(directory string, email string, date string) {
+		git(directory, []string{
+			"GIT_AUTHOR_NAME=Author", "GIT_AUTHOR_EMAIL=" + email, "GIT_AUTHOR_DATE=" + date,
+			"GIT_COMMITTER_NAME=Author", "GIT_COMMITTER_EMAIL=" + email, "GIT_COMMITTER_DATE=" + date,
+		}, "commit", "--allow-empty", "-m", "change")
+	}
+
+	// A work tree nested under a directory, with commits by several authors
+	app := filepath.Join(root, "work", "app")
+	require.NoError(t, os.MkdirAll(app, 0755))
+	git(app, nil, "init", "--quiet")
+	git(app, nil, "remote", "add", "origin", "https://git.example.com/app.git")
+	commit(app, "user1@example.com", "2022-06-01T12:00:00Z")
+	commit(app, "User1@Example.com", "2023-06-01T12:00:00Z")
+	commit(app, "user1@work.example.com", "2024-06-01T12:00:00Z")
+	commit(app, "user2@example.com", "2024-06-02T12:00:00Z")
+
+	// A bare mirror holding the same commits, and one more
+	mirror := filepath.Join(root, "mirrors", "app.git")
+	git(root, nil, "clone", "--quiet", "--mirror", app, mirror)
+	scratch := filepath.Join(root, "scratch")
+	git(root, nil, "clone", "--quiet", mirror, scratch)
+	commit(scratch, "user1@example.com", "2024-07-01T12:00:00Z")
+	git(scratch, nil, "push", "--quiet", "origin", "HEAD")
+	require.NoError(t, os.RemoveAll(scratch))
+
+	localGit, err := rpt.NewLocalGit([]string{"user1@example.com", "user1@work.example.com"},
+		[]string{filepath.Join(root, "work"), filepath.Join(root, "mirrors")})
+	assert.NoError(t, err)
+	collector, err := rpt.NewProviderCollector(localGit, "user1", 2023, 2024)
+	assert.NoError(t, err)
+	queryResults, err := collector.Collect(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, queryResults, 2)
+
+	collection := queryResults["user1-2023"].User.ContributionsCollection
+	assert.Equal(t, 1, int(collection.TotalCommitContributions))
+	assert.Equal(t, "https://git.example.com/app.git",
+		string(collection.CommitContributionsByRepository[0].Repository.URL))
+
+	// Ensure the mirrored commit is counted once, and the new one is counted
+	collection = queryResults["user1-2024"].User.ContributionsCollection
+	assert.Equal(t, 2, int(collection.TotalCommitContributions))
+	assert.Equal(t, 1, collection.TotalRepositories())
+}
@@ -0,0 +1,83 @@
+package reporting
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// WriteMarkdown writes the totals as Markdown tables, for a profile README or
+// a wiki page, with a table for each user and for each year when there are any,
+// and for each quarter or month and each language when the report has them
+func (a AggregatedResults) WriteMarkdown(w io.Writer) error {
+
+	var markdown strings.Builder
+	markdown.WriteString("## Contributions\n\n")
+	markdown.WriteString("| Commits | Repositories | Other contributions |\n")
+	markdown.WriteString("| ---: | ---: | ---: |\n")
+	fmt.Fprintf(&markdown, "| %d | %d | %d |\n",
+		a.TotalCommitContributions, a.TotalRepositories, a.TotalOtherContributions)
+
+	// A table for each user or year, with a row for each
+	table := func(title string, column string, names []string, totals []Totals) {
+		if len(names) == 0 {
+			return
+		}
+		fmt.Fprintf(&markdown, "\n### %s\n\n", title)
+		fmt.Fprintf(&markdown, "| %s | Commits | Repositories | Other contributions |\n", column)
+		markdown.WriteString("| --- | ---: | ---: | ---: |\n")
+		for i, name := range names {
+			fmt.Fprintf(&markdown, "| %s | %d | %d | %d |\n", escapeMarkdownCell(name),
+				totals[i].TotalCommitContributions, totals[i].TotalRepositories, totals[i].TotalOtherContributions)
+		}
+	}
+
+	var years []string
+	var yearTotals []Totals
+	for _, year := range slices.Sorted(maps.Keys(a.ByYear)) {
+		years = append(years, a.YearLabel(year))
+		yearTotals = append(yearTotals, a.ByYear[year])
+	}
+	table("By year", "Year", years, yearTotals)
+
+	var periods []string
+	var periodTotals []Totals
+	for _, period := range slices.Sorted(maps.Keys(a.ByPeriod)) {
+		periods = append(periods, period)
+		periodTotals = append(periodTotals, a.ByPeriod[period])
+	}
+	if a.Granularity != "" {
+		table("By "+a.Granularity, strings.ToUpper(a.Granularity[:1])+a.Granularity[1:], periods, periodTotals)
+	}
+
+	var users []string
+	var userTotals []Totals
+	for _, user := range slices.Sorted(maps.Keys(a.ByUser)) {
+		users = append(users, user)
+		userTotals = append(userTotals, a.ByUser[user])
+	}
+	table("By user", "User", users, userTotals)
+
+	if len(a.Languages) > 0 {
+		markdown.WriteString("\n### By language\n\n")
+		markdown.WriteString("| Language | Commits | Repositories | Contributions |\n")
+		markdown.WriteString("| --- | ---: | ---: | ---: |\n")
+		for _, language := range a.Languages {
+			fmt.Fprintf(&markdown, "| %s | %d | %d | %d |\n", escapeMarkdownCell(language.Language),
+				language.Commits, language.Repositories, language.Contributions)
+		}
+	}
+
+	_, err := io.WriteString(w, markdown.String())
+	if err != nil {
+		return fmt.Errorf("failed to write the markdown: %w", err)
+	}
+	return nil
+}
+
+// escapeMarkdownCell escapes the pipes that would end a table cell
+func escapeMarkdownCell(text string) string {
+	return strings.ReplaceAll(text, "|", `\|`)
+}
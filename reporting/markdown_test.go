@@ -0,0 +1,48 @@
+package reporting_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test writing the totals as Markdown tables
+func TestWriteMarkdown(t *testing.T) {
+	queryResults := commitsByYear("user1", map[int]int{2022: 10, 2023: 20})
+	rpt.MergeQueryResults(queryResults, commitsByYear("user|2", map[int]int{2023: 5}))
+	reporter := &rpt.Reporter{}
+	results, err := reporter.Aggregate(context.Background(), queryResults)
+	require.NoError(t, err)
+
+	var markdown bytes.Buffer
+	require.NoError(t, results.WriteMarkdown(&markdown))
+	assert.Equal(t, `## Contributions
+
+| Commits | Repositories | Other contributions |
+| ---: | ---: | ---: |
+| 35 | 1 | 0 |
+
+### By year
+
+| Year | Commits | Repositories | Other contributions |
+| --- | ---: | ---: | ---: |
+| 2022 | 10 | 1 | 0 |
+| 2023 | 25 | 1 | 0 |
+
+### By user
+
+| User | Commits | Repositories | Other contributions |
+| --- | ---: | ---: | ---: |
+| user1 | 30 | 1 | 0 |
+| user\|2 | 5 | 1 | 0 |
+`, markdown.String())
+
+	// Ensure results without users or years have only the totals
+	markdown.Reset()
+	require.NoError(t, rpt.AggregatedResults{TotalCommitContributions: 3}.WriteMarkdown(&markdown))
+	assert.NotContains(t, markdown.String(), "###")
+}
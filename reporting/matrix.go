@@ -0,0 +1,151 @@
+package reporting
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A Matrix publisher posts a summary of the results to a Matrix room
+type Matrix struct {
+	// The homeserver base URL, like https://matrix.example.org
+	Homeserver string
+	// The room ID (!id:server) or alias (#alias:server) to post to
+	Room string
+	// The access token of the posting account
+	AccessToken string
+	// The results of the previous snapshot, used to report deltas
+	Previous *AggregatedResults
+	// The HTTP client used to call the homeserver
+	Client *http.Client
+}
+
+// Constructs a new Matrix object
+// The homeserver is the base URL of the Matrix homeserver
+// The room is the room ID or alias to post to
+// The accessToken authenticates the posting account
+func NewMatrix(homeserver string, room string, accessToken string) (matrix *Matrix, err error) {
+
+	if homeserver == "" || room == "" {
+		err = fmt.Errorf("the matrix homeserver and room cannot be blank")
+		return nil, err
+	}
+
+	if accessToken == "" {
+		err = fmt.Errorf("the matrix access token cannot be blank")
+		return nil, err
+	}
+
+	return &Matrix{
+		Homeserver:  strings.TrimSuffix(homeserver, "/"),
+		Room:        room,
+		AccessToken: accessToken,
+		Client:      http.DefaultClient,
+	}, err
+}
+
+// Export posts the summary as a formatted notice to the room
+func (m *Matrix) Export(queryResults map[string]QueryResult, aggregatedResults AggregatedResults) error {
+
+	roomID, err := m.roomID()
+	if err != nil {
+		return err
+	}
+
+	summary := Summarize(aggregatedResults, m.Previous)
+	content := map[string]any{
+		"msgtype":        "m.notice",
+		"body":           summary.Text(),
+		"format":         "org.matrix.custom.html",
+		"formatted_body": matrixHTML(summary),
+	}
+
+	// The transaction ID makes retries of the same message idempotent
+	transactionID := "ghcontributions-" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	endpoint := m.Homeserver + "/_matrix/client/v3/rooms/" + url.PathEscape(roomID) +
+		"/send/m.room.message/" + transactionID
+	return m.do(http.MethodPut, endpoint, content, nil)
+}
+
+// roomID resolves the room alias to a room ID, if needed
+func (m *Matrix) roomID() (string, error) {
+
+	if !strings.HasPrefix(m.Room, "#") {
+		return m.Room, nil
+	}
+
+	var directory struct {
+		RoomID string `json:"room_id"`
+	}
+	endpoint := m.Homeserver + "/_matrix/client/v3/directory/room/" + url.PathEscape(m.Room)
+	err := m.do(http.MethodGet, endpoint, nil, &directory)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve the matrix room alias: %w", err)
+	}
+	return directory.RoomID, nil
+}
+
+// do sends an authenticated request to the homeserver, decoding the response into result
+func (m *Matrix) do(method string, endpoint string, body any, result any) error {
+
+	var requestBody bytes.Buffer
+	if body != nil {
+		err := json.NewEncoder(&requestBody).Encode(body)
+		if err != nil {
+			return err
+		}
+	}
+
+	request, err := http.NewRequest(method, endpoint, &requestBody)
+	if err != nil {
+		return fmt.Errorf("failed to build the matrix request: %w", err)
+	}
+	request.Header.Set("Authorization", "Bearer "+m.AccessToken)
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := m.Client.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to call matrix: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode/100 != 2 {
+		return fmt.Errorf("failed to call matrix: homeserver returned %s", response.Status)
+	}
+	if result != nil {
+		return json.NewDecoder(response.Body).Decode(result)
+	}
+	return nil
+}
+
+// matrixHTML renders the summary as the HTML subset supported by Matrix clients
+func matrixHTML(summary Summary) string {
+
+	commits, repositories, other := metricDeltas(summary.Delta)
+	var body strings.Builder
+	fmt.Fprintf(&body, "<h4>GitHub contributions summary</h4><p><strong>%s</strong> commits across "+
+		"<strong>%s</strong> repositories, and <strong>%s</strong> other contributions</p>",
+		formatMetric(summary.Totals.TotalCommitContributions, commits),
+		formatMetric(summary.Totals.TotalRepositories, repositories),
+		formatMetric(summary.Totals.TotalOtherContributions, other))
+
+	if len(summary.Users) > 0 {
+		body.WriteString("<ul>")
+		for _, user := range summary.Users {
+			commits, repositories, other := metricDeltas(user.Delta)
+			fmt.Fprintf(&body, "<li><strong>%s</strong>: %s commits, %s repositories, %s other</li>",
+				html.EscapeString(user.User),
+				formatMetric(user.Totals.TotalCommitContributions, commits),
+				formatMetric(user.Totals.TotalRepositories, repositories),
+				formatMetric(user.Totals.TotalOtherContributions, other))
+		}
+		body.WriteString("</ul>")
+	}
+	return body.String()
+}
@@ -0,0 +1,56 @@
+package reporting_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the NewMatrix constructor
+func TestNewMatrix(t *testing.T) {
+	_, err := rpt.NewMatrix("", "!room:example.org", "token")
+	assert.Error(t, err)
+
+	_, err = rpt.NewMatrix("https://matrix.example.org", "!room:example.org", "")
+	assert.Error(t, err)
+}
+
+// Test the Matrix Export method with a room alias
+func TestMatrixExport(t *testing.T) {
+	var sentPath, authorization string
+	var content map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "/directory/room/") {
+			w.Write([]byte(`{"room_id": "!abc:example.org"}`))
+			return
+		}
+		sentPath = r.URL.Path
+		authorization = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&content)
+		w.Write([]byte(`{"event_id": "$1"}`))
+	}))
+	defer server.Close()
+
+	matrix, err := rpt.NewMatrix(server.URL, "#team:example.org", "secret")
+	assert.NoError(t, err)
+
+	results := rpt.AggregatedResults{
+		TotalCommitContributions: 10,
+		ByUser: map[string]rpt.Totals{
+			"user1": {TotalCommitContributions: 10},
+		},
+	}
+	err = matrix.Export(nil, results)
+	assert.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(sentPath, "/_matrix/client/v3/rooms/!abc:example.org/send/m.room.message/"))
+	assert.Equal(t, "Bearer secret", authorization)
+	assert.Equal(t, "m.notice", content["msgtype"])
+	assert.Contains(t, content["body"], "Commits: 10")
+	assert.Contains(t, content["formatted_body"], "<li><strong>user1</strong>: 10 commits")
+}
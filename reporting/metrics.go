@@ -0,0 +1,138 @@
+package reporting
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// A metric is a single Prometheus gauge or counter sample with optional labels
+type metric struct {
+	name    string
+	help    string
+	labels  map[string]string
+	value   float64
+	counter bool
+}
+
+// metrics returns the aggregated results as a list of Prometheus gauges
+func (a AggregatedResults) metrics() []metric {
+	return []metric{
+		{
+			name:  MetricsNamespace + "_total_commit_contributions",
+			help:  "The count of all commits across all users in the results.",
+			value: float64(a.TotalCommitContributions),
+		},
+		{
+			name:  MetricsNamespace + "_total_repositories",
+			help:  "The count of unique repositories contributed to.",
+			value: float64(a.TotalRepositories),
+		},
+		{
+			name:  MetricsNamespace + "_total_other_contributions",
+			help:  "The count of all issues, pull requests, and pull request reviews.",
+			value: float64(a.TotalOtherContributions),
+		},
+		{
+			name:  MetricsNamespace + "_last_run_timestamp_seconds",
+			help:  "The Unix time of the run that produced the results.",
+			value: float64(a.Timestamp),
+		},
+	}
+}
+
+// userYearMetrics returns the contributions of each user-year as Prometheus
+// gauges labeled by user and year, grouped by name
+func userYearMetrics(queryResults map[string]QueryResult) []metric {
+
+	var names = []struct {
+		name  string
+		help  string
+		count func(collection ContributionsCollection) githubv4.Int
+	}{
+		{"commit_contributions", "The count of commits by the user in the year.",
+			func(c ContributionsCollection) githubv4.Int { return c.TotalCommitContributions }},
+		{"issue_contributions", "The count of issues opened by the user in the year.",
+			func(c ContributionsCollection) githubv4.Int { return c.TotalIssueContributions }},
+		{"pull_request_contributions", "The count of pull requests opened by the user in the year.",
+			func(c ContributionsCollection) githubv4.Int { return c.TotalPullRequestContributions }},
+		{"pull_request_review_contributions", "The count of pull request reviews by the user in the year.",
+			func(c ContributionsCollection) githubv4.Int { return c.TotalPullRequestReviewContributions }},
+	}
+	var metrics []metric
+	for _, name := range names {
+		for _, userYear := range slices.Sorted(maps.Keys(queryResults)) {
+			user, year := splitUserYear(userYear)
+			metrics = append(metrics, metric{
+				name:   MetricsNamespace + "_" + name.name,
+				help:   name.help,
+				labels: map[string]string{"user": user, "year": strconv.Itoa(year)},
+				value:  float64(name.count(queryResults[userYear].User.ContributionsCollection)),
+			})
+		}
+	}
+	return metrics
+}
+
+// metrics returns the lowest rate limit points remaining for each API resource
+// as Prometheus gauges
+func (r RunStats) metrics() []metric {
+	var metrics []metric
+	for _, resource := range slices.Sorted(maps.Keys(r.RateLimits)) {
+		metrics = append(metrics, metric{
+			name:   MetricsNamespace + "_rate_limit_remaining",
+			help:   "The lowest rate limit points remaining for any token in the last run.",
+			labels: map[string]string{"resource": resource},
+			value:  float64(r.RateLimits[resource].Remaining),
+		})
+	}
+	return metrics
+}
+
+// WriteMetrics writes the aggregated results in the Prometheus text exposition format
+func (a AggregatedResults) WriteMetrics(w io.Writer) error {
+	return writeMetrics(w, a.metrics())
+}
+
+// writeMetrics writes each metric sample, emitting the HELP and TYPE
+// lines once for each consecutive group of samples sharing a name
+func writeMetrics(w io.Writer, metrics []metric) error {
+	previousName := ""
+	for _, m := range metrics {
+		if m.name != previousName {
+			kind := "gauge"
+			if m.counter {
+				kind = "counter"
+			}
+			_, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", m.name, m.help, m.name, kind)
+			if err != nil {
+				return err
+			}
+			previousName = m.name
+		}
+		_, err := fmt.Fprintf(w, "%s%s %s\n", m.name, formatLabels(m.labels),
+			strconv.FormatFloat(m.value, 'f', -1, 64))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// formatLabels renders a label set as {key="value",...} in key order
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	escaper := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	pairs := make([]string, 0, len(labels))
+	for _, key := range slices.Sorted(maps.Keys(labels)) {
+		pairs = append(pairs, key+`="`+escaper.Replace(labels[key])+`"`)
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
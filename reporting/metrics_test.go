@@ -0,0 +1,30 @@
+package reporting_test
+
+import (
+	"strings"
+	"testing"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the WriteMetrics method
+func TestWriteMetrics(t *testing.T) {
+	results := rpt.AggregatedResults{
+		Timestamp:                1730498450,
+		TotalCommitContributions: 1234,
+		TotalRepositories:        5,
+		TotalOtherContributions:  678,
+	}
+
+	var metrics strings.Builder
+	err := results.WriteMetrics(&metrics)
+	assert.NoError(t, err)
+
+	output := metrics.String()
+	assert.Contains(t, output, "# TYPE ghcontributions_total_commit_contributions gauge\n")
+	assert.Contains(t, output, "ghcontributions_total_commit_contributions 1234\n")
+	assert.Contains(t, output, "ghcontributions_total_repositories 5\n")
+	assert.Contains(t, output, "ghcontributions_total_other_contributions 678\n")
+	assert.Contains(t, output, "ghcontributions_last_run_timestamp_seconds 1730498450\n")
+}
@@ -0,0 +1,119 @@
+package reporting
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// The milestone metrics
+const (
+	MilestoneContributions = "contributions"
+	MilestoneCommits       = "commits"
+	MilestoneReviews       = "reviews"
+	MilestoneRepositories  = "repositories"
+)
+
+// A Milestone is a round number of a user's contributions, counted from the
+// first year collected, and the date it was reached
+type Milestone struct {
+	User   string `json:"user"`
+	Metric string `json:"metric"`
+	Value  int    `json:"value"`
+	// The date, estimated from a steady pace through the year it was reached in
+	Date string `json:"date"`
+}
+
+// The smallest milestone of each metric. Larger milestones follow at
+// five and ten times the one before.
+var firstMilestones = map[string]int{
+	MilestoneContributions: 100,
+	MilestoneCommits:       100,
+	MilestoneReviews:       100,
+	MilestoneRepositories:  10,
+}
+
+// FindMilestones finds the milestones each user reached, from the cumulative
+// counts each year, sorted by date
+func FindMilestones(queryResults map[string]QueryResult) []Milestone {
+
+	var milestones []Milestone
+	for user, userQueryResults := range groupQueryResults(queryResults, func(user string, year int) string {
+		return user
+	}) {
+		// Each year's counts, with repositories counted the first year they appear
+		byYear := make(map[int]map[string]int)
+		var seen = make(map[string]bool)
+		var years []int
+		for userYear := range userQueryResults {
+			_, year := splitUserYear(userYear)
+			years = append(years, year)
+		}
+		slices.Sort(years)
+		for _, year := range years {
+			collection := userQueryResults[user+"-"+strconv.Itoa(year)].User.ContributionsCollection
+			counts := map[string]int{
+				MilestoneContributions: int(collection.TotalCommitContributions + collection.TotalIssueContributions +
+					collection.TotalPullRequestContributions + collection.TotalPullRequestReviewContributions),
+				MilestoneCommits: int(collection.TotalCommitContributions),
+				MilestoneReviews: int(collection.TotalPullRequestReviewContributions),
+			}
+			for _, repository := range collection.RepositoryContributions() {
+				if !seen[repository.key()] {
+					seen[repository.key()] = true
+					counts[MilestoneRepositories]++
+				}
+			}
+			byYear[year] = counts
+		}
+
+		for _, metric := range slices.Sorted(maps.Keys(firstMilestones)) {
+			var total int
+			next := firstMilestones[metric]
+			for _, year := range years {
+				count := byYear[year][metric]
+				for total+count >= next {
+					milestones = append(milestones, Milestone{
+						User:   user,
+						Metric: metric,
+						Value:  next,
+						Date:   milestoneDate(year, float64(next-total)/float64(count)),
+					})
+					next = nextMilestone(next)
+				}
+				total += count
+			}
+		}
+	}
+
+	slices.SortFunc(milestones, func(a, b Milestone) int {
+		return cmp.Or(cmp.Compare(a.Date, b.Date), cmp.Compare(a.User, b.User),
+			cmp.Compare(a.Metric, b.Metric), cmp.Compare(a.Value, b.Value))
+	})
+	return milestones
+}
+
+// nextMilestone returns the milestone after the value, alternating between
+// five and two times the one before, like 100, 500, 1000, 5000
+func nextMilestone(value int) int {
+	power := 1
+	for power*10 <= value {
+		power *= 10
+	}
+	if value == power {
+		return value * 5
+	}
+	return value * 2
+}
+
+// milestoneDate estimates the date a fraction of the way through the year
+func milestoneDate(year int, fraction float64) string {
+	yearRange := YearRange(year, year)
+	date := yearRange.From.Add(time.Duration(fraction * float64(yearRange.To.Sub(yearRange.From))))
+	if !date.Before(yearRange.To) {
+		date = yearRange.To.AddDate(0, 0, -1)
+	}
+	return date.Format(time.DateOnly)
+}
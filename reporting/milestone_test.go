@@ -0,0 +1,54 @@
+package reporting_test
+
+import (
+	"testing"
+	"time"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test finding the milestones reached from the cumulative counts each year
+func TestFindMilestones(t *testing.T) {
+	// 60 commits in 2021, then 60 in 2022, and 480 in 2023
+	queryResults := commitsByYear("user1", map[int]int{2021: 60, 2022: 60, 2023: 480})
+
+	milestones := rpt.FindMilestones(queryResults)
+	assert.Equal(t, []rpt.Milestone{
+		// The 100th commit is 40 of the 60 in 2022, two thirds of the way through the year
+		{User: "user1", Metric: rpt.MilestoneCommits, Value: 100, Date: "2022-09-01"},
+		{User: "user1", Metric: rpt.MilestoneContributions, Value: 100, Date: "2022-09-01"},
+		// The 500th is 380 of the 480 in 2023
+		{User: "user1", Metric: rpt.MilestoneCommits, Value: 500, Date: "2023-10-16"},
+		{User: "user1", Metric: rpt.MilestoneContributions, Value: 500, Date: "2023-10-16"},
+	}, milestones)
+}
+
+// Test the sequence of milestones and the repositories milestone
+func TestFindMilestonesSequence(t *testing.T) {
+	var contributions []rpt.Contribution
+	date := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	for index := range 12 {
+		contributions = append(contributions, rpt.Contribution{
+			Kind:       rpt.ContributionPullRequestReview,
+			Repository: rpt.Repository{Name: string(rune('a' + index))},
+			Date:       date,
+			Count:      100,
+		})
+	}
+	milestones := rpt.FindMilestones(rpt.QueryResultsFromContributions("user1", contributions))
+
+	var values = make(map[string][]int)
+	for _, milestone := range milestones {
+		values[milestone.Metric] = append(values[milestone.Metric], milestone.Value)
+		assert.Regexp(t, `^2020-`, milestone.Date)
+	}
+	assert.Equal(t, []int{100, 500, 1000}, values[rpt.MilestoneReviews])
+	assert.Equal(t, []int{100, 500, 1000}, values[rpt.MilestoneContributions])
+	assert.Equal(t, []int{10}, values[rpt.MilestoneRepositories])
+	assert.Empty(t, values[rpt.MilestoneCommits])
+
+	// Ensure a milestone reached by the last contribution of the year falls on its last day
+	milestones = rpt.FindMilestones(commitsByYear("user1", map[int]int{2020: 100}))
+	assert.Equal(t, "2020-12-31", milestones[0].Date)
+}
@@ -0,0 +1,47 @@
+package reporting
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+)
+
+// DecryptOpenPGP decrypts an OpenPGP message encrypted with a passphrase, like with
+// gpg -c, either ASCII armored or binary. The passphrase function is called once,
+// only for a message that needs one. A message encrypted to a key is an error,
+// leaving it to gpg and the keys of its agent.
+func DecryptOpenPGP(ciphertext []byte, passphrase func() ([]byte, error)) ([]byte, error) {
+
+	var reader io.Reader = bytes.NewReader(ciphertext)
+	if bytes.HasPrefix(bytes.TrimSpace(ciphertext), []byte("-----BEGIN PGP")) {
+		block, err := armor.Decode(reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode the armored message: %w", err)
+		}
+		reader = block.Body
+	}
+
+	prompted := false
+	message, err := openpgp.ReadMessage(reader, openpgp.EntityList{}, func(keys []openpgp.Key, symmetric bool) ([]byte, error) {
+		if !symmetric {
+			return nil, errors.New("the message is encrypted to a key rather than a passphrase")
+		}
+		if prompted {
+			return nil, errors.New("the passphrase is incorrect")
+		}
+		prompted = true
+		return passphrase()
+	}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt the message: %w", err)
+	}
+	plaintext, err := io.ReadAll(message.UnverifiedBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt the message: %w", err)
+	}
+	return plaintext, nil
+}
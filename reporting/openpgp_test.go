@@ -0,0 +1,78 @@
+package reporting_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// encryptSymmetrically encrypts the plaintext with the passphrase, like gpg -c,
+// armored like gpg -ca when asked
+func encryptSymmetrically(t *testing.T, plaintext string, passphrase string, armored bool) []byte {
+	var ciphertext bytes.Buffer
+	var writer io.WriteCloser = nopWriteCloser{&ciphertext}
+	if armored {
+		var err error
+		writer, err = armor.Encode(&ciphertext, "PGP MESSAGE", nil)
+		require.NoError(t, err)
+	}
+	plaintextWriter, err := openpgp.SymmetricallyEncrypt(writer, []byte(passphrase), nil, nil)
+	require.NoError(t, err)
+	_, err = plaintextWriter.Write([]byte(plaintext))
+	require.NoError(t, err)
+	require.NoError(t, plaintextWriter.Close())
+	require.NoError(t, writer.Close())
+	return ciphertext.Bytes()
+}
+
+// A nopWriteCloser adds a Close method that does nothing to a buffer
+type nopWriteCloser struct {
+	*bytes.Buffer
+}
+
+// Close does nothing
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+// Test decrypting passphrase encrypted credentials
+func TestDecryptOpenPGP(t *testing.T) {
+	credentials := `[{"username": "user1", "token": "token1"}]`
+	prompts := 0
+	passphrase := func(value string) func() ([]byte, error) {
+		return func() ([]byte, error) {
+			prompts++
+			return []byte(value), nil
+		}
+	}
+
+	for _, armored := range []bool{false, true} {
+		ciphertext := encryptSymmetrically(t, credentials, "secret", armored)
+		plaintext, err := rpt.DecryptOpenPGP(ciphertext, passphrase("secret"))
+		require.NoError(t, err)
+		assert.Equal(t, credentials, string(plaintext))
+	}
+	assert.Equal(t, 2, prompts)
+
+	// Ensure a wrong passphrase is tried only once
+	prompts = 0
+	_, err := rpt.DecryptOpenPGP(encryptSymmetrically(t, credentials, "secret", true), passphrase("wrong"))
+	assert.Error(t, err)
+	assert.Equal(t, 1, prompts)
+
+	// Ensure a failed prompt stops the decryption
+	promptErr := errors.New("no terminal")
+	_, err = rpt.DecryptOpenPGP(encryptSymmetrically(t, credentials, "secret", false),
+		func() ([]byte, error) { return nil, promptErr })
+	assert.ErrorIs(t, err, promptErr)
+
+	_, err = rpt.DecryptOpenPGP([]byte(credentials), passphrase("secret"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,93 @@
+package reporting
+
+import (
+	"cmp"
+	"math"
+	"slices"
+)
+
+// A SharedRepository is a repository more than one user contributed to, with
+// how the contributions split between them
+type SharedRepository struct {
+	Repository
+	// The contributions of each user, with the most contributions first
+	Users []UserShare `json:"users"`
+}
+
+// A UserShare holds a user's contributions to a shared repository
+type UserShare struct {
+	User    string `json:"user"`
+	Commits int    `json:"commits"`
+	// The issues, pull requests, and pull request reviews
+	Other int `json:"other"`
+	// The user's percentage of all the contributions to the repository
+	Share float64 `json:"share"`
+}
+
+// SharedRepositories finds the repositories more than one user contributed to
+// across all years, with the repositories shared by the most users first, then
+// those with the most contributions
+func SharedRepositories(queryResults map[string]QueryResult) []SharedRepository {
+
+	// Sum each user's contributions to each repository
+	var shares = make(map[string]map[string]*UserShare)
+	var repositories = make(map[string]Repository)
+	for userYear, queryResult := range queryResults {
+		user, _ := splitUserYear(userYear)
+		collection := queryResult.User.ContributionsCollection
+		add := func(repositoryContributions []RepositoryContribution, commits bool) {
+			for _, repositoryContribution := range repositoryContributions {
+				key := repositoryContribution.key()
+				if shares[key] == nil {
+					shares[key] = make(map[string]*UserShare)
+					repositories[key] = repositoryContribution.repository()
+				}
+				if shares[key][user] == nil {
+					shares[key][user] = &UserShare{User: user}
+				}
+				if commits {
+					shares[key][user].Commits += int(repositoryContribution.Contributions.TotalCount)
+				} else {
+					shares[key][user].Other += int(repositoryContribution.Contributions.TotalCount)
+				}
+			}
+		}
+		add(collection.CommitContributionsByRepository, true)
+		add(collection.IssueContributionsByRepository, false)
+		add(collection.PullRequestContributionsByRepository, false)
+		add(collection.PullRequestReviewContributionsByRepository, false)
+	}
+
+	var sharedRepositories = make([]SharedRepository, 0)
+	var totals = make(map[string]int)
+	for key, userShares := range shares {
+		if len(userShares) < 2 {
+			continue
+		}
+		sharedRepository := SharedRepository{Repository: repositories[key]}
+		for _, userShare := range userShares {
+			totals[key] += userShare.Commits + userShare.Other
+			sharedRepository.Users = append(sharedRepository.Users, *userShare)
+		}
+		for i := range sharedRepository.Users {
+			userShare := &sharedRepository.Users[i]
+			if totals[key] > 0 {
+				userShare.Share = math.Round(float64(userShare.Commits+userShare.Other)/float64(totals[key])*1000) / 10
+			}
+		}
+		slices.SortFunc(sharedRepository.Users, func(a, b UserShare) int {
+			return cmp.Or(cmp.Compare(b.Commits+b.Other, a.Commits+a.Other), cmp.Compare(a.User, b.User))
+		})
+		sharedRepositories = append(sharedRepositories, sharedRepository)
+	}
+
+	slices.SortFunc(sharedRepositories, func(a, b SharedRepository) int {
+		return cmp.Or(
+			cmp.Compare(len(b.Users), len(a.Users)),
+			cmp.Compare(totals[b.key()], totals[a.key()]),
+			cmp.Compare(a.Name, b.Name),
+			cmp.Compare(a.URL, b.URL),
+		)
+	})
+	return sharedRepositories
+}
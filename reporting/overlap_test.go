@@ -0,0 +1,60 @@
+package reporting_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test finding the repositories several users contributed to
+func TestSharedRepositories(t *testing.T) {
+	shared := rpt.Repository{Name: "team/shared", URL: "https://github.com/team/shared"}
+	other := rpt.Repository{Name: "team/other", URL: "https://github.com/team/other"}
+	solo := rpt.Repository{Name: "alice/solo", URL: "https://github.com/alice/solo"}
+	date := time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	queryResults := rpt.QueryResultsFromContributions("alice", []rpt.Contribution{
+		{Kind: rpt.ContributionCommit, Repository: shared, Date: date, Count: 6},
+		{Kind: rpt.ContributionPullRequest, Repository: shared, Date: date, Count: 2},
+		{Kind: rpt.ContributionCommit, Repository: other, Date: date, Count: 1},
+		{Kind: rpt.ContributionCommit, Repository: solo, Date: date, Count: 9},
+	})
+	rpt.MergeQueryResults(queryResults, rpt.QueryResultsFromContributions("bob", []rpt.Contribution{
+		{Kind: rpt.ContributionCommit, Repository: shared, Date: date, Count: 1},
+		{Kind: rpt.ContributionPullRequestReview, Repository: shared, Date: date.AddDate(-1, 0, 0), Count: 1},
+		{Kind: rpt.ContributionIssue, Repository: other, Date: date, Count: 3},
+	}))
+
+	sharedRepositories := rpt.SharedRepositories(queryResults)
+	assert.Equal(t, []rpt.SharedRepository{
+		{
+			Repository: shared,
+			Users: []rpt.UserShare{
+				{User: "alice", Commits: 6, Other: 2, Share: 80},
+				{User: "bob", Commits: 1, Other: 1, Share: 20},
+			},
+		},
+		{
+			Repository: other,
+			Users: []rpt.UserShare{
+				{User: "bob", Commits: 0, Other: 3, Share: 75},
+				{User: "alice", Commits: 1, Other: 0, Share: 25},
+			},
+		},
+	}, sharedRepositories)
+
+	// Ensure the report only lists shared repositories for more than one user
+	reporter := &rpt.Reporter{}
+	aggregatedResults, err := reporter.Aggregate(context.Background(), queryResults)
+	assert.NoError(t, err)
+	assert.Equal(t, sharedRepositories, aggregatedResults.SharedRepositories)
+
+	singleUser, err := loadQueryResultsMap("single_user_single_year.json")
+	assert.NoError(t, err)
+	aggregatedResults, err = reporter.Aggregate(context.Background(), singleUser)
+	assert.NoError(t, err)
+	assert.Empty(t, aggregatedResults.SharedRepositories)
+}
@@ -0,0 +1,129 @@
+package reporting
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// The granularities of the periods a report breaks the contributions down by
+const (
+	GranularityYear    = "year"
+	GranularityQuarter = "quarter"
+	GranularityMonth   = "month"
+)
+
+// The granularities, from the coarsest
+var Granularities = []string{GranularityYear, GranularityQuarter, GranularityMonth}
+
+// periodMonths returns the number of months in each period of the granularity,
+// with a year for an unknown granularity
+func periodMonths(granularity string) int {
+	switch granularity {
+	case GranularityQuarter:
+		return 3
+	case GranularityMonth:
+		return 1
+	}
+	return 12
+}
+
+// SplitPeriods splits the range into the periods of the granularity, newest first,
+// with the first and last clipped to the range. Years and quarters start in the
+// fiscal year start month, like FiscalYearRange.
+func SplitPeriods(dateRange DateRange, granularity string, fiscalYearStart time.Month) []DateRange {
+
+	months := periodMonths(granularity)
+	var periods []DateRange
+	lastYear := FiscalYear(dateRange.To.Add(-time.Second), fiscalYearStart)
+	for year := lastYear; year >= FiscalYear(dateRange.From, fiscalYearStart); year-- {
+		yearRange := FiscalYearRange(year, year, fiscalYearStart)
+		for month := 12 - months; month >= 0; month -= months {
+			from := yearRange.From.AddDate(0, month, 0)
+			to := from.AddDate(0, months, 0)
+			if !to.After(dateRange.From) || !from.Before(dateRange.To) {
+				continue
+			}
+			if from.Before(dateRange.From) {
+				from = dateRange.From
+			}
+			if to.After(dateRange.To) {
+				to = dateRange.To
+			}
+			periods = append(periods, DateRange{From: from, To: to})
+		}
+	}
+	return periods
+}
+
+// PeriodLabel names the period of the granularity the time falls in, in UTC, like
+// 2023-Q2 for a quarter, 2023-06 for a month, or 2023 for a year. Fiscal years and
+// quarters are named like the fiscal year, like FY2023-Q2.
+func PeriodLabel(t time.Time, granularity string, fiscalYearStart time.Month) string {
+
+	t = t.UTC()
+	year := strconv.Itoa(FiscalYear(t, fiscalYearStart))
+	if fiscalYearStart > time.January {
+		year = "FY" + year
+	}
+	switch granularity {
+	case GranularityQuarter:
+		monthOfYear := (int(t.Month()) - int(max(fiscalYearStart, time.January)) + 12) % 12
+		return fmt.Sprintf("%s-Q%d", year, monthOfYear/3+1)
+	case GranularityMonth:
+		return t.Format("2006-01")
+	}
+	return year
+}
+
+// QueryResultsByPeriod tallies a user's contributions by the periods of the
+// granularity, as query results keyed like user-year within each period's label
+func QueryResultsByPeriod(user string, contributions []Contribution, granularity string,
+	fiscalYearStart time.Month) map[string]map[string]QueryResult {
+
+	var byPeriod = make(map[string][]Contribution)
+	for _, contribution := range contributions {
+		period := PeriodLabel(contribution.Date, granularity, fiscalYearStart)
+		byPeriod[period] = append(byPeriod[period], contribution)
+	}
+	var periodResults = make(map[string]map[string]QueryResult)
+	for period, periodContributions := range byPeriod {
+		periodResults[period] = QueryResultsByFiscalYear(user, periodContributions, fiscalYearStart)
+	}
+	return periodResults
+}
+
+// MergePeriodResults copies the source period results into the destination, adding
+// the contributions of the same user-year in the same period together
+func MergePeriodResults(destination map[string]map[string]QueryResult, source map[string]map[string]QueryResult) {
+	for period, queryResults := range source {
+		if destination[period] == nil {
+			destination[period] = make(map[string]QueryResult)
+		}
+		MergeQueryResults(destination[period], queryResults)
+	}
+}
+
+// CollectPeriods collects the contributions from the provider like Collect, also
+// tallying them by the periods of the collector's granularity. The provider only
+// tells the periods apart when it dates each contribution within its period, like
+// a GitHub provider with the same granularity.
+func (p *ProviderCollector) CollectPeriods(ctx context.Context) (queryResults map[string]QueryResult,
+	periodResults map[string]map[string]QueryResult, err error) {
+
+	contributions, err := p.Provider.Collect(ctx, p.User, p.Range)
+	queryResults = QueryResultsByFiscalYear(p.User, contributions, p.FiscalYearStart)
+	periodResults = QueryResultsByPeriod(p.User, contributions, p.Granularity, p.FiscalYearStart)
+	return queryResults, periodResults, err
+}
+
+// AggregatePeriods summarizes each period across all users, keyed by its label,
+// filtering and counting the repositories and the restricted contributions like Aggregate
+func (r *Reporter) AggregatePeriods(periodResults map[string]map[string]QueryResult) map[string]Totals {
+	var byPeriod = make(map[string]Totals)
+	for period, queryResults := range periodResults {
+		byPeriod[period] = r.totals(queryResults)
+	}
+	return byPeriod
+}
@@ -0,0 +1,107 @@
+package reporting_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test splitting a range into quarters and months, newest first and clipped to the range
+func TestSplitPeriods(t *testing.T) {
+	dateRange := rpt.DateRange{From: time.Date(2022, time.February, 15, 0, 0, 0, 0, time.UTC),
+		To: time.Date(2022, time.August, 1, 0, 0, 0, 0, time.UTC)}
+
+	var quarters []string
+	for _, period := range rpt.SplitPeriods(dateRange, rpt.GranularityQuarter, time.January) {
+		quarters = append(quarters, period.String())
+	}
+	assert.Equal(t, []string{"2022-07-01..2022-07-31", "2022-04-01..2022-06-30", "2022-02-15..2022-03-31"}, quarters)
+	assert.Len(t, rpt.SplitPeriods(dateRange, rpt.GranularityMonth, time.January), 6)
+	assert.Equal(t, []rpt.DateRange{dateRange}, rpt.SplitPeriods(dateRange, rpt.GranularityYear, time.January))
+
+	// Ensure fiscal quarters start in the fiscal year start month
+	fiscalQuarters := rpt.SplitPeriods(rpt.FiscalYearRange(2023, 2023, time.July), rpt.GranularityQuarter, time.July)
+	require.Len(t, fiscalQuarters, 4)
+	assert.Equal(t, "2023-04-01..2023-06-30", fiscalQuarters[0].String())
+	assert.Equal(t, "2022-07-01..2022-09-30", fiscalQuarters[3].String())
+}
+
+// Test naming the period a time falls in
+func TestPeriodLabel(t *testing.T) {
+	date := time.Date(2022, time.August, 10, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, "2022-Q3", rpt.PeriodLabel(date, rpt.GranularityQuarter, time.January))
+	assert.Equal(t, "FY2023-Q1", rpt.PeriodLabel(date, rpt.GranularityQuarter, time.July))
+	assert.Equal(t, "FY2022-Q4", rpt.PeriodLabel(date, rpt.GranularityQuarter, time.October))
+	assert.Equal(t, "2022-08", rpt.PeriodLabel(date, rpt.GranularityMonth, time.July))
+	assert.Equal(t, "2022", rpt.PeriodLabel(date, rpt.GranularityYear, time.January))
+	assert.Equal(t, "FY2023", rpt.PeriodLabel(date, rpt.GranularityYear, time.July))
+}
+
+// Test collecting and aggregating quarters, queried a quarter at a time
+func TestCollectPeriods(t *testing.T) {
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Query     string
+			Variables map[string]any
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+
+		// Answer each aliased quarter with commits to one repository
+		var user = map[string]any{"login": "user1"}
+		for i := 0; request.Variables[fmt.Sprintf("from%d", i)] != nil; i++ {
+			ranges = append(ranges, request.Variables[fmt.Sprintf("from%d", i)].(string)[:10])
+			user[fmt.Sprintf("year%d", i)] = map[string]any{
+				"totalCommitContributions": i + 1,
+				"commitContributionsByRepository": []map[string]any{{
+					"repository":    map[string]any{"name": "app", "url": "https://github.com/owner/app"},
+					"contributions": map[string]any{"totalCount": i + 1},
+				}},
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"user": user}})
+	}))
+	defer server.Close()
+
+	gitHub, err := rpt.NewGitHub(githubv4.NewEnterpriseClient(server.URL, server.Client()))
+	require.NoError(t, err)
+	gitHub.Granularity = rpt.GranularityQuarter
+	var queriedYears []int
+	gitHub.OnQuery = func(user string, years int, latency time.Duration) {
+		queriedYears = append(queriedYears, years)
+	}
+	collector := &rpt.ProviderCollector{Provider: gitHub, User: "user1", Range: rpt.YearRange(2022, 2022),
+		Granularity: rpt.GranularityQuarter}
+	queryResults, periodResults, err := collector.CollectPeriods(context.Background())
+	require.NoError(t, err)
+
+	// Ensure each quarter is queried, and the year counts as done with its first quarter
+	assert.Equal(t, []string{"2022-10-01", "2022-07-01", "2022-04-01", "2022-01-01"}, ranges)
+	assert.Equal(t, []int{1}, queriedYears)
+	assert.Equal(t, 10, int(queryResults["user1-2022"].User.ContributionsCollection.TotalCommitContributions))
+
+	reporter := &rpt.Reporter{}
+	byPeriod := reporter.AggregatePeriods(periodResults)
+	assert.Equal(t, rpt.Totals{TotalCommitContributions: 1, TotalRepositories: 1}, byPeriod["2022-Q4"])
+	assert.Equal(t, rpt.Totals{TotalCommitContributions: 4, TotalRepositories: 1}, byPeriod["2022-Q1"])
+
+	// Ensure the markdown has a table of the quarters
+	aggregatedResults, err := reporter.Aggregate(context.Background(), queryResults)
+	require.NoError(t, err)
+	aggregatedResults.ByPeriod = byPeriod
+	aggregatedResults.Granularity = rpt.GranularityQuarter
+	var markdown bytes.Buffer
+	require.NoError(t, aggregatedResults.WriteMarkdown(&markdown))
+	assert.Contains(t, markdown.String(), "### By quarter\n\n| Quarter | Commits | Repositories | Other contributions |")
+	assert.Contains(t, markdown.String(), "| 2022-Q1 | 4 | 1 | 0 |")
+}
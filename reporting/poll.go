@@ -0,0 +1,42 @@
+package reporting
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// Poll collects at once and then again at each interval, or as soon as a
+// refresh arrives, until the context is done. A failure of the first collection
+// is returned, as it's likely a misconfiguration, while later failures are
+// logged and collected again at the next interval.
+func Poll(ctx context.Context, interval time.Duration, refresh <-chan struct{}, collect func(ctx context.Context) error) error {
+
+	if interval <= 0 {
+		return fmt.Errorf("the polling interval must be positive, not %s", interval)
+	}
+	err := collect(ctx)
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+		case <-ticker.C:
+		case <-refresh:
+			// Restart the interval from the refresh
+			ticker.Reset(interval)
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		err = collect(ctx)
+		if err != nil {
+			slog.Error("Couldn't collect", "error", err)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package reporting_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test collecting at each interval and on a refresh until canceled
+func TestPoll(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	refresh := make(chan struct{}, 1)
+	collections := make(chan int, 10)
+	count := 0
+	done := make(chan error)
+	go func() {
+		done <- rpt.Poll(ctx, time.Hour, refresh, func(ctx context.Context) error {
+			count++
+			collections <- count
+			if count > 1 {
+				return errors.New("timeout")
+			}
+			return nil
+		})
+	}()
+
+	// Ensure the first collection runs at once, and a refresh runs another, even after a failure
+	assert.Equal(t, 1, <-collections)
+	refresh <- struct{}{}
+	assert.Equal(t, 2, <-collections)
+	refresh <- struct{}{}
+	assert.Equal(t, 3, <-collections)
+
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+// Test stopping the poll when the first collection fails
+func TestPollError(t *testing.T) {
+	err := rpt.Poll(context.Background(), time.Hour, nil, func(ctx context.Context) error {
+		return errors.New("unknown user")
+	})
+	assert.Error(t, err)
+
+	err = rpt.Poll(context.Background(), 0, nil, func(ctx context.Context) error { return nil })
+	assert.Error(t, err)
+}
@@ -0,0 +1,114 @@
+package reporting
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// A Profile describes a user with the public fields of their account, so a
+// shared report is self-describing
+type Profile struct {
+	Name      string `json:"name,omitempty"`
+	Company   string `json:"company,omitempty"`
+	Location  string `json:"location,omitempty"`
+	Followers int    `json:"followers"`
+	Bio       string `json:"bio,omitempty"`
+	// The organizations the user is a member of
+	Organizations []Organization `json:"organizations,omitempty"`
+}
+
+// An Organization names an organization a user is a member of
+type Organization struct {
+	Login string `json:"login"`
+	Name  string `json:"name,omitempty"`
+}
+
+// A ProfileSource looks up a user's profile, for providers with profile metadata
+type ProfileSource interface {
+	Profile(ctx context.Context, user string) (Profile, error)
+}
+
+// A profileQuery selects the public profile fields of a user
+type profileQuery struct {
+	User struct {
+		Name      githubv4.String
+		Company   githubv4.String
+		Location  githubv4.String
+		Bio       githubv4.String
+		Followers struct {
+			TotalCount githubv4.Int
+		}
+		Organizations struct {
+			Nodes []struct {
+				Login githubv4.String
+				Name  githubv4.String
+			}
+		} `graphql:"organizations(first: 100)"`
+	} `graphql:"user(login: $login)"`
+}
+
+// Profile looks up the user's public profile and organizations. The organizations
+// include private memberships the token can see, unless PublicOrganizationsOnly is set.
+func (g *GitHub) Profile(ctx context.Context, user string) (Profile, error) {
+
+	var query profileQuery
+	err := g.Client.Query(ctx, &query, map[string]interface{}{
+		"login": githubv4.String(user),
+	})
+	if err != nil {
+		return Profile{}, fmt.Errorf("failed to query github for the profile of %s: %w", user, err)
+	}
+
+	profile := Profile{
+		Name:      string(query.User.Name),
+		Company:   string(query.User.Company),
+		Location:  string(query.User.Location),
+		Followers: int(query.User.Followers.TotalCount),
+		Bio:       string(query.User.Bio),
+	}
+	for _, node := range query.User.Organizations.Nodes {
+		profile.Organizations = append(profile.Organizations, Organization{Login: string(node.Login), Name: string(node.Name)})
+	}
+
+	// Only the REST API limits the list to public memberships
+	if g.PublicOrganizationsOnly {
+		profile.Organizations, err = g.publicOrganizations(ctx, user)
+		if err != nil {
+			return profile, err
+		}
+	}
+	return profile, nil
+}
+
+// publicOrganizations lists the organizations the user is a public member of
+func (g *GitHub) publicOrganizations(ctx context.Context, user string) ([]Organization, error) {
+
+	requestURL := fmt.Sprintf("%s/users/%s/orgs?per_page=100", g.RESTURL, url.PathEscape(user))
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Accept", "application/vnd.github+json")
+
+	response, err := g.HTTPClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the organizations of %s: %w", user, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("failed to list the organizations of %s: github returned %s", user, response.Status)
+	}
+
+	var organizations []Organization
+	err = json.NewDecoder(response.Body).Decode(&organizations)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the github organizations: %w", err)
+	}
+	return organizations, nil
+}
@@ -0,0 +1,71 @@
+package reporting_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test looking up a profile with the GitHub provider
+func TestGitHubProfile(t *testing.T) {
+	client := &pagedGraphQLClient{Pages: []string{
+		`{"user": {"name": "Alice Example", "company": "@example", "location": "Denver, CO",
+			"bio": "Builds things", "followers": {"totalCount": 42},
+			"organizations": {"nodes": [{"login": "example", "name": "Example Inc."}, {"login": "private-team", "name": ""}]}}}`,
+	}}
+	gitHub, err := rpt.NewGitHub(client)
+	require.NoError(t, err)
+
+	profile, err := gitHub.Profile(context.Background(), "alice")
+	require.NoError(t, err)
+	assert.Equal(t, rpt.Profile{
+		Name: "Alice Example", Company: "@example", Location: "Denver, CO", Followers: 42, Bio: "Builds things",
+		Organizations: []rpt.Organization{{Login: "example", Name: "Example Inc."}, {Login: "private-team"}},
+	}, profile)
+	assert.Equal(t, githubv4.String("alice"), client.Variables[0]["login"])
+
+	// Ensure blank fields are left out of the report
+	profileJSON, err := json.Marshal(rpt.Profile{Followers: 3})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"followers": 3}`, string(profileJSON))
+}
+
+// Test limiting a profile to public organization memberships
+func TestGitHubProfilePublicOrganizations(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/users/alice/orgs", r.URL.Path)
+		fmt.Fprint(w, `[{"login": "example", "id": 1, "description": "Makes examples"}]`)
+	}))
+	defer server.Close()
+
+	client := &pagedGraphQLClient{Pages: []string{
+		`{"user": {"name": "Alice Example", "followers": {"totalCount": 1},
+			"organizations": {"nodes": [{"login": "example"}, {"login": "private-team"}]}}}`,
+	}}
+	gitHub, err := rpt.NewGitHub(client)
+	require.NoError(t, err)
+	gitHub.RESTURL = server.URL
+	gitHub.PublicOrganizationsOnly = true
+
+	profile, err := gitHub.Profile(context.Background(), "alice")
+	require.NoError(t, err)
+	assert.Equal(t, "Alice Example", profile.Name)
+	assert.Equal(t, []rpt.Organization{{Login: "example"}}, profile.Organizations)
+}
+
+// Test a failed profile lookup
+func TestGitHubProfileError(t *testing.T) {
+	gitHub, err := rpt.NewGitHub(&pagedGraphQLClient{Pages: []string{`not json`}})
+	require.NoError(t, err)
+
+	_, err = gitHub.Profile(context.Background(), "ghost")
+	assert.ErrorContains(t, err, "failed to query github for the profile of ghost")
+}
@@ -0,0 +1,166 @@
+package reporting
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The width of each progress bar, in characters
+const progressBarWidth = 24
+
+// A Progress draws a bar for each collection and one for the whole run on a
+// terminal, counting the years collected of those in range. The time remaining is
+// estimated from the observed latency of each query. It's safe for concurrent use.
+type Progress struct {
+	// The terminal the bars are drawn on
+	Writer io.Writer
+	// The number of collections that run at once, dividing the time remaining
+	Concurrency int
+
+	mu sync.Mutex
+	// The tasks in the order they were tracked
+	tasks []*ProgressTask
+	// The total latency of the queries and the years they collected
+	latency      time.Duration
+	yearsQueried int
+	// The number of lines drawn last, to draw over
+	lines int
+}
+
+// A ProgressTask is the progress of one collection. A nil task tracks nothing,
+// for runs without a progress bar.
+type ProgressTask struct {
+	progress *Progress
+	name     string
+	years    int
+	done     int
+	finished bool
+}
+
+// Constructs a new Progress object
+// The writer is the terminal the bars are drawn on
+// The concurrency is the number of collections that run at once
+func NewProgress(writer io.Writer, concurrency int) *Progress {
+	return &Progress{Writer: writer, Concurrency: max(concurrency, 1)}
+}
+
+// Track adds a bar for a collection of the years, named like its user
+func (p *Progress) Track(name string, years int) *ProgressTask {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	task := &ProgressTask{progress: p, name: name, years: max(years, 0)}
+	p.tasks = append(p.tasks, task)
+	p.draw()
+	return task
+}
+
+// Advance counts the years a query collected and the time it took
+func (t *ProgressTask) Advance(years int, latency time.Duration) {
+	if t == nil {
+		return
+	}
+	p := t.progress
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	t.done = min(t.done+years, t.years)
+	p.latency += latency
+	p.yearsQueried += years
+	p.draw()
+}
+
+// Finish completes the collection, counting the years it had no need to query,
+// like the cached years or those before the account was created
+func (t *ProgressTask) Finish() {
+	if t == nil {
+		return
+	}
+	p := t.progress
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	t.done = t.years
+	t.finished = true
+	p.draw()
+}
+
+// Write writes log lines above the bars, rather than having the bars drawn over
+// them, so a Progress can be the writer of the run's logs
+func (p *Progress) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.lines > 0 {
+		fmt.Fprintf(p.Writer, "\033[%dA\033[J", p.lines)
+		p.lines = 0
+	}
+	n, err := p.Writer.Write(b)
+	if err != nil {
+		return n, err
+	}
+	p.draw()
+	return n, nil
+}
+
+// Remaining estimates the time left from the average latency per year queried,
+// with the unfinished collections running up to the concurrency at once. It's
+// zero until a query has been observed.
+func (p *Progress) Remaining() time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.remaining()
+}
+
+// remaining estimates the time left, with the lock held
+func (p *Progress) remaining() time.Duration {
+	if p.yearsQueried == 0 {
+		return 0
+	}
+	years, running := 0, 0
+	for _, task := range p.tasks {
+		if !task.finished {
+			years += task.years - task.done
+			running++
+		}
+	}
+	if running == 0 {
+		return 0
+	}
+	perYear := p.latency / time.Duration(p.yearsQueried)
+	return perYear * time.Duration(years) / time.Duration(min(running, p.Concurrency))
+}
+
+// draw redraws every bar over those drawn last, with the lock held
+func (p *Progress) draw() {
+	var b strings.Builder
+	if p.lines > 0 {
+		fmt.Fprintf(&b, "\033[%dA", p.lines)
+	}
+	width := len("Total")
+	total, done := 0, 0
+	for _, task := range p.tasks {
+		width = max(width, len(task.name))
+		total += task.years
+		done += task.done
+	}
+	for _, task := range p.tasks {
+		fmt.Fprintf(&b, "\033[2K%-*s %s %d/%d years\n", width, task.name, progressBar(task.done, task.years),
+			task.done, task.years)
+	}
+	fmt.Fprintf(&b, "\033[2K%-*s %s %d/%d years", width, "Total", progressBar(done, total), done, total)
+	if remaining := p.remaining(); remaining > 0 {
+		fmt.Fprintf(&b, ", about %s left", remaining.Round(time.Second))
+	}
+	b.WriteString("\n")
+	p.lines = len(p.tasks) + 1
+	io.WriteString(p.Writer, b.String())
+}
+
+// progressBar draws a bar filled in proportion to the years done
+func progressBar(done int, total int) string {
+	filled := progressBarWidth
+	if total > 0 {
+		filled = progressBarWidth * done / total
+	}
+	return "[" + strings.Repeat("#", filled) + strings.Repeat("-", progressBarWidth-filled) + "]"
+}
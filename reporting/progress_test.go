@@ -0,0 +1,48 @@
+package reporting_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test drawing the progress of collections and estimating the time remaining
+func TestProgress(t *testing.T) {
+	var terminal bytes.Buffer
+	progress := rpt.NewProgress(&terminal, 2)
+	user1 := progress.Track("user1", 10)
+	user2 := progress.Track("user2", 10)
+	assert.Zero(t, progress.Remaining())
+
+	// Ensure the estimate averages the latency per year, with both users running at once
+	user1.Advance(5, 10*time.Second)
+	user2.Advance(5, 10*time.Second)
+	assert.Equal(t, 10*time.Second, progress.Remaining())
+
+	// Ensure a finished collection counts its remaining years, and the rest run alone
+	user1.Finish()
+	assert.Equal(t, 10*time.Second, progress.Remaining())
+
+	terminal.Reset()
+	user2.Advance(2, 4*time.Second)
+	lines := strings.Split(strings.TrimSuffix(terminal.String(), "\n"), "\n")
+	assert.Len(t, lines, 3)
+	assert.Contains(t, lines[0], "\033[3A")
+	assert.Contains(t, lines[0], "user1 [########################] 10/10 years")
+	assert.Contains(t, lines[1], "user2 [################--------] 7/10 years")
+	assert.Contains(t, lines[2], "Total [####################----] 17/20 years, about 6s left")
+
+	// Ensure log lines are written above the bars
+	terminal.Reset()
+	progress.Write([]byte("a log line\n"))
+	assert.True(t, strings.HasPrefix(terminal.String(), "\033[3A\033[Ja log line\n\033[2Kuser1"))
+
+	// Ensure a nil task tracks nothing
+	var untracked *rpt.ProgressTask
+	untracked.Advance(1, time.Second)
+	untracked.Finish()
+}
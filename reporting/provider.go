@@ -0,0 +1,159 @@
+package reporting
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+)
+
+// A ContributionKind names the kind of a contribution
+type ContributionKind string
+
+// The kinds of contributions providers report
+const (
+	ContributionCommit            ContributionKind = "commit"
+	ContributionIssue             ContributionKind = "issue"
+	ContributionPullRequest       ContributionKind = "pull_request"
+	ContributionPullRequestReview ContributionKind = "pull_request_review"
+	ContributionRestricted        ContributionKind = "restricted"
+	// The total of the contribution calendar, like a GitHub profile shows, which
+	// isn't a contribution of its own
+	ContributionCalendarTotal ContributionKind = "calendar_total"
+)
+
+// A Contribution counts contributions of a single kind made to a repository
+// on a date. Providers that report some contributions only as totals leave
+// the repository blank for those.
+type Contribution struct {
+	Kind       ContributionKind `json:"kind"`
+	Repository Repository       `json:"repository"`
+	Date       time.Time        `json:"date"`
+	Count      int              `json:"count"`
+	// For contributions without a repository, the count of repositories of the
+	// kind the provider reported when it listed fewer, like GitHub past MaxRepositories
+	Repositories int `json:"repositories,omitempty"`
+}
+
+// A DateRange holds the dates from the start of From up to, but not including, To
+type DateRange struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// YearRange returns the date range covering the first year through the last year, in UTC
+func YearRange(firstYear int, lastYear int) DateRange {
+	return DateRange{
+		From: time.Date(firstYear, time.January, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(lastYear+1, time.January, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+// FiscalYearRange returns the date range covering the first fiscal year through the
+// last, in UTC, for fiscal years starting in the month and named like the calendar
+// year they end in, so fiscal 2022 starting in July runs from July 2021 through June
+// 2022. Years starting in January, or in no month, are calendar years.
+func FiscalYearRange(firstYear int, lastYear int, start time.Month) DateRange {
+	if start <= time.January {
+		return YearRange(firstYear, lastYear)
+	}
+	return DateRange{
+		From: time.Date(firstYear-1, start, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(lastYear, start, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+// FiscalYear returns the fiscal year the time falls in, in UTC, for fiscal years
+// starting in the month, like FiscalYearRange
+func FiscalYear(t time.Time, start time.Month) int {
+	t = t.UTC()
+	if start > time.January && t.Month() >= start {
+		return t.Year() + 1
+	}
+	return t.Year()
+}
+
+// Contains reports whether the time falls within the range
+func (d DateRange) Contains(t time.Time) bool {
+	return !t.Before(d.From) && t.Before(d.To)
+}
+
+// A Provider collects a user's contributions from a single platform, like GitHub
+// or GitLab. Providers only fetch and translate; the contributions are converted
+// to query results, so every provider shares the same aggregation.
+type Provider interface {
+	Collect(ctx context.Context, user string, dateRange DateRange) ([]Contribution, error)
+}
+
+// A ProviderCollector collects a single user's contributions from a provider
+// as query results keyed like user-year
+type ProviderCollector struct {
+	// The provider the contributions are collected from
+	Provider Provider
+	// The username on the provider
+	User string
+	// The dates to collect contributions for
+	Range DateRange
+	// The month the years of the query results start in, for fiscal years,
+	// or January for calendar years
+	FiscalYearStart time.Month
+	// The granularity of the periods CollectPeriods tallies, like GranularityQuarter
+	Granularity string
+}
+
+// Constructs a new ProviderCollector object
+// The provider is the platform to collect from
+// The user is the username on the provider
+// The firstYear is the first year in the sequence to report
+// The lastYear is the last year in the sequence to report
+func NewProviderCollector(provider Provider, user string, firstYear int, lastYear int) (collector *ProviderCollector, err error) {
+
+	if user == "" {
+		err = fmt.Errorf("user cannot be blank in constructing a collector")
+		return nil, err
+	}
+	firstYear, lastYear = reportingYears(firstYear, lastYear)
+
+	return &ProviderCollector{
+		Provider: provider,
+		User:     user,
+		Range:    YearRange(firstYear, lastYear),
+	}, err
+}
+
+// Collect collects the contributions from the provider and converts them to query results.
+// The contributions collected before an error, or before the context is canceled,
+// are still returned.
+func (p *ProviderCollector) Collect(ctx context.Context) (map[string]QueryResult, error) {
+	contributions, err := p.Provider.Collect(ctx, p.User, p.Range)
+	return QueryResultsByFiscalYear(p.User, contributions, p.FiscalYearStart), err
+}
+
+// QueryResultsFromContributions tallies a user's contributions by year, as query
+// results keyed like user-year
+func QueryResultsFromContributions(user string, contributions []Contribution) map[string]QueryResult {
+	return QueryResultsByFiscalYear(user, contributions, time.January)
+}
+
+// QueryResultsByFiscalYear tallies a user's contributions by the fiscal years
+// starting in the month, as query results keyed like user-year with the fiscal year
+func QueryResultsByFiscalYear(user string, contributions []Contribution, start time.Month) map[string]QueryResult {
+
+	var tallies = make(map[int]*contributionTally)
+	for _, contribution := range contributions {
+		year := FiscalYear(contribution.Date, start)
+		if tallies[year] == nil {
+			tallies[year] = &contributionTally{}
+		}
+		tallies[year].add(contribution)
+	}
+
+	var queryResults = make(map[string]QueryResult)
+	for year, tally := range tallies {
+		userYear := user + "-" + strconv.Itoa(year)
+		slog.Debug("Collected", "user", user, "year", year)
+		queryResults[userYear] = tally.queryResult(user)
+	}
+	return queryResults
+}
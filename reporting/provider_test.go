@@ -0,0 +1,375 @@
+package reporting_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Test the YearRange function and the DateRange Contains method
+func TestYearRange(t *testing.T) {
+	dateRange := rpt.YearRange(2022, 2023)
+	assert.True(t, dateRange.Contains(time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC)))
+	assert.True(t, dateRange.Contains(time.Date(2023, time.December, 31, 23, 59, 59, 0, time.UTC)))
+	assert.False(t, dateRange.Contains(time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, dateRange.Contains(time.Date(2021, time.December, 31, 23, 59, 59, 0, time.UTC)))
+}
+
+// Test the fiscal year ranges, and the fiscal year of a time, named like the year they end in
+func TestFiscalYearRange(t *testing.T) {
+	dateRange := rpt.FiscalYearRange(2022, 2023, time.July)
+	assert.Equal(t, "2021-07-01..2023-06-30", dateRange.String())
+	assert.Equal(t, rpt.YearRange(2022, 2023), rpt.FiscalYearRange(2022, 2023, time.January))
+	assert.Equal(t, rpt.YearRange(2022, 2023), rpt.FiscalYearRange(2022, 2023, 0))
+
+	assert.Equal(t, 2022, rpt.FiscalYear(time.Date(2021, time.July, 1, 0, 0, 0, 0, time.UTC), time.July))
+	assert.Equal(t, 2022, rpt.FiscalYear(time.Date(2022, time.June, 30, 23, 59, 59, 0, time.UTC), time.July))
+	assert.Equal(t, 2023, rpt.FiscalYear(time.Date(2022, time.July, 1, 0, 0, 0, 0, time.UTC), time.July))
+	assert.Equal(t, 2022, rpt.FiscalYear(time.Date(2022, time.July, 1, 0, 0, 0, 0, time.UTC), time.January))
+}
+
+// Test the NewProviderCollector constructor
+func TestNewProviderCollector(t *testing.T) {
+	gitHub, err := rpt.NewGitHub(&MockGraphQLClient{})
+	assert.NoError(t, err)
+
+	_, err = rpt.NewProviderCollector(gitHub, "", 2022, 2023)
+	assert.Error(t, err)
+
+	collector, err := rpt.NewProviderCollector(gitHub, "user1", 2022, 2023)
+	assert.NoError(t, err)
+	assert.Equal(t, rpt.YearRange(2022, 2023), collector.Range)
+}
+
+// Test converting contributions to query results
+func TestQueryResultsFromContributions(t *testing.T) {
+	date := time.Date(2023, time.May, 1, 0, 0, 0, 0, time.UTC)
+	app := rpt.Repository{Name: "app", URL: "https://example.com/app"}
+	contributions := []rpt.Contribution{
+		{Kind: rpt.ContributionCommit, Repository: app, Date: date, Count: 3},
+		{Kind: rpt.ContributionCommit, Repository: app, Date: date, Count: 2},
+		{Kind: rpt.ContributionCommit, Date: date, Count: 4},
+		{Kind: rpt.ContributionIssue, Repository: rpt.Repository{Name: "lib"}, Date: date, Count: 1},
+		{Kind: rpt.ContributionRestricted, Date: date, Count: 6},
+		{Kind: rpt.ContributionPullRequest, Repository: app, Date: date.AddDate(1, 0, 0), Count: 1},
+	}
+
+	queryResults := rpt.QueryResultsFromContributions("user1", contributions)
+	assert.Len(t, queryResults, 2)
+
+	// Ensure contributions without a repository count toward the totals only
+	collection := queryResults["user1-2023"].User.ContributionsCollection
+	assert.Equal(t, "user1", string(queryResults["user1-2023"].User.Login))
+	assert.Equal(t, 9, int(collection.TotalCommitContributions))
+	assert.Equal(t, 1, int(collection.TotalIssueContributions))
+	assert.Equal(t, 6, int(collection.RestrictedContributionsCount))
+	assert.Len(t, collection.CommitContributionsByRepository, 1)
+	assert.Equal(t, 5, int(collection.CommitContributionsByRepository[0].Contributions.TotalCount))
+	assert.Equal(t, 2, collection.TotalRepositories())
+
+	collection = queryResults["user1-2024"].User.ContributionsCollection
+	assert.Equal(t, 1, int(collection.TotalPullRequestContributions))
+}
+
+// Test that the GitHub provider aggregates to the same totals as the Reporter
+func TestGitHubCollect(t *testing.T) {
+	fixtureData := make(map[string]rpt.QueryResult)
+	for _, year := range []string{"2022", "2023"} {
+		result, err := loadSingleFixture("multiple_years_deduplicated_"+year+".json", "user1", year)
+		assert.NoError(t, err)
+		fixtureData[year] = result
+	}
+	mockClient := &MockGraphQLClient{Responses: fixtureData}
+	mockClient.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	reporter, err := rpt.NewReporter(mockClient, "user1", 2022, 2023)
+	assert.NoError(t, err)
+	reporterResults, err := reporter.Collect(context.Background())
+	assert.NoError(t, err)
+	expected, err := reporter.Aggregate(context.Background(), reporterResults)
+	assert.NoError(t, err)
+
+	gitHub, err := rpt.NewGitHub(mockClient)
+	assert.NoError(t, err)
+	gitHub.YearsPerQuery = 1 // Query like the Reporter, a year at a time
+	collector, err := rpt.NewProviderCollector(gitHub, "user1", 2022, 2023)
+	assert.NoError(t, err)
+	providerResults, err := collector.Collect(context.Background())
+	assert.NoError(t, err)
+	actual, err := reporter.Aggregate(context.Background(), providerResults)
+	assert.NoError(t, err)
+
+	assert.NotZero(t, expected.TotalCommitContributions)
+	assert.Equal(t, expected.Totals(), actual.Totals())
+	assert.ElementsMatch(t, expected.Repositories, actual.Repositories)
+	assert.Equal(t, expected.ByUser, actual.ByUser)
+}
+
+// Test finding the API URLs of a GitHub Enterprise Server
+func TestGitHubEnterpriseURLs(t *testing.T) {
+	for _, baseURL := range []string{"https://ghe.example.com", "https://ghe.example.com/", "https://ghe.example.com/api/graphql"} {
+		graphQLURL, restURL := rpt.GitHubEnterpriseURLs(baseURL)
+		assert.Equal(t, "https://ghe.example.com/api/graphql", graphQLURL)
+		assert.Equal(t, "https://ghe.example.com/api/v3", restURL)
+	}
+}
+
+// Test querying the details of each repository from GitHub
+func TestGitHubCollectRepositoryDetails(t *testing.T) {
+	var requests []struct {
+		Query     string
+		Variables map[string]any
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Query     string
+			Variables map[string]any
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		requests = append(requests, request)
+		w.Write([]byte(`{"data": {"user": {"login": "user1", "contributionsCollection": {
+			"totalCommitContributions": 3,
+			"commitContributionsByRepository": [{
+				"repository": {"name": "app", "url": "https://github.com/o/app", "isFork": true, "stargazerCount": 42,
+					"description": "An app", "primaryLanguage": {"name": "Go"}},
+				"contributions": {"totalCount": 3}
+			}]}}}}`))
+	}))
+	defer server.Close()
+
+	gitHub, err := rpt.NewGitHub(githubv4.NewEnterpriseClient(server.URL, server.Client()))
+	require.NoError(t, err)
+	gitHub.RepositoryDetails = true
+	contributions, err := gitHub.Collect(context.Background(), "user1", rpt.YearRange(2023, 2023))
+	require.NoError(t, err)
+
+	require.Len(t, requests, 1)
+	assert.Contains(t, requests[0].Query, "$details:Boolean!")
+	assert.Contains(t, requests[0].Query, "stargazerCount @include(if: $details)")
+	assert.Equal(t, true, requests[0].Variables["details"])
+	assert.Contains(t, requests[0].Query, "rateLimit{cost,remaining,resetAt}")
+	assert.Contains(t, requests[0].Query, "isArchived,isDisabled,isFork")
+
+	require.Len(t, contributions, 1)
+	assert.Equal(t, rpt.Repository{Name: "app", URL: "https://github.com/o/app", IsFork: true, Stars: 42,
+		Language: "Go", Description: "An app"}, contributions[0].Repository)
+
+	// Ensure the details are kept through the query results
+	aggregatedResults, err := (&rpt.Reporter{}).Aggregate(context.Background(), rpt.QueryResultsFromContributions("user1", contributions))
+	require.NoError(t, err)
+	assert.Equal(t, contributions[0].Repository, aggregatedResults.Repositories[0].Repository)
+}
+
+// Test asking for several years in each query to GitHub
+func TestGitHubCollectBatched(t *testing.T) {
+	var requests []struct {
+		Query     string
+		Variables map[string]any
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Query     string
+			Variables map[string]any
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		requests = append(requests, request)
+
+		// Answer each aliased year with commits numbering the year, with no activity before 2020
+		var user = map[string]any{"login": "user1"}
+		for i := 0; request.Variables[fmt.Sprintf("from%d", i)] != nil; i++ {
+			year, _ := strconv.Atoi(request.Variables[fmt.Sprintf("from%d", i)].(string)[:4])
+			user[fmt.Sprintf("year%d", i)] = map[string]any{
+				"totalCommitContributions": year - 2000,
+				"hasActivityInThePast":     year > 2020,
+				"contributionCalendar":     map[string]any{"totalContributions": year - 1990},
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"user": user}})
+	}))
+	defer server.Close()
+
+	gitHub, err := rpt.NewGitHub(githubv4.NewEnterpriseClient(server.URL, server.Client()))
+	require.NoError(t, err)
+	gitHub.YearsPerQuery = 3
+	gitHub.StopAtInactiveYear = true
+	var queriedYears []int
+	gitHub.OnQuery = func(user string, years int, latency time.Duration) {
+		queriedYears = append(queriedYears, years)
+	}
+	dateRange := rpt.DateRange{From: time.Date(2018, time.January, 1, 0, 0, 0, 0, time.UTC),
+		To: time.Date(2023, time.July, 1, 0, 0, 0, 0, time.UTC)}
+	contributions, err := gitHub.Collect(context.Background(), "user1", dateRange)
+	require.NoError(t, err)
+	assert.Equal(t, []int{3, 3}, queriedYears)
+
+	// Ensure 2023 to 2021 come in one query, and the years after 2020 in the next are left out
+	require.Len(t, requests, 2)
+	assert.Contains(t, requests[0].Query, "year0: contributionsCollection(from: $from0, to: $to0)")
+	assert.Contains(t, requests[0].Query, "year2: contributionsCollection(from: $from2, to: $to2)")
+	assert.Equal(t, "2023-06-30T23:59:59Z", requests[0].Variables["to0"])
+	assert.Equal(t, "2021-01-01T00:00:00Z", requests[0].Variables["from2"])
+	assert.Equal(t, "2018-01-01T00:00:00Z", requests[1].Variables["from2"])
+
+	queryResults := rpt.QueryResultsFromContributions("user1", contributions)
+	assert.Len(t, queryResults, 4)
+	assert.Equal(t, 23, int(queryResults["user1-2023"].User.ContributionsCollection.TotalCommitContributions))
+	assert.Equal(t, 33, int(queryResults["user1-2023"].User.ContributionsCollection.ContributionCalendar.TotalContributions))
+	assert.Equal(t, 20, int(queryResults["user1-2020"].User.ContributionsCollection.TotalCommitContributions))
+	assert.NotContains(t, queryResults, "user1-2019")
+}
+
+// Test collecting fiscal years, queried and tallied from the month they start in
+func TestGitHubCollectFiscalYears(t *testing.T) {
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Query     string
+			Variables map[string]any
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+
+		// Answer each aliased year with one commit
+		var user = map[string]any{"login": "user1"}
+		for i := 0; request.Variables[fmt.Sprintf("from%d", i)] != nil; i++ {
+			ranges = append(ranges, request.Variables[fmt.Sprintf("from%d", i)].(string)[:10]+".."+
+				request.Variables[fmt.Sprintf("to%d", i)].(string)[:10])
+			user[fmt.Sprintf("year%d", i)] = map[string]any{"totalCommitContributions": 1}
+		}
+		json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"user": user}})
+	}))
+	defer server.Close()
+
+	gitHub, err := rpt.NewGitHub(githubv4.NewEnterpriseClient(server.URL, server.Client()))
+	require.NoError(t, err)
+	gitHub.FiscalYearStart = time.July
+	collector := &rpt.ProviderCollector{Provider: gitHub, User: "user1", FiscalYearStart: time.July,
+		Range: rpt.DateRange{From: time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC),
+			To: rpt.FiscalYearRange(2023, 2023, time.July).To}}
+	queryResults, err := collector.Collect(context.Background())
+	require.NoError(t, err)
+
+	// Ensure the first fiscal year is clipped to the range, and each is keyed like the year it ends in
+	assert.Equal(t, []string{"2022-07-01..2023-06-30", "2021-07-01..2022-06-30", "2021-03-01..2021-06-30"}, ranges)
+	assert.Len(t, queryResults, 3)
+	assert.Contains(t, queryResults, "user1-2021")
+	assert.Contains(t, queryResults, "user1-2023")
+
+	// Ensure the report labels the fiscal years
+	aggregatedResults, err := (&rpt.Reporter{FiscalYearStart: time.July}).Aggregate(context.Background(), queryResults)
+	require.NoError(t, err)
+	assert.Equal(t, int(time.July), aggregatedResults.FiscalYearStart)
+	assert.Equal(t, "FY2023", aggregatedResults.YearLabel(2023))
+	assert.Equal(t, "2023", rpt.AggregatedResults{}.YearLabel(2023))
+}
+
+// Test counting the repositories GitHub leaves out of the lists cut short at the limit
+func TestGitHubCollectTruncatedRepositories(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Query     string
+			Variables map[string]any
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		query = request.Query
+
+		// Answer with more repositories committed to than are listed
+		json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"user": map[string]any{
+			"login": "user1",
+			"contributionsCollection": map[string]any{
+				"totalCommitContributions":                150,
+				"totalRepositoriesWithContributedCommits": 120,
+				"totalRepositoriesWithContributedIssues":  1,
+				"totalIssueContributions":                 1,
+				"commitContributionsByRepository": []map[string]any{{
+					"repository":    map[string]any{"name": "app", "url": "https://github.com/owner/app"},
+					"contributions": map[string]any{"totalCount": 30},
+				}},
+				"issueContributionsByRepository": []map[string]any{{
+					"repository":    map[string]any{"name": "docs", "url": "https://github.com/owner/docs"},
+					"contributions": map[string]any{"totalCount": 1},
+				}},
+			},
+		}}})
+	}))
+	defer server.Close()
+
+	gitHub, err := rpt.NewGitHub(githubv4.NewEnterpriseClient(server.URL, server.Client()))
+	require.NoError(t, err)
+	collector := &rpt.ProviderCollector{Provider: gitHub, User: "user1", Range: rpt.YearRange(2023, 2023)}
+	queryResults, err := collector.Collect(context.Background())
+	require.NoError(t, err)
+
+	// Ensure the query asks for the most repositories, and the unlisted ones are still counted
+	assert.Contains(t, query, "commitContributionsByRepository(maxRepositories: 100)")
+	collection := queryResults["user1-2023"].User.ContributionsCollection
+	assert.Equal(t, 150, int(collection.TotalCommitContributions))
+	assert.Equal(t, 120, int(collection.TotalRepositoriesWithContributedCommits))
+	assert.True(t, collection.Truncated())
+	assert.Equal(t, 120, collection.TotalRepositories())
+
+	aggregatedResults, err := (&rpt.Reporter{}).Aggregate(context.Background(), queryResults)
+	require.NoError(t, err)
+	assert.Equal(t, 120, aggregatedResults.TotalRepositories)
+	assert.Equal(t, 120, aggregatedResults.ByYear[2023].TotalRepositories)
+	assert.Len(t, aggregatedResults.Repositories, 2)
+}
+
+// Test collecting the years after a gap in the user's activity, skipping those
+// before the account was created
+func TestGitHubCollectGap(t *testing.T) {
+	var years []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Variables map[string]any
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		year, _ := strconv.Atoi(request.Variables["from"].(string)[:4])
+		years = append(years, year)
+
+		// Answer with commits in 2023 and 2019, and none in the years between
+		var commits int
+		if year == 2023 || year == 2019 {
+			commits = year - 2000
+		}
+		json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"user": map[string]any{
+			"login":     "user1",
+			"createdAt": "2019-05-01T00:00:00Z",
+			"contributionsCollection": map[string]any{
+				"totalCommitContributions": commits,
+				"hasActivityInThePast":     year > 2019 && year != 2021,
+			},
+		}}})
+	}))
+	defer server.Close()
+
+	gitHub, err := rpt.NewGitHub(githubv4.NewEnterpriseClient(server.URL, server.Client()))
+	require.NoError(t, err)
+	gitHub.YearsPerQuery = 1
+
+	// Ensure every year since the account was created is collected, despite the gap
+	contributions, err := gitHub.Collect(context.Background(), "user1", rpt.YearRange(2015, 2023))
+	require.NoError(t, err)
+	assert.Equal(t, []int{2023, 2022, 2021, 2020, 2019}, years)
+	queryResults := rpt.QueryResultsFromContributions("user1", contributions)
+	assert.Equal(t, 19, int(queryResults["user1-2019"].User.ContributionsCollection.TotalCommitContributions))
+
+	// Ensure the collection stops at the gap when asked to
+	years = nil
+	gitHub.StopAtInactiveYear = true
+	contributions, err = gitHub.Collect(context.Background(), "user1", rpt.YearRange(2015, 2023))
+	require.NoError(t, err)
+	assert.Equal(t, []int{2023, 2022, 2021}, years)
+	assert.NotContains(t, rpt.QueryResultsFromContributions("user1", contributions), "user1-2019")
+}
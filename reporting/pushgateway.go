@@ -0,0 +1,65 @@
+package reporting
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// A Pushgateway pushes the metrics of a run to a Prometheus Pushgateway,
+// which suits cron-style runs that have no long-lived server to scrape
+type Pushgateway struct {
+	// The base URL of the Pushgateway, like http://localhost:9091
+	URL string
+	// The job label used to group the pushed metrics
+	Job string
+	// The HTTP client used to push the metrics
+	Client *http.Client
+}
+
+// Constructs a new Pushgateway object
+// The pushgatewayURL is the base URL of the Pushgateway service
+func NewPushgateway(pushgatewayURL string) (pushgateway Pushgateway, err error) {
+
+	if pushgatewayURL == "" {
+		err = fmt.Errorf("the pushgateway URL cannot be blank")
+		return Pushgateway{}, err
+	}
+
+	return Pushgateway{
+		URL:    strings.TrimSuffix(pushgatewayURL, "/"),
+		Job:    DefaultPushgatewayJob,
+		Client: http.DefaultClient,
+	}, err
+}
+
+// Push replaces the metrics in the job's group with the aggregated results
+func (p *Pushgateway) Push(results AggregatedResults) error {
+
+	var body bytes.Buffer
+	err := results.WriteMetrics(&body)
+	if err != nil {
+		return err
+	}
+
+	// A PUT replaces all metrics previously pushed for the job
+	endpoint := p.URL + "/metrics/job/" + url.PathEscape(p.Job)
+	request, err := http.NewRequest(http.MethodPut, endpoint, &body)
+	if err != nil {
+		return fmt.Errorf("failed to build the pushgateway request: %w", err)
+	}
+	request.Header.Set("Content-Type", "text/plain; version=0.0.4")
+
+	response, err := p.Client.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to push metrics: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode/100 != 2 {
+		return fmt.Errorf("failed to push metrics: pushgateway returned %s", response.Status)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package reporting_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the NewPushgateway constructor
+func TestNewPushgateway(t *testing.T) {
+	_, err := rpt.NewPushgateway("")
+	assert.Error(t, err)
+
+	pushgateway, err := rpt.NewPushgateway("http://localhost:9091/")
+	assert.NoError(t, err)
+	assert.Equal(t, "http://localhost:9091", pushgateway.URL)
+	assert.Equal(t, rpt.DefaultPushgatewayJob, pushgateway.Job)
+}
+
+// Test the Push method
+func TestPush(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		wantErr    bool
+	}{
+		{
+			name:       "accepted push",
+			statusCode: http.StatusOK,
+			wantErr:    false,
+		},
+		{
+			name:       "rejected push",
+			statusCode: http.StatusBadRequest,
+			wantErr:    true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var method, path, body string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				method = r.Method
+				path = r.URL.Path
+				b, _ := io.ReadAll(r.Body)
+				body = string(b)
+				w.WriteHeader(test.statusCode)
+			}))
+			defer server.Close()
+
+			pushgateway, err := rpt.NewPushgateway(server.URL)
+			assert.NoError(t, err)
+
+			results := rpt.AggregatedResults{TotalCommitContributions: 42}
+			err = pushgateway.Push(results)
+
+			if test.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, http.MethodPut, method)
+			assert.Equal(t, "/metrics/job/ghcontributions", path)
+			assert.Contains(t, body, "ghcontributions_total_commit_contributions 42\n")
+		})
+	}
+}
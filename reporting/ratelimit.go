@@ -0,0 +1,65 @@
+package reporting
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// The fewest GraphQL rate limit points left before the queries pause until the reset
+const DefaultMinRemaining = 100
+
+// A RateLimit is the state of the GitHub GraphQL rate limit after a query
+type RateLimit struct {
+	// The points the query cost
+	Cost githubv4.Int
+	// The points left until the reset
+	Remaining githubv4.Int
+	// When the points are replenished
+	ResetAt githubv4.DateTime
+}
+
+// A RateLimitPolicy pauses the queries of a token until the rate limit resets
+// when few points remain, instead of failing on the exhausted limit
+type RateLimitPolicy struct {
+	// Pause when fewer points than this remain. Zero never pauses.
+	MinRemaining int
+	// Called with the rate limit after each query, like for adding up the cost, when set
+	OnRateLimit func(user string, rateLimit RateLimit)
+}
+
+// DefaultRateLimitPolicy returns a policy pausing when fewer than
+// DefaultMinRemaining points remain
+func DefaultRateLimitPolicy() RateLimitPolicy {
+	return RateLimitPolicy{MinRemaining: DefaultMinRemaining}
+}
+
+// wait passes the rate limit after a user's query to OnRateLimit, then waits until
+// the reset if too few points remain. Responses without a rate limit are skipped.
+// Canceling the context stops the wait.
+func (p RateLimitPolicy) wait(ctx context.Context, user string, rateLimit RateLimit) error {
+
+	if rateLimit.ResetAt.IsZero() {
+		return nil
+	}
+	if p.OnRateLimit != nil {
+		p.OnRateLimit(user, rateLimit)
+	}
+	pause := time.Until(rateLimit.ResetAt.Time)
+	if int(rateLimit.Remaining) >= p.MinRemaining || pause <= 0 {
+		return nil
+	}
+
+	slog.Info("Pausing for the GitHub rate limit", "user", user, "until", rateLimit.ResetAt.Local().Format(time.TimeOnly),
+		"remaining", int(rateLimit.Remaining))
+	timer := time.NewTimer(pause)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,65 @@
+package reporting_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Test pausing the queries until the rate limit resets when few points remain
+func TestRateLimitPolicy(t *testing.T) {
+	queryResult := commitsByYear("user1", map[int]int{2023: 10})["user1-2023"]
+	queryResult.RateLimit = rpt.RateLimit{
+		Cost:      1,
+		Remaining: 20,
+		ResetAt:   githubv4.DateTime{Time: time.Now().Add(200 * time.Millisecond)},
+	}
+	mockClient := &MockGraphQLClient{Responses: map[string]rpt.QueryResult{"2023": queryResult}}
+	mockClient.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	var costs []int
+	rateLimitPolicy := rpt.RateLimitPolicy{
+		MinRemaining: 10,
+		OnRateLimit: func(user string, rateLimit rpt.RateLimit) {
+			assert.Equal(t, "user1", user)
+			costs = append(costs, int(rateLimit.Cost))
+		},
+	}
+	reporter, err := rpt.NewReporter(mockClient, "user1", 2023, 2023, rpt.WithRateLimitPolicy(rateLimitPolicy))
+	assert.NoError(t, err)
+
+	// Ensure enough points remaining doesn't pause
+	start := time.Now()
+	queryResults, err := reporter.Collect(context.Background())
+	assert.NoError(t, err)
+	assert.Contains(t, queryResults, "user1-2023")
+	assert.Less(t, time.Since(start), 150*time.Millisecond)
+	assert.Equal(t, []int{1}, costs)
+
+	// Ensure too few points remaining pauses until the reset
+	reporter.RateLimitPolicy.MinRemaining = 50
+	start = time.Now()
+	_, err = reporter.Collect(context.Background())
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+	assert.Equal(t, []int{1, 1}, costs)
+
+	// Ensure canceling the context stops the pause
+	queryResult.RateLimit.ResetAt = githubv4.DateTime{Time: time.Now().Add(time.Hour)}
+	mockClient.Responses["2023"] = queryResult
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = reporter.Collect(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// Ensure responses without a rate limit never pause
+	mockClient.Responses["2023"] = commitsByYear("user1", map[int]int{2023: 10})["user1-2023"]
+	_, err = reporter.Collect(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, costs, 3)
+}
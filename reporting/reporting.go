@@ -1,11 +1,17 @@
 package reporting
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/shurcooL/githubv4"
@@ -20,92 +26,378 @@ type GraphQLClient interface {
 
 // A QueryResult represents a Github GraphQL query result that returns select high level fields
 // The User.Login field tag ($login) is configurable with a variables map, as are the
-// User.ContributionCollection field tags ($from and $to).
+// User.ContributionCollection field tags ($from and $to). The rate limit after the
+// query and the account's creation time are left out of reports.
 type QueryResult struct {
 	User struct {
 		Login                   githubv4.String
-		ContributionsCollection struct {
-			HasAnyContributions                                githubv4.Boolean
-			HasActivityInThePast                               githubv4.Boolean
-			RestrictedContributionsCount                       githubv4.Int
-			TotalCommitContributions                           githubv4.Int
-			TotalIssueContributions                            githubv4.Int
-			TotalPullRequestContributions                      githubv4.Int
-			TotalPullRequestReviewContributions                githubv4.Int
-			TotalRepositoriesWithContributedIssues             githubv4.Int
-			TotalRepositoriesWithContributedCommits            githubv4.Int
-			TotalRepositoriesWithContributedPullRequests       githubv4.Int
-			TotalRepositoriesWithContributedPullRequestReviews githubv4.Int
-			CommitContributionsByRepository                    []struct {
-				Repository struct {
-					Name githubv4.String
-					URL  githubv4.String
-				}
-				Contributions struct {
-					TotalCount githubv4.Int
-				}
-			}
-			IssueContributionsByRepository []struct {
-				Repository struct {
-					Name githubv4.String
-					URL  githubv4.String
-				}
-				Contributions struct {
-					TotalCount githubv4.Int
-				}
-			}
-			PullRequestContributionsByRepository []struct {
-				Repository struct {
-					Name githubv4.String
-					URL  githubv4.String
-				}
-				Contributions struct {
-					TotalCount githubv4.Int
-				}
-			}
-			PullRequestReviewContributionsByRepository []struct {
-				Repository struct {
-					Name githubv4.String
-					URL  githubv4.String
-				}
-				Contributions struct {
-					TotalCount githubv4.Int
-				}
-			}
-		} `graphql:"contributionsCollection(from: $from, to: $to)"`
+		CreatedAt               githubv4.DateTime       `json:"-"`
+		ContributionsCollection ContributionsCollection `graphql:"contributionsCollection(from: $from, to: $to)"`
 	} `graphql:"user(login: $login)"`
+	RateLimit RateLimit `json:"-"`
+}
+
+// A ContributionsCollection holds a user's contribution counts over a date range
+type ContributionsCollection struct {
+	HasAnyContributions                                githubv4.Boolean
+	HasActivityInThePast                               githubv4.Boolean
+	RestrictedContributionsCount                       githubv4.Int
+	TotalCommitContributions                           githubv4.Int
+	TotalIssueContributions                            githubv4.Int
+	TotalPullRequestContributions                      githubv4.Int
+	TotalPullRequestReviewContributions                githubv4.Int
+	TotalRepositoriesWithContributedIssues             githubv4.Int
+	TotalRepositoriesWithContributedCommits            githubv4.Int
+	TotalRepositoriesWithContributedPullRequests       githubv4.Int
+	TotalRepositoriesWithContributedPullRequestReviews githubv4.Int
+	// GitHub lists at most MaxRepositories repositories of each kind, so these can
+	// leave out some of those the totals of repositories count
+	CommitContributionsByRepository            []RepositoryContribution `graphql:"commitContributionsByRepository(maxRepositories: 100)"`
+	IssueContributionsByRepository             []RepositoryContribution `graphql:"issueContributionsByRepository(maxRepositories: 100)"`
+	PullRequestContributionsByRepository       []RepositoryContribution `graphql:"pullRequestContributionsByRepository(maxRepositories: 100)"`
+	PullRequestReviewContributionsByRepository []RepositoryContribution `graphql:"pullRequestReviewContributionsByRepository(maxRepositories: 100)"`
+	// The total GitHub shows on the user's profile for the range
+	ContributionCalendar struct {
+		TotalContributions githubv4.Int
+	}
+}
+
+// A RepositoryContribution holds a repository and the count of
+// contributions of a single kind made to it
+type RepositoryContribution struct {
+	Repository struct {
+		Name       githubv4.String
+		URL        githubv4.String
+		IsArchived githubv4.Boolean
+		IsDisabled githubv4.Boolean
+		IsFork     githubv4.Boolean
+		// The details are only queried when the details variable is true
+		StargazerCount  githubv4.Int    `graphql:"stargazerCount @include(if: $details)"`
+		Description     githubv4.String `graphql:"description @include(if: $details)"`
+		PrimaryLanguage struct {
+			Name githubv4.String
+		} `graphql:"primaryLanguage @include(if: $details)"`
+	}
+	Contributions struct {
+		TotalCount githubv4.Int
+	}
+}
+
+// repository returns the repository contributed to
+func (r RepositoryContribution) repository() Repository {
+	return Repository{
+		Name:        string(r.Repository.Name),
+		URL:         string(r.Repository.URL),
+		IsArchived:  bool(r.Repository.IsArchived),
+		IsDisabled:  bool(r.Repository.IsDisabled),
+		IsFork:      bool(r.Repository.IsFork),
+		Stars:       int(r.Repository.StargazerCount),
+		Language:    string(r.Repository.PrimaryLanguage.Name),
+		Description: string(r.Repository.Description),
+	}
+}
+
+// key identifies the repository contributed to, like Repository.key
+func (r RepositoryContribution) key() string {
+	if r.Repository.URL != "" {
+		return string(r.Repository.URL)
+	}
+	return string(r.Repository.Name)
+}
+
+// setRepository sets the repository contributed to
+func (r *RepositoryContribution) setRepository(repository Repository) {
+	r.Repository.Name = githubv4.String(repository.Name)
+	r.Repository.URL = githubv4.String(repository.URL)
+	r.Repository.IsArchived = githubv4.Boolean(repository.IsArchived)
+	r.Repository.IsDisabled = githubv4.Boolean(repository.IsDisabled)
+	r.Repository.IsFork = githubv4.Boolean(repository.IsFork)
+	r.Repository.StargazerCount = githubv4.Int(repository.Stars)
+	r.Repository.PrimaryLanguage.Name = githubv4.String(repository.Language)
+	r.Repository.Description = githubv4.String(repository.Description)
+}
+
+// RepositoryContributions returns the commit, issue, pull request, and pull request
+// review contributions by repository as a single list
+func (c ContributionsCollection) RepositoryContributions() []RepositoryContribution {
+	return slices.Concat(
+		c.CommitContributionsByRepository,
+		c.IssueContributionsByRepository,
+		c.PullRequestContributionsByRepository,
+		c.PullRequestReviewContributionsByRepository,
+	)
+}
+
+// TotalRepositories returns the count of unique repositories contributed to, or the
+// most repositories of a single kind when more of them aren't listed
+func (c ContributionsCollection) TotalRepositories() int {
+	var uniqueRepositories = make(map[string]bool)
+	for _, repository := range c.RepositoryContributions() {
+		uniqueRepositories[repository.key()] = true
+	}
+	return max(len(uniqueRepositories), c.leastRepositories())
+}
+
+// leastRepositories returns the most repositories contributed to with a single
+// kind of contribution, the fewest repositories the collection can count even
+// when the lists by repository are cut short at MaxRepositories
+func (c ContributionsCollection) leastRepositories() int {
+	return int(max(c.TotalRepositoriesWithContributedCommits, c.TotalRepositoriesWithContributedIssues,
+		c.TotalRepositoriesWithContributedPullRequests, c.TotalRepositoriesWithContributedPullRequestReviews))
+}
+
+// Truncated reports whether the lists by repository leave out some of the
+// repositories contributed to, as GitHub lists at most MaxRepositories of each kind
+func (c ContributionsCollection) Truncated() bool {
+	return int(c.TotalRepositoriesWithContributedCommits) > len(c.CommitContributionsByRepository) ||
+		int(c.TotalRepositoriesWithContributedIssues) > len(c.IssueContributionsByRepository) ||
+		int(c.TotalRepositoriesWithContributedPullRequests) > len(c.PullRequestContributionsByRepository) ||
+		int(c.TotalRepositoriesWithContributedPullRequestReviews) > len(c.PullRequestReviewContributionsByRepository)
 }
 
-// Repository holds a Github repository name and its URL
+// Repository holds a Github repository name and its URL, and whether the
+// repository is read-only or disabled now, or a fork of another
 type Repository struct {
-	Name string `json:"name"`
-	URL  string `json:"url"`
+	Name       string `json:"name"`
+	URL        string `json:"url"`
+	IsArchived bool   `json:"isArchived,omitempty"`
+	IsDisabled bool   `json:"isDisabled,omitempty"`
+	IsFork     bool   `json:"isFork,omitempty"`
+	// The details, when the provider was asked for them
+	Stars       int    `json:"stars,omitempty"`
+	Language    string `json:"language,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// key identifies the repository across users by its URL, as repositories of
+// different owners can share a name, or by its name when it has no URL
+func (r Repository) key() string {
+	if r.URL != "" {
+		return r.URL
+	}
+	return r.Name
+}
+
+// RepositoryTotals holds a repository with the contributions of each kind to it
+type RepositoryTotals struct {
+	Repository
+	Commits            int `json:"commits"`
+	Issues             int `json:"issues"`
+	PullRequests       int `json:"pullRequests"`
+	PullRequestReviews int `json:"pullRequestReviews"`
+	// The contributions of every kind
+	Contributions int `json:"contributions"`
 }
 
 // AggregatedResults stores the aggregated results in three fields: totalCommitContributions,
 // totalRepositories, and totalOtherContributions
 type AggregatedResults struct {
-	Timestamp                int          `json:"timestamp"`
-	TotalCommitContributions int          `json:"totalCommitContributions"`
-	TotalRepositories        int          `json:"totalRepositories"`
-	TotalOtherContributions  int          `json:"totalOtherContributions"`
-	Repositories             []Repository `json:"repositories"`
+	Timestamp                int `json:"timestamp"`
+	TotalCommitContributions int `json:"totalCommitContributions"`
+	TotalRepositories        int `json:"totalRepositories"`
+	TotalOtherContributions  int `json:"totalOtherContributions"`
+	// The issues, pull requests, and pull request reviews the other contributions
+	// add up, without the restricted contributions
+	TotalIssueContributions             int `json:"totalIssueContributions"`
+	TotalPullRequestContributions       int `json:"totalPullRequestContributions"`
+	TotalPullRequestReviewContributions int `json:"totalPullRequestReviewContributions"`
+	// The contribution calendar totals, the figures GitHub profiles show, added up
+	TotalContributions int `json:"totalContributions,omitempty"`
+	// The anonymized contributions to private repositories, counted among the other
+	// contributions when the Reporter includes them
+	TotalRestrictedContributions int `json:"totalRestrictedContributions,omitempty"`
+	// Whether the restricted contributions are counted among the other contributions
+	IncludeRestricted bool `json:"includeRestricted,omitempty"`
+	// Each repository contributed to, with the most contributions first
+	Repositories []RepositoryTotals `json:"repositories"`
+	// The contributions to the repositories in each primary language, when the
+	// repositories have their details
+	Languages []LanguageTotals `json:"languages,omitempty"`
+	// The totals for each user across all years
+	ByUser map[string]Totals `json:"byUser,omitempty"`
+	// The totals for each year across all users
+	ByYear map[int]Totals `json:"byYear,omitempty"`
+	// The month the years start in when they're fiscal years, each named like
+	// the calendar year it ends in
+	FiscalYearStart int `json:"fiscalYearStart,omitempty"`
+	// The totals for each quarter or month across all users, keyed like 2023-Q2
+	// or 2023-06, when the report has a finer granularity than years
+	ByPeriod map[string]Totals `json:"byPeriod,omitempty"`
+	// The granularity of the periods, like quarter or month
+	Granularity string `json:"granularity,omitempty"`
+	// The repositories the contributions were aggregated from, when filtered
+	RepositoryFilter *RepositoryFilter `json:"repositoryFilter,omitempty"`
+	// The repositories counted from the lists by repository and from GitHub's
+	// totals, when counted from the totals or when the lists leave some out
+	RepositoryCounts *RepositoryCounts `json:"repositoryCounts,omitempty"`
+	// The profile of each user, from the providers that have one
+	Profiles map[string]Profile `json:"profiles,omitempty"`
+	// The repositories more than one user contributed to
+	SharedRepositories []SharedRepository `json:"sharedRepositories,omitempty"`
+	// The other contributors to the most contributed repositories
+	Collaborators []Collaborator `json:"collaborators,omitempty"`
+	// The year over year changes in each metric
+	Analysis *Analysis `json:"analysis,omitempty"`
+	// The round numbers of contributions each user reached
+	Milestones []Milestone `json:"milestones,omitempty"`
+	// The hours of the day the sampled commits were made in
+	Activity *HourlyActivity `json:"activity,omitempty"`
+	// The projected contributions for the current year
+	Forecast *Forecast `json:"forecast,omitempty"`
+	// The streaks of days with contributions and the busiest day
+	Streaks *Streaks `json:"streaks,omitempty"`
+	// The progress toward each goal in its current period
+	Goals []GoalProgress `json:"goals,omitempty"`
+	// The pull requests grouped by the issue tracker projects they reference
+	TicketProjects map[string]TicketProject `json:"ticketProjects,omitempty"`
+	// The fields added by a report script
+	Custom map[string]any `json:"custom,omitempty"`
+	// The collections that failed, with the user-years to retry
+	Errors []CollectionError `json:"errors,omitempty"`
+	// The requests, rate limit, and time the run took
+	RunStats *RunStats `json:"runStats,omitempty"`
+}
+
+// Totals returns the headline metrics of the aggregated results
+func (a AggregatedResults) Totals() Totals {
+	return Totals{
+		TotalCommitContributions: a.TotalCommitContributions,
+		TotalRepositories:        a.TotalRepositories,
+		TotalOtherContributions:  a.TotalOtherContributions,
+		TotalContributions:       a.TotalContributions,
+	}
+}
+
+// YearLabel names a year of the results, like FY2022 for a fiscal year
+func (a AggregatedResults) YearLabel(year int) string {
+	if a.FiscalYearStart > int(time.January) {
+		return "FY" + strconv.Itoa(year)
+	}
+	return strconv.Itoa(year)
+}
+
+// Totals holds the three headline metrics for a subset of the query results,
+// and the contribution calendar total when the providers report one
+type Totals struct {
+	TotalCommitContributions int `json:"totalCommitContributions"`
+	TotalRepositories        int `json:"totalRepositories"`
+	TotalOtherContributions  int `json:"totalOtherContributions"`
+	TotalContributions       int `json:"totalContributions,omitempty"`
+}
+
+// Sub returns the change in each metric from the other totals to these totals
+func (t Totals) Sub(other Totals) Totals {
+	return Totals{
+		TotalCommitContributions: t.TotalCommitContributions - other.TotalCommitContributions,
+		TotalRepositories:        t.TotalRepositories - other.TotalRepositories,
+		TotalOtherContributions:  t.TotalOtherContributions - other.TotalOtherContributions,
+		TotalContributions:       t.TotalContributions - other.TotalContributions,
+	}
 }
 
 // Represents a Github username and its associated API token string
 type Credential struct {
 	Username string `json:"username"`
 	Token    string `json:"token"`
+	// The provider to collect from, github (the default), gitlab, bitbucket,
+	// azuredevops, gerrit, or git for local clones
+	Provider string `json:"provider,omitempty"`
+	// The base URL of a self-managed provider instance, like a GitHub Enterprise
+	// Server, a Gerrit server, or an Azure DevOps organization
+	URL string `json:"url,omitempty"`
+	// The email addresses commits are authored with, for local clones
+	Emails []string `json:"emails,omitempty"`
+	// The directories searched for local clones
+	Directories []string `json:"directories,omitempty"`
+	// The ID of a GitHub App authenticating in place of the token, with its
+	// installation ID and the path to, or contents of, its PEM private key
+	AppID          int64  `json:"appId,omitempty"`
+	InstallationID int64  `json:"installationId,omitempty"`
+	PrivateKey     string `json:"privateKey,omitempty"`
+}
+
+// Host returns the host of the credential's self-managed instance, like
+// ghe.example.com, or blank for the provider's own, like github.com
+func (c Credential) Host() string {
+	instanceURL, err := url.Parse(c.URL)
+	if err != nil {
+		return ""
+	}
+	host := strings.ToLower(instanceURL.Host)
+	if host == "github.com" || host == "api.github.com" {
+		return ""
+	}
+	return host
+}
+
+// Source names where the credential's contributions come from, its provider
+// with the host of a self-managed instance, like github@ghe.example.com, so the
+// same login on different instances is told apart
+func (c Credential) Source() string {
+	source := cmp.Or(c.Provider, ProviderGitHub)
+	if host := c.Host(); host != "" {
+		source += "@" + host
+	}
+	return source
 }
 
 // Represents a list of credential objects
 type Credentials []Credential
 
+// Usernames returns the comma separated usernames of the credentials
+func (c Credentials) Usernames() string {
+	var usernames []string
+	for _, credential := range c {
+		usernames = append(usernames, credential.Username)
+	}
+	return strings.Join(usernames, ",")
+}
+
+// Sources lists the distinct sources of each user's credentials, sorted
+func (c Credentials) Sources() map[string][]string {
+	var sources = make(map[string][]string)
+	for _, credential := range c {
+		sources[credential.Username] = append(sources[credential.Username], credential.Source())
+	}
+	for user := range sources {
+		sources[user] = slices.Compact(slices.Sorted(slices.Values(sources[user])))
+	}
+	return sources
+}
+
+// A credentialVariable matches a ${NAME} reference to an environment variable
+var credentialVariable = regexp.MustCompile(`\$\{(\w+)\}`)
+
+// ExpandEnv replaces each ${NAME} in the usernames, tokens, URLs, and private
+// keys with the value of the environment variable, so tokens needn't be kept on
+// disk. A variable that isn't set is an error, rather than a blank token.
+func (c Credentials) ExpandEnv() error {
+	for i := range c {
+		for _, field := range []*string{&c[i].Username, &c[i].Token, &c[i].URL, &c[i].PrivateKey} {
+			var missing []string
+			*field = credentialVariable.ReplaceAllStringFunc(*field, func(reference string) string {
+				name := credentialVariable.FindStringSubmatch(reference)[1]
+				value, found := os.LookupEnv(name)
+				if !found {
+					missing = append(missing, name)
+				}
+				return value
+			})
+			if len(missing) > 0 {
+				return fmt.Errorf("the %s variable in the credential of %s is not set", missing[0], c[i].Username)
+			}
+		}
+	}
+	return nil
+}
+
 // A Reporter collects high level statistics for one or more github
 // usernames, aggregates the results, and reports the results
 // as three simple metrics: “totalCodeCommits“, across “totalRepositories“, and
 // “totalOtherContributions“ (an aggregate of total TotalIssueContributions,
-// “totalPullRequestContributions“, and “totalPullRequestReviewContributions“)
+// “totalPullRequestContributions“, and “totalPullRequestReviewContributions“).
+// A Reporter keeps no results between calls, as Collect returns them, so it's
+// safe for concurrent use and for several independent runs in one process.
 type Reporter struct {
 	// A client that implements the GraphQLClient interface like
 	// an authenticated Github Client using an OAuth token
@@ -116,6 +408,41 @@ type Reporter struct {
 	LastYear int
 	// The first year to report statistics (defaults to 2000)
 	FirstYear int
+	// How failed queries are retried (defaults to DefaultRetries retries)
+	RetryPolicy RetryPolicy
+	// When queries pause for the rate limit (defaults to DefaultMinRemaining points)
+	RateLimitPolicy RateLimitPolicy
+	// Whether to stop at the first year without any earlier activity, skipping
+	// any older years after a gap in the user's activity
+	StopAtInactiveYear bool
+	// Whether to count the anonymized contributions to private repositories among
+	// the other contributions, like a profile showing private contributions
+	IncludeRestricted bool
+	// The month the years start in, for fiscal years named like the calendar
+	// year they end in, or January for calendar years
+	FiscalYearStart time.Month
+	// How the repositories are counted, RepositoryCountListed (the default) or
+	// RepositoryCountTotals
+	RepositoryCount string
+	// The repositories whose contributions are aggregated, every one by default
+	Filter RepositoryFilter
+}
+
+// A ReporterOption changes a Reporter as it's constructed
+type ReporterOption func(*Reporter)
+
+// WithRateLimitPolicy sets when the Reporter pauses for the rate limit
+func WithRateLimitPolicy(rateLimitPolicy RateLimitPolicy) ReporterOption {
+	return func(r *Reporter) {
+		r.RateLimitPolicy = rateLimitPolicy
+	}
+}
+
+// WithRetryPolicy sets how the Reporter retries failed queries
+func WithRetryPolicy(retryPolicy RetryPolicy) ReporterOption {
+	return func(r *Reporter) {
+		r.RetryPolicy = retryPolicy
+	}
 }
 
 // Constructs a new Reporter object
@@ -123,13 +450,35 @@ type Reporter struct {
 // The user is a github username string
 // The firstYear is the first year in the sequence to report
 // The lastYear is the last year in the sequence to report
-func NewReporter(client GraphQLClient, user string, firstYear int, lastYear int) (reporter Reporter, err error) {
+// The options change the defaults, like WithRetryPolicy
+func NewReporter(client GraphQLClient, user string, firstYear int, lastYear int,
+	options ...ReporterOption) (reporter Reporter, err error) {
 
 	if user == "" {
 		err = fmt.Errorf("user cannot be blank in constructing a query")
 		return Reporter{}, err
 	}
 
+	firstYear, lastYear = reportingYears(firstYear, lastYear)
+
+	reporter = Reporter{
+		Client:          client,
+		User:            user,
+		LastYear:        lastYear,
+		FirstYear:       firstYear,
+		RetryPolicy:     DefaultRetryPolicy(DefaultRetries),
+		RateLimitPolicy: DefaultRateLimitPolicy(),
+	}
+	for _, option := range options {
+		option(&reporter)
+	}
+	return reporter, err
+}
+
+// reportingYears validates the first and last years to report, replacing
+// years out of range with the defaults
+func reportingYears(firstYear int, lastYear int) (int, int) {
+
 	// Start with the current thisYear in UTC
 	thisYear := time.Now().UTC().Year()
 
@@ -140,57 +489,49 @@ func NewReporter(client GraphQLClient, user string, firstYear int, lastYear int)
 
 	if lastYear > thisYear {
 		lastYear = thisYear
-		log.Printf("the last reporting year can't be in the future. Using %d", thisYear)
+		slog.Warn("The last reporting year can't be in the future", "using", thisYear)
 	}
 
 	if lastYear == 0 || lastYear < firstYear {
 		lastYear = thisYear
-		log.Printf("the last reporting year can't be earlier than the first year. Using %d", thisYear)
-
+		slog.Warn("The last reporting year can't be earlier than the first year", "using", thisYear)
 	}
-
-	return Reporter{
-		Client:    client,
-		User:      user,
-		LastYear:  lastYear,
-		FirstYear: firstYear,
-	}, err
+	return firstYear, lastYear
 }
 
-// Collects Github contribution statistics via the GraphQL service
-// Returns the results as map of user-year strings to Query objects, and a nil error on success
-func (r *Reporter) Collect() (map[string]QueryResult, error) {
+// Collects Github contribution statistics via the GraphQL service, newest year first
+// Years before the user's account was created are skipped.
+// Returns the results as map of user-year strings to Query objects, and a nil error on success.
+// A failed query returns the years collected so far, with an error naming the user and year,
+// leaving the caller to abort, retry, or skip. Canceling the context stops the collection the same way.
+func (r *Reporter) Collect(ctx context.Context) (map[string]QueryResult, error) {
 
 	var queryResults = make(map[string]QueryResult)
 
-	log.Printf("fetching repository statistics...")
+	slog.Debug("Fetching repository statistics", "user", r.User)
 
 	// run the queries
 	for targetYear := r.LastYear; targetYear >= r.FirstYear; targetYear-- {
-		var queryResult = QueryResult{}
+		yearRange := FiscalYearRange(targetYear, targetYear, r.FiscalYearStart)
+		from := yearRange.From               // {year}-01-01T00:00:00
+		to := yearRange.To.Add(-time.Second) // {year}-12-31T11:59:59
 
-		from := time.Date(targetYear, time.January, 1, 0, 0, 0, 0, time.UTC) // {year}-01-01T00:00:00
-		to := from.AddDate(1, 0, 0).Add(-time.Second)                        // {year}-12-31T11:59:59
-
-		// Build a map of variable values
-		var variables = map[string]interface{}{
-			"login": githubv4.String(r.User),
-			"from":  githubv4.DateTime{Time: from},
-			"to":    githubv4.DateTime{Time: to},
-		}
-
-		err := r.Client.Query(context.Background(), &queryResult, variables)
+		queryResult, err := queryContributions(ctx, r.Client, r.RetryPolicy, r.RateLimitPolicy, r.User, from, to, false)
 		if err != nil {
-			return queryResults, fmt.Errorf("failed to query github: %w", err)
+			return queryResults, err
 		}
 		if githubv4.String(queryResult.User.Login) != "" {
 			userYear := r.User + "-" + strconv.Itoa(targetYear)
-			log.Println(userYear)
+			slog.Debug("Collected", "user", r.User, "year", targetYear)
 			queryResults[userYear] = queryResult // Store a copy of the user-year results
 		}
-		// Stop if no prior activity exists
+		// Stop before the account existed, or if asked to when no prior activity exists
+		createdAt := queryResult.User.CreatedAt.Time
+		if !createdAt.IsZero() && !createdAt.Before(from) {
+			break
+		}
 		hasActivityInThePast := queryResult.User.ContributionsCollection.HasActivityInThePast
-		if !hasActivityInThePast {
+		if r.StopAtInactiveYear && !bool(hasActivityInThePast) {
 			break
 		}
 	}
@@ -198,9 +539,9 @@ func (r *Reporter) Collect() (map[string]QueryResult, error) {
 }
 
 // Reports the final results
-func (r *Reporter) Report(queryResults map[string]QueryResult) (aggregatedResultsJSON string, err error) {
+func (r *Reporter) Report(ctx context.Context, queryResults map[string]QueryResult) (aggregatedResultsJSON string, err error) {
 
-	aggregatedResults, err := r.Aggregate(queryResults)
+	aggregatedResults, err := r.Aggregate(ctx, queryResults)
 	if err != nil {
 		return aggregatedResultsJSON, err
 	}
@@ -216,74 +557,193 @@ func (r *Reporter) Report(queryResults map[string]QueryResult) (aggregatedResult
 // Aggregates the results of each user over each year into:
 //   - totalCommitContributions: The count of all commits across all users in the results.
 //   - totalRepositories: The count of unique list of repository names committed to and contributed to
-//     in other ways (issues, pull requests, and pull request reviews).
+//     in other ways (issues, pull requests, and pull request reviews), or at least the repositories
+//     of a single kind when the lists are cut short at MaxRepositories.
 //   - totalOtherContributions: The count of all other contributions across all users, including
 //     all issues, pull requests, and pull request reviews.
-func (r *Reporter) Aggregate(queryResults map[string]QueryResult) (aggregatedResults AggregatedResults, err error) {
-
-	aggregatedResults = AggregatedResults{}
-	// For counting the contributions
-	var contributionsByRepo = make(map[string]int)
-	// For listing repo URLs by repo name
-	var uniqueRepositories = make(map[string]string)
+//   - totalIssueContributions, totalPullRequestContributions, and totalPullRequestReviewContributions:
+//     The counts of each kind of the other contributions across all users.
+//
+// Only the contributions to the repositories the Reporter's filter picks are aggregated.
+// The aggregation doesn't start if the context is already canceled.
+func (r *Reporter) Aggregate(ctx context.Context, queryResults map[string]QueryResult) (aggregatedResults AggregatedResults, err error) {
+
+	if err = ctx.Err(); err != nil {
+		return AggregatedResults{}, fmt.Errorf("failed to aggregate the results: %w", err)
+	}
+	aggregatedResults = AggregatedResults{IncludeRestricted: r.IncludeRestricted}
+	if !r.Filter.IsZero() {
+		queryResults = FilterQueryResults(queryResults, r.Filter)
+		filter := r.Filter
+		aggregatedResults.RepositoryFilter = &filter
+	}
+	// For counting the contributions to each repository by its key
+	var uniqueRepositories = make(map[string]*RepositoryTotals)
+	leastRepositories := 0
 
-	for userYear, queryResult := range queryResults {
-		log.Println(userYear)
+	for _, queryResult := range queryResults {
 		// Aggregate total commits
 		aggregatedResults.TotalCommitContributions +=
 			int(queryResult.User.ContributionsCollection.TotalCommitContributions)
-		// Aggregate other contributions
+		// Aggregate other contributions, and each kind of them
 		aggregatedResults.TotalOtherContributions +=
 			(int(queryResult.User.ContributionsCollection.TotalIssueContributions) +
 				int(queryResult.User.ContributionsCollection.TotalPullRequestContributions) +
 				int(queryResult.User.ContributionsCollection.TotalPullRequestReviewContributions))
-		// Aggregate total repositories
-		for _, repository := range queryResult.User.ContributionsCollection.CommitContributionsByRepository {
-			contributionsByRepo[string(repository.Repository.Name)] = contributionsByRepo[string(repository.Repository.Name)] + 1
-			uniqueRepositories[string(repository.Repository.Name)] = string(repository.Repository.URL)
+		aggregatedResults.TotalIssueContributions +=
+			int(queryResult.User.ContributionsCollection.TotalIssueContributions)
+		aggregatedResults.TotalPullRequestContributions +=
+			int(queryResult.User.ContributionsCollection.TotalPullRequestContributions)
+		aggregatedResults.TotalPullRequestReviewContributions +=
+			int(queryResult.User.ContributionsCollection.TotalPullRequestReviewContributions)
+		// Aggregate the contribution calendar totals
+		aggregatedResults.TotalContributions +=
+			int(queryResult.User.ContributionsCollection.ContributionCalendar.TotalContributions)
+		// Aggregate restricted contributions, when asked to
+		if r.IncludeRestricted {
+			restricted := int(queryResult.User.ContributionsCollection.RestrictedContributionsCount)
+			aggregatedResults.TotalRestrictedContributions += restricted
+			aggregatedResults.TotalOtherContributions += restricted
 		}
-		for _, repository := range queryResult.User.ContributionsCollection.IssueContributionsByRepository {
-			contributionsByRepo[string(repository.Repository.Name)] = contributionsByRepo[string(repository.Repository.Name)] + 1
-			uniqueRepositories[string(repository.Repository.Name)] = string(repository.Repository.URL)
-		}
-		for _, repository := range queryResult.User.ContributionsCollection.PullRequestContributionsByRepository {
-			contributionsByRepo[string(repository.Repository.Name)] = contributionsByRepo[string(repository.Repository.Name)] + 1
-			uniqueRepositories[string(repository.Repository.Name)] = string(repository.Repository.URL)
-		}
-		for _, repository := range queryResult.User.ContributionsCollection.PullRequestReviewContributionsByRepository {
-			contributionsByRepo[string(repository.Repository.Name)] = contributionsByRepo[string(repository.Repository.Name)] + 1
-			uniqueRepositories[string(repository.Repository.Name)] = string(repository.Repository.URL)
+		// Aggregate the contributions of each kind to each repository
+		collection := queryResult.User.ContributionsCollection
+		leastRepositories = max(leastRepositories, collection.leastRepositories())
+		for _, kind := range []struct {
+			contributions []RepositoryContribution
+			count         func(totals *RepositoryTotals) *int
+		}{
+			{collection.CommitContributionsByRepository, func(totals *RepositoryTotals) *int { return &totals.Commits }},
+			{collection.IssueContributionsByRepository, func(totals *RepositoryTotals) *int { return &totals.Issues }},
+			{collection.PullRequestContributionsByRepository, func(totals *RepositoryTotals) *int { return &totals.PullRequests }},
+			{collection.PullRequestReviewContributionsByRepository, func(totals *RepositoryTotals) *int { return &totals.PullRequestReviews }},
+		} {
+			for _, repository := range kind.contributions {
+				key := repository.key()
+				if uniqueRepositories[key] == nil {
+					uniqueRepositories[key] = &RepositoryTotals{Repository: repository.repository()}
+				}
+				*kind.count(uniqueRepositories[key]) += int(repository.Contributions.TotalCount)
+				uniqueRepositories[key].Contributions += int(repository.Contributions.TotalCount)
+			}
 		}
 	}
-	aggregatedResults.TotalRepositories = len(contributionsByRepo)
+	// Count the repositories left out of cut short lists, at least those of one result,
+	// or from the totals of repositories when asked to, comparing both when they diverge
+	aggregatedResults.TotalRepositories = max(len(uniqueRepositories), leastRepositories)
+	repositoryCounts := CountRepositories(queryResults)
+	repositoryCounts.Counted = cmp.Or(r.RepositoryCount, RepositoryCountListed)
+	if r.RepositoryCount == RepositoryCountTotals {
+		aggregatedResults.TotalRepositories = repositoryCounts.Totals
+	}
+	if r.RepositoryCount == RepositoryCountTotals || len(repositoryCounts.Diverged) > 0 {
+		aggregatedResults.RepositoryCounts = &repositoryCounts
+	}
 	aggregatedResults.Timestamp = int(time.Now().Unix())
+	if r.FiscalYearStart > time.January {
+		aggregatedResults.FiscalYearStart = int(r.FiscalYearStart)
+	}
+
+	// List the repositories with the most contributions first
+	aggregatedResults.Repositories = make([]RepositoryTotals, 0, len(uniqueRepositories))
+	for _, repository := range uniqueRepositories {
+		aggregatedResults.Repositories = append(aggregatedResults.Repositories, *repository)
+	}
+	slices.SortFunc(aggregatedResults.Repositories, func(a, b RepositoryTotals) int {
+		return cmp.Or(cmp.Compare(b.Contributions, a.Contributions), cmp.Compare(a.Name, b.Name), cmp.Compare(a.URL, b.URL))
+	})
+	aggregatedResults.Languages = Languages(aggregatedResults.Repositories)
+
+	// Summarize each user across all years
+	aggregatedResults.ByUser = make(map[string]Totals)
+	for user, userQueryResults := range groupQueryResults(queryResults, func(user string, year int) string {
+		return user
+	}) {
+		aggregatedResults.ByUser[user] = r.totals(userQueryResults)
+	}
+
+	// Summarize each year across all users
+	aggregatedResults.ByYear = make(map[int]Totals)
+	for key, yearQueryResults := range groupQueryResults(queryResults, func(user string, year int) string {
+		return strconv.Itoa(year)
+	}) {
+		year, _ := strconv.Atoi(key)
+		aggregatedResults.ByYear[year] = r.totals(yearQueryResults)
+	}
 
-	// A slice of repositories to be added as a list to the results
-	repos := make([]Repository, 0)
-	for key, val := range uniqueRepositories {
-		var repo = Repository{}
-		repo.Name = key
-		repo.URL = val
-		repos = append(repos, repo)
+	// Find the repositories the users worked on together
+	if len(aggregatedResults.ByUser) > 1 {
+		aggregatedResults.SharedRepositories = SharedRepositories(queryResults)
 	}
 
-	aggregatedResults.Repositories = repos
+	// Analyze the changes from year to year
+	aggregatedResults.Analysis = r.Analyze(queryResults)
+	aggregatedResults.Milestones = FindMilestones(queryResults)
 	return
 }
 
-// Poll periodically queries the Github API (TODO)
-func Poll() {
-	// Periodically poll and cache github statistics
-	ticker := time.NewTicker(time.Minute * PollingIntervalInMinutes)
-	done := make(chan bool)
+// sumTotals sums the contributions in the query results, counting each repository once,
+// and the restricted contributions among the other contributions when included
+func sumTotals(queryResults map[string]QueryResult, includeRestricted bool) (totals Totals) {
+	var uniqueRepositories = make(map[string]bool)
+	leastRepositories := 0
+	for _, queryResult := range queryResults {
+		collection := queryResult.User.ContributionsCollection
+		totals.TotalCommitContributions += int(collection.TotalCommitContributions)
+		totals.TotalOtherContributions += int(collection.TotalIssueContributions) +
+			int(collection.TotalPullRequestContributions) +
+			int(collection.TotalPullRequestReviewContributions)
+		if includeRestricted {
+			totals.TotalOtherContributions += int(collection.RestrictedContributionsCount)
+		}
+		totals.TotalContributions += int(collection.ContributionCalendar.TotalContributions)
+		for _, repository := range collection.RepositoryContributions() {
+			uniqueRepositories[repository.key()] = true
+		}
+		leastRepositories = max(leastRepositories, collection.leastRepositories())
+	}
+	totals.TotalRepositories = max(len(uniqueRepositories), leastRepositories)
+	return totals
+}
+
+// totals sums the contributions in the query results like sumTotals, filtering the
+// repositories and counting the restricted contributions and the repositories the
+// way the Reporter does, so every section of a report has the same totals
+func (r *Reporter) totals(queryResults map[string]QueryResult) Totals {
+	if !r.Filter.IsZero() {
+		queryResults = FilterQueryResults(queryResults, r.Filter)
+	}
+	totals := sumTotals(queryResults, r.IncludeRestricted)
+	if r.RepositoryCount == RepositoryCountTotals {
+		totals.TotalRepositories = CountRepositories(queryResults).Totals
+	}
+	return totals
+}
 
-	for {
-		select {
-		case <-done:
-			return
-		case t := <-ticker.C:
-			// TODO: poll github here
-			log.Printf("Tick at: %s", t)
+// groupQueryResults splits user-year query results into groups named by the key function
+func groupQueryResults(queryResults map[string]QueryResult,
+	key func(user string, year int) string) map[string]map[string]QueryResult {
+
+	var groups = make(map[string]map[string]QueryResult)
+	for userYear, queryResult := range queryResults {
+		group := key(splitUserYear(userYear))
+		if groups[group] == nil {
+			groups[group] = make(map[string]QueryResult)
 		}
+		groups[group][userYear] = queryResult
+	}
+	return groups
+}
+
+// splitUserYear splits a user-year results key into its username and year.
+// Usernames may contain hyphens, so the year follows the last one.
+func splitUserYear(userYear string) (user string, year int) {
+	index := strings.LastIndex(userYear, "-")
+	if index < 0 {
+		return userYear, 0
+	}
+	year, err := strconv.Atoi(userYear[index+1:])
+	if err != nil {
+		return userYear, 0
 	}
+	return userYear[:index], year
 }
@@ -3,11 +3,13 @@ package reporting_test
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
 	"strconv"
+	"sync"
 	"testing"
 	"time"
 
@@ -217,7 +219,7 @@ func TestAggregate(t *testing.T) {
 			assert.NoError(t, err, "Failed to load fixture: %s", test.fixtureFile)
 
 			reporter := &rpt.Reporter{}
-			result, err := reporter.Aggregate(queryResults)
+			result, err := reporter.Aggregate(context.Background(), queryResults)
 
 			assert.NoError(t, err)
 			// Ensure the three main aggregated stats are reported correctly
@@ -238,7 +240,7 @@ func TestReport(t *testing.T) {
 	assert.NoError(t, err, "Failed to load report test fixture")
 
 	reporter := &rpt.Reporter{}
-	jsonStr, err := reporter.Report(queryResults)
+	jsonStr, err := reporter.Report(context.Background(), queryResults)
 
 	assert.NoError(t, err)
 	assert.NotEmpty(t, jsonStr)
@@ -251,9 +253,6 @@ func TestReport(t *testing.T) {
 
 // Test the Collect method
 func TestCollect(t *testing.T) {
-	// Reset global state before each test
-	// queryResults = make(map[string]QueryResult)
-
 	tests := []struct {
 		name          string
 		fixtureFiles  map[string]string // Maps year -> fixture filename
@@ -322,8 +321,7 @@ func TestCollect(t *testing.T) {
 
 			// Setup reporter and execute
 			test.reporter.Client = mockClient
-			// queryResults, err := test.reporter.Collect()
-			_, err := test.reporter.Collect()
+			_, err := test.reporter.Collect(context.Background())
 
 			// Verify
 			if test.expectedError {
@@ -336,6 +334,76 @@ func TestCollect(t *testing.T) {
 	}
 }
 
+// Test collecting for two users at once, each keeping only its own results
+func TestCollectConcurrently(t *testing.T) {
+	fixture, err := loadSingleFixture("single_user_single_year.json", "user1", "2023")
+	if err != nil {
+		t.Fatalf("Failed to load the fixture: %v", err)
+	}
+	mockClient := &MockGraphQLClient{Responses: map[string]rpt.QueryResult{"2023": fixture}}
+	mockClient.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	users := []string{"user1", "user2"}
+	var results = make([]map[string]rpt.QueryResult, len(users))
+	var errs = make([]error, len(users))
+	var wait sync.WaitGroup
+	for i, user := range users {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			reporter := rpt.Reporter{Client: mockClient, User: user, FirstYear: 2023, LastYear: 2023}
+			results[i], errs[i] = reporter.Collect(context.Background())
+		}()
+	}
+	wait.Wait()
+
+	for i, user := range users {
+		assert.NoError(t, errs[i])
+		assert.Len(t, results[i], 1)
+		assert.Contains(t, results[i], user+"-2023")
+	}
+}
+
+// Test that a failed query returns an error naming the user and year,
+// along with the years collected before it
+func TestCollectError(t *testing.T) {
+	fixture, err := loadSingleFixture("single_user_single_year.json", "user1", "2023")
+	if err != nil {
+		t.Fatalf("Failed to load the fixture: %v", err)
+	}
+	fixture.User.ContributionsCollection.HasActivityInThePast = true
+	queryErr := errors.New("502 Bad Gateway")
+	mockClient := &MockGraphQLClient{Responses: map[string]rpt.QueryResult{"2023": fixture}}
+	mockClient.On("Query", mock.Anything, mock.Anything, mock.MatchedBy(func(vars map[string]interface{}) bool {
+		return vars["from"].(githubv4.DateTime).Year() == 2023
+	})).Return(nil)
+	mockClient.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(queryErr)
+
+	reporter := rpt.Reporter{Client: mockClient, User: "user1", FirstYear: 2021, LastYear: 2023}
+	queryResults, err := reporter.Collect(context.Background())
+	assert.ErrorIs(t, err, queryErr)
+	assert.EqualError(t, err, "failed to query github for user1 in 2022: 502 Bad Gateway")
+	assert.Contains(t, queryResults, "user1-2023")
+	mockClient.AssertNumberOfCalls(t, "Query", 2)
+}
+
+// Test stopping the collection and aggregation with a canceled context
+func TestCollectCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	mockClient := &MockGraphQLClient{}
+	mockClient.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(context.Canceled)
+
+	reporter := rpt.Reporter{Client: mockClient, User: "user1", FirstYear: 2022, LastYear: 2023}
+	queryResults, err := reporter.Collect(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, queryResults)
+	mockClient.AssertNumberOfCalls(t, "Query", 1)
+
+	_, err = reporter.Aggregate(ctx, commitsByYear("user1", map[int]int{2023: 10}))
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
 // Test the Credential struct
 func TestCredential(t *testing.T) {
 	cred := rpt.Credential{
@@ -357,6 +425,25 @@ func TestCredentials(t *testing.T) {
 	assert.Len(t, creds, 2)
 	assert.Equal(t, "user1", creds[0].Username)
 	assert.Equal(t, "token2", creds[1].Token)
+	assert.Equal(t, "user1,user2", creds.Usernames())
+}
+
+// Test expanding environment variables in credentials
+func TestCredentialsExpandEnv(t *testing.T) {
+	t.Setenv("GHC_TEST_TOKEN", "token1")
+	t.Setenv("GHC_TEST_HOST", "ghe.example.com")
+	creds := rpt.Credentials{
+		{Username: "user1", Token: "${GHC_TEST_TOKEN}", URL: "https://${GHC_TEST_HOST}"},
+		{Username: "user2", Token: "pa$$word"},
+	}
+	assert.NoError(t, creds.ExpandEnv())
+	assert.Equal(t, "token1", creds[0].Token)
+	assert.Equal(t, "https://ghe.example.com", creds[0].URL)
+	assert.Equal(t, "pa$$word", creds[1].Token)
+
+	// Ensure a variable that isn't set is an error
+	creds = rpt.Credentials{{Username: "user3", Token: "${GHC_TEST_MISSING}"}}
+	assert.EqualError(t, creds.ExpandEnv(), "the GHC_TEST_MISSING variable in the credential of user3 is not set")
 }
 
 // Test the Repository struct
@@ -373,6 +460,149 @@ func TestRepository(t *testing.T) {
 	assert.Equal(t, expected, string(jsonData))
 }
 
+// Test the totals for each year in the aggregated results
+func TestAggregateByYear(t *testing.T) {
+	queryResults := commitsByYear("user1", map[int]int{2022: 10, 2023: 20})
+	rpt.MergeQueryResults(queryResults, commitsByYear("user2", map[int]int{2023: 5}))
+
+	reporter := &rpt.Reporter{}
+	result, err := reporter.Aggregate(context.Background(), queryResults)
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]rpt.Totals{
+		2022: {TotalCommitContributions: 10, TotalRepositories: 1},
+		2023: {TotalCommitContributions: 25, TotalRepositories: 1},
+	}, result.ByYear)
+
+	jsonData, err := json.Marshal(result)
+	assert.NoError(t, err)
+	assert.Contains(t, string(jsonData), `"byYear":{"2022":{"totalCommitContributions":10,`)
+}
+
+// Test counting restricted contributions among the other contributions
+func TestAggregateRestricted(t *testing.T) {
+	queryResults := commitsByYear("user1", map[int]int{2022: 10, 2023: 20})
+	collection := queryResults["user1-2023"].User.ContributionsCollection
+	collection.TotalIssueContributions = 2
+	collection.RestrictedContributionsCount = 7
+	queryResult := queryResults["user1-2023"]
+	queryResult.User.ContributionsCollection = collection
+	queryResults["user1-2023"] = queryResult
+
+	// Ensure restricted contributions are left out by default
+	result, err := (&rpt.Reporter{}).Aggregate(context.Background(), queryResults)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.TotalOtherContributions)
+	assert.Zero(t, result.TotalRestrictedContributions)
+	assert.Equal(t, 2, result.TotalIssueContributions)
+	assert.Zero(t, result.TotalPullRequestContributions)
+
+	result, err = (&rpt.Reporter{IncludeRestricted: true}).Aggregate(context.Background(), queryResults)
+	assert.NoError(t, err)
+	assert.Equal(t, 9, result.TotalOtherContributions)
+	assert.Equal(t, 7, result.TotalRestrictedContributions)
+	assert.Equal(t, 2, result.TotalIssueContributions)
+	assert.Equal(t, 9, result.ByUser["user1"].TotalOtherContributions)
+	assert.Equal(t, 9, result.ByYear[2023].TotalOtherContributions)
+	assert.Zero(t, result.ByYear[2022].TotalOtherContributions)
+}
+
+// Test adding up the contribution calendar totals of each account, apart from the other metrics
+func TestAggregateCalendarTotal(t *testing.T) {
+	date := time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)
+	queryResults := rpt.QueryResultsFromContributions("user1", []rpt.Contribution{
+		{Kind: rpt.ContributionCommit, Repository: rpt.Repository{Name: "app"}, Date: date, Count: 4},
+		{Kind: rpt.ContributionCalendarTotal, Date: date, Count: 6},
+		{Kind: rpt.ContributionCalendarTotal, Date: date.AddDate(1, 0, 0), Count: 3},
+	})
+	rpt.MergeQueryResults(queryResults, rpt.QueryResultsFromContributions("user2", []rpt.Contribution{
+		{Kind: rpt.ContributionCalendarTotal, Date: date, Count: 10},
+	}))
+
+	result, err := (&rpt.Reporter{}).Aggregate(context.Background(), queryResults)
+	assert.NoError(t, err)
+	assert.Equal(t, 19, result.TotalContributions)
+	assert.Zero(t, result.TotalOtherContributions)
+	assert.Equal(t, 1, result.TotalRepositories)
+	assert.Equal(t, rpt.Totals{TotalCommitContributions: 4, TotalRepositories: 1, TotalContributions: 16}, result.ByYear[2023])
+	assert.Equal(t, 3, result.ByYear[2024].TotalContributions)
+	assert.Equal(t, 9, result.ByUser["user1"].TotalContributions)
+	assert.Equal(t, 19, result.Totals().TotalContributions)
+
+	// Ensure the same user-year from two providers adds up
+	collection := queryResults["user2-2023"].User.ContributionsCollection.Add(queryResults["user1-2023"].User.ContributionsCollection)
+	assert.Equal(t, 16, int(collection.ContributionCalendar.TotalContributions))
+}
+
+// Test flagging archived and disabled repositories in the aggregated results
+func TestAggregateArchivedRepositories(t *testing.T) {
+	date := time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)
+	queryResults := rpt.QueryResultsFromContributions("user1", []rpt.Contribution{
+		{Kind: rpt.ContributionCommit, Repository: rpt.Repository{Name: "old", URL: "u1", IsArchived: true}, Date: date, Count: 2},
+		{Kind: rpt.ContributionIssue, Repository: rpt.Repository{Name: "gone", URL: "u2", IsDisabled: true}, Date: date, Count: 1},
+	})
+
+	reporter := &rpt.Reporter{}
+	result, err := reporter.Aggregate(context.Background(), queryResults)
+	assert.NoError(t, err)
+	assert.Equal(t, []rpt.RepositoryTotals{
+		{Repository: rpt.Repository{Name: "old", URL: "u1", IsArchived: true}, Commits: 2, Contributions: 2},
+		{Repository: rpt.Repository{Name: "gone", URL: "u2", IsDisabled: true}, Issues: 1, Contributions: 1},
+	}, result.Repositories)
+
+	jsonData, err := json.Marshal(result.Repositories[0])
+	assert.NoError(t, err)
+	assert.Regexp(t, `"(isArchived|isDisabled)":true`, string(jsonData))
+}
+
+// Test the contributions of each kind to each repository in the aggregated results
+func TestAggregateRepositoryTotals(t *testing.T) {
+	date := time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)
+	queryResults := rpt.QueryResultsFromContributions("user1", []rpt.Contribution{
+		{Kind: rpt.ContributionCommit, Repository: rpt.Repository{Name: "app", URL: "u1"}, Date: date, Count: 4},
+		{Kind: rpt.ContributionPullRequest, Repository: rpt.Repository{Name: "app", URL: "u1"}, Date: date, Count: 2},
+		{Kind: rpt.ContributionPullRequestReview, Repository: rpt.Repository{Name: "lib", URL: "u2"}, Date: date, Count: 3},
+		{Kind: rpt.ContributionIssue, Repository: rpt.Repository{Name: "docs", URL: "u3"}, Date: date, Count: 3},
+	})
+	rpt.MergeQueryResults(queryResults, rpt.QueryResultsFromContributions("user2", []rpt.Contribution{
+		{Kind: rpt.ContributionCommit, Repository: rpt.Repository{Name: "lib", URL: "u2"}, Date: date, Count: 5},
+	}))
+
+	// Ensure the repositories are sorted by their contributions, then by name
+	result, err := (&rpt.Reporter{}).Aggregate(context.Background(), queryResults)
+	assert.NoError(t, err)
+	assert.Equal(t, []rpt.RepositoryTotals{
+		{Repository: rpt.Repository{Name: "lib", URL: "u2"}, Commits: 5, PullRequestReviews: 3, Contributions: 8},
+		{Repository: rpt.Repository{Name: "app", URL: "u1"}, Commits: 4, PullRequests: 2, Contributions: 6},
+		{Repository: rpt.Repository{Name: "docs", URL: "u3"}, Issues: 3, Contributions: 3},
+	}, result.Repositories)
+	assert.Equal(t, 3, result.TotalRepositories)
+
+	jsonData, err := json.Marshal(result.Repositories[2])
+	assert.NoError(t, err)
+	assert.Equal(t, `{"name":"docs","url":"u3","commits":0,"issues":3,"pullRequests":0,"pullRequestReviews":0,"contributions":3}`,
+		string(jsonData))
+}
+
+// Test that repositories of different owners sharing a name are counted apart
+func TestAggregateRepositoriesSharingName(t *testing.T) {
+	date := time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)
+	queryResults := rpt.QueryResultsFromContributions("alice", []rpt.Contribution{
+		{Kind: rpt.ContributionCommit, Repository: rpt.Repository{Name: "utils", URL: "https://github.com/alice/utils"}, Date: date, Count: 2},
+	})
+	rpt.MergeQueryResults(queryResults, rpt.QueryResultsFromContributions("bob", []rpt.Contribution{
+		{Kind: rpt.ContributionCommit, Repository: rpt.Repository{Name: "utils", URL: "https://github.com/bob/utils"}, Date: date, Count: 3},
+		{Kind: rpt.ContributionIssue, Repository: rpt.Repository{Name: "utils", URL: "https://github.com/bob/utils"}, Date: date, Count: 1},
+	}))
+
+	result, err := (&rpt.Reporter{}).Aggregate(context.Background(), queryResults)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.TotalRepositories)
+	assert.Equal(t, 2, result.ByYear[2023].TotalRepositories)
+	assert.Len(t, result.Repositories, 2)
+	assert.Equal(t, "https://github.com/bob/utils", result.Repositories[0].URL)
+	assert.Len(t, rpt.TopRepositories(queryResults, 10), 2)
+}
+
 // Test the QueryResult struct
 func TestQueryResult(t *testing.T) {
 	result := rpt.QueryResult{}
@@ -394,7 +624,7 @@ func BenchmarkAggregate(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, err := r.Aggregate(queryResults)
+		_, err := r.Aggregate(context.Background(), queryResults)
 		if err != nil {
 			b.Fatal(err)
 		}
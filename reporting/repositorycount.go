@@ -0,0 +1,70 @@
+package reporting
+
+import "slices"
+
+// The ways the repositories contributed to are counted
+const (
+	// Count the unique repositories in the lists by repository, which GitHub cuts
+	// short at MaxRepositories
+	RepositoryCountListed = "listed"
+	// Count the repositories GitHub totals for each kind of contribution, which
+	// are never cut short but can't be told apart across user-years
+	RepositoryCountTotals = "totals"
+)
+
+// The ways of counting repositories, with the default first
+var RepositoryCountModes = []string{RepositoryCountListed, RepositoryCountTotals}
+
+// RepositoryCounts compares the repositories counted from the lists by repository
+// with those counted from GitHub's totals of repositories of each kind
+type RepositoryCounts struct {
+	// The way totalRepositories was counted, like RepositoryCountTotals
+	Counted string `json:"counted"`
+	// The unique repositories in the lists by repository across all user-years
+	Listed int `json:"listed"`
+	// The repositories of each user added up, each counting the unique repositories
+	// listed across the user's years, or those GitHub totals in the user's year with
+	// the most when there are more. A year counts the kind of contribution with the
+	// most repositories, as the kinds mostly go to the same repositories. The totals
+	// can't tell repositories apart, so a repository contributed to in several years
+	// counts once, but one several users contributed to counts for each. It's never
+	// fewer than the repositories listed.
+	Totals int `json:"totals"`
+	// The user-years whose lists leave out some of the repositories GitHub totals
+	Diverged []string `json:"diverged,omitempty"`
+}
+
+// CountRepositories counts the repositories of the query results from the lists
+// by repository and from the totals of repositories of each kind, flagging the
+// user-years where the lists were cut short
+func CountRepositories(queryResults map[string]QueryResult) (counts RepositoryCounts) {
+
+	var uniqueRepositories = make(map[string]bool)
+	var listedByUser = make(map[string]map[string]bool)
+	var leastByUser = make(map[string]int)
+	for userYear, queryResult := range queryResults {
+		user, _ := splitUserYear(userYear)
+		if listedByUser[user] == nil {
+			listedByUser[user] = make(map[string]bool)
+		}
+		collection := queryResult.User.ContributionsCollection
+		listed := make(map[string]bool)
+		for _, repository := range collection.RepositoryContributions() {
+			uniqueRepositories[repository.key()] = true
+			listedByUser[user][repository.key()] = true
+			listed[repository.key()] = true
+		}
+		// The years can't be told apart, so the year with the most counts for the user
+		leastByUser[user] = max(leastByUser[user], len(listed), collection.leastRepositories())
+		if collection.Truncated() {
+			counts.Diverged = append(counts.Diverged, userYear)
+		}
+	}
+	for user, listed := range listedByUser {
+		counts.Totals += max(len(listed), leastByUser[user])
+	}
+	counts.Listed = len(uniqueRepositories)
+	counts.Totals = max(counts.Totals, counts.Listed)
+	slices.Sort(counts.Diverged)
+	return counts
+}
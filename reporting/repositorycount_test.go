@@ -0,0 +1,58 @@
+package reporting_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test counting the repositories from the lists and from the totals of repositories
+func TestCountRepositories(t *testing.T) {
+	date := time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)
+	queryResults := commitsByYear("user1", map[int]int{2022: 10, 2023: 20})
+
+	// A year whose list of repositories committed to was cut short
+	rpt.MergeQueryResults(queryResults, rpt.QueryResultsFromContributions("user2", []rpt.Contribution{
+		{Kind: rpt.ContributionCommit, Repository: rpt.Repository{Name: "web"}, Date: date, Count: 5},
+		{Kind: rpt.ContributionCommit, Date: date, Count: 30, Repositories: 4},
+		{Kind: rpt.ContributionIssue, Repository: rpt.Repository{Name: "docs"}, Date: date, Count: 1},
+	}))
+
+	// Ensure each user counts the repositories of their year with the most, so the
+	// repository committed to in both of user1's years counts once, and the cut
+	// short year diverges
+	counts := rpt.CountRepositories(queryResults)
+	assert.Equal(t, 3, counts.Listed)
+	assert.Equal(t, 5, counts.Totals)
+	assert.Equal(t, []string{"user2-2023"}, counts.Diverged)
+
+	// Ensure the lists count by default, with the comparison added as they diverge
+	result, err := (&rpt.Reporter{}).Aggregate(context.Background(), queryResults)
+	require.NoError(t, err)
+	assert.Equal(t, 4, result.TotalRepositories)
+	require.NotNil(t, result.RepositoryCounts)
+	assert.Equal(t, rpt.RepositoryCountListed, result.RepositoryCounts.Counted)
+
+	result, err = (&rpt.Reporter{RepositoryCount: rpt.RepositoryCountTotals}).Aggregate(context.Background(), queryResults)
+	require.NoError(t, err)
+	assert.Equal(t, 5, result.TotalRepositories)
+	assert.Equal(t, 1, result.ByUser["user1"].TotalRepositories)
+	assert.Equal(t, 4, result.ByUser["user2"].TotalRepositories)
+	assert.Equal(t, rpt.RepositoryCountTotals, result.RepositoryCounts.Counted)
+
+	// Ensure the different repositories listed in a user's years each count
+	manyYears := rpt.QueryResultsFromContributions("user3", []rpt.Contribution{
+		{Kind: rpt.ContributionCommit, Repository: rpt.Repository{Name: "web"}, Date: date, Count: 1},
+		{Kind: rpt.ContributionCommit, Repository: rpt.Repository{Name: "api"}, Date: date.AddDate(1, 0, 0), Count: 1},
+	})
+	assert.Equal(t, 2, rpt.CountRepositories(manyYears).Totals)
+
+	// Ensure the comparison is left out when the lists hold every repository
+	result, err = (&rpt.Reporter{}).Aggregate(context.Background(), commitsByYear("user1", map[int]int{2023: 1}))
+	require.NoError(t, err)
+	assert.Nil(t, result.RepositoryCounts)
+}
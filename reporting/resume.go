@@ -0,0 +1,105 @@
+package reporting
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// The number of top repositories listed for each employment period
+const resumeRepositories = 5
+
+// An EmploymentPeriod labels the dates of a job, like Acme Corp from 2016 to 2019
+type EmploymentPeriod struct {
+	// The label of the period, like the employer's name
+	Label string `json:"label"`
+	// The dates, in any form ParsePeriod accepts. A range without an end, like
+	// 2021-03.., runs to the present.
+	Period string `json:"period"`
+	// The parsed dates of the period
+	Range DateRange `json:"-"`
+}
+
+// A ResumeEntry summarizes the contributions of an employment period
+type ResumeEntry struct {
+	Label string `json:"label"`
+	// The dates of the period
+	Dates string `json:"dates"`
+	Totals
+	// The repositories with the most contributions in the period
+	Repositories []Repository `json:"repositories"`
+}
+
+// ParseEmploymentPeriods parses and validates a JSON list of employment periods,
+// ending open ranges at the current time
+func ParseEmploymentPeriods(data []byte, now time.Time) ([]EmploymentPeriod, error) {
+
+	var periods []EmploymentPeriod
+	err := json.Unmarshal(data, &periods)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the employment periods: %w", err)
+	}
+	if len(periods) == 0 {
+		return nil, fmt.Errorf("the employment periods cannot be empty")
+	}
+
+	today := now.UTC().Format(time.DateOnly)
+	for i, period := range periods {
+		if period.Label == "" {
+			return nil, fmt.Errorf("the employment period %q needs a label", period.Period)
+		}
+		dates := period.Period
+		if strings.HasSuffix(dates, "..") {
+			dates += today
+		}
+		periods[i].Range, err = ParsePeriod(dates)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the %s period: %w", period.Label, err)
+		}
+	}
+	return periods, nil
+}
+
+// Resume summarizes the query results collected over each employment period
+func Resume(periods []EmploymentPeriod, queryResults []map[string]QueryResult) []ResumeEntry {
+	return (&Reporter{}).Resume(periods, queryResults)
+}
+
+// Resume summarizes the query results collected over each employment period like
+// the Resume function, with the totals counted like the Reporter's
+func (r *Reporter) Resume(periods []EmploymentPeriod, queryResults []map[string]QueryResult) []ResumeEntry {
+
+	var entries []ResumeEntry
+	for i, period := range periods {
+		entries = append(entries, ResumeEntry{
+			Label:        period.Label,
+			Dates:        period.Range.String(),
+			Totals:       r.totals(queryResults[i]),
+			Repositories: TopRepositories(queryResults[i], resumeRepositories),
+		})
+	}
+	return entries
+}
+
+// ResumeText renders the entries as plain text, with a paragraph per period
+func ResumeText(entries []ResumeEntry) string {
+
+	var text strings.Builder
+	for i, entry := range entries {
+		if i > 0 {
+			text.WriteString("\n")
+		}
+		fmt.Fprintf(&text, "%s (%s)\n%d commits across %d repositories, %d other contributions\n",
+			entry.Label, entry.Dates, entry.TotalCommitContributions,
+			entry.TotalRepositories, entry.TotalOtherContributions)
+		if len(entry.Repositories) > 0 {
+			var names []string
+			for _, repository := range entry.Repositories {
+				names = append(names, repository.Name)
+			}
+			fmt.Fprintf(&text, "Top repositories: %s\n", strings.Join(names, ", "))
+		}
+	}
+	return text.String()
+}
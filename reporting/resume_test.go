@@ -0,0 +1,74 @@
+package reporting_test
+
+import (
+	"testing"
+	"time"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test parsing employment periods
+func TestParseEmploymentPeriods(t *testing.T) {
+	now := time.Date(2024, time.May, 15, 12, 0, 0, 0, time.UTC)
+	periods, err := rpt.ParseEmploymentPeriods([]byte(`[
+		{"label": "Acme Corp", "period": "2016..2019"},
+		{"label": "Initech", "period": "2020-01..2021-02"},
+		{"label": "Globex", "period": "2021-03.."}
+	]`), now)
+	require.NoError(t, err)
+	require.Len(t, periods, 3)
+	assert.Equal(t, "2016-01-01..2019-12-31", periods[0].Range.String())
+	assert.Equal(t, "2020-01-01..2021-02-28", periods[1].Range.String())
+	assert.Equal(t, "2021-03-01..2024-05-15", periods[2].Range.String())
+
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"invalid json", `{`},
+		{"no periods", `[]`},
+		{"no label", `[{"period": "2016..2019"}]`},
+		{"bad period", `[{"label": "Acme Corp", "period": "2019..2016"}]`},
+		{"starts in the future", `[{"label": "Acme Corp", "period": "2025.."}]`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := rpt.ParseEmploymentPeriods([]byte(test.data), now)
+			assert.Error(t, err)
+		})
+	}
+}
+
+// Test summarizing the contributions of each employment period
+func TestResume(t *testing.T) {
+	periods, err := rpt.ParseEmploymentPeriods([]byte(`[
+		{"label": "Acme Corp", "period": "2016..2019"},
+		{"label": "Globex", "period": "2020"}
+	]`), time.Now())
+	require.NoError(t, err)
+
+	date := time.Date(2018, time.May, 1, 0, 0, 0, 0, time.UTC)
+	acme := rpt.QueryResultsFromContributions("user1", []rpt.Contribution{
+		{Kind: rpt.ContributionCommit, Repository: rpt.Repository{Name: "acme/api", URL: "https://example.com/acme/api"}, Date: date, Count: 30},
+		{Kind: rpt.ContributionCommit, Repository: rpt.Repository{Name: "acme/web", URL: "https://example.com/acme/web"}, Date: date, Count: 10},
+		{Kind: rpt.ContributionPullRequest, Repository: rpt.Repository{Name: "acme/web", URL: "https://example.com/acme/web"}, Date: date, Count: 5},
+	})
+	entries := rpt.Resume(periods, []map[string]rpt.QueryResult{acme, {}})
+	require.Len(t, entries, 2)
+
+	assert.Equal(t, "Acme Corp", entries[0].Label)
+	assert.Equal(t, "2016-01-01..2019-12-31", entries[0].Dates)
+	assert.Equal(t, rpt.Totals{TotalCommitContributions: 40, TotalRepositories: 2, TotalOtherContributions: 5}, entries[0].Totals)
+	require.Len(t, entries[0].Repositories, 2)
+	assert.Equal(t, "acme/api", entries[0].Repositories[0].Name)
+	assert.Empty(t, entries[1].Repositories)
+
+	assert.Equal(t, "Acme Corp (2016-01-01..2019-12-31)\n"+
+		"40 commits across 2 repositories, 5 other contributions\n"+
+		"Top repositories: acme/api, acme/web\n"+
+		"\n"+
+		"Globex (2020-01-01..2020-12-31)\n"+
+		"0 commits across 0 repositories, 0 other contributions\n", rpt.ResumeText(entries))
+}
@@ -0,0 +1,90 @@
+package reporting
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+// A CollectionError records a collection that failed part of the way through,
+// with the user-years it left without results
+type CollectionError struct {
+	User string `json:"user"`
+	// The provider of the user's credential, blank for github
+	Provider string `json:"provider,omitempty"`
+	// The host of the credential's self-managed instance, blank for the provider's own
+	Host string `json:"host,omitempty"`
+	// The user-years in the collection's range without any results
+	UserYears []string `json:"userYears"`
+	Error     string   `json:"error"`
+}
+
+// A Report holds the aggregated results with the query results they were
+// aggregated from, so a partially failed run can be retried and merged
+type Report struct {
+	AggregatedResults
+	QueryResults map[string]QueryResult `json:"queryResults"`
+	// The range collected for each user, so an incremental run can tell years
+	// without any contributions from years that weren't collected
+	Collected map[string]DateRange `json:"collected,omitempty"`
+	// The sources each user was collected from, like github@ghe.example.com, so
+	// an incremental run only reuses the years of the same sources
+	Sources map[string][]string `json:"sources,omitempty"`
+}
+
+// NewCollectionError records the error of a credential's collection over the range, listing
+// the user-years in the range missing from the query results it returned. Years
+// without any contributions are listed too, since they can't be told apart from
+// years that weren't collected. The years are fiscal years when the fiscal year
+// start is after January.
+func NewCollectionError(credential Credential, dateRange DateRange, fiscalYearStart time.Month,
+	queryResults map[string]QueryResult, err error) CollectionError {
+
+	user := credential.Username
+	collectionError := CollectionError{User: user, Provider: credential.Provider, Host: credential.Host(),
+		UserYears: make([]string, 0), Error: err.Error()}
+	lastYear := FiscalYear(dateRange.To.Add(-time.Second), fiscalYearStart)
+	for year := FiscalYear(dateRange.From, fiscalYearStart); year <= lastYear; year++ {
+		userYear := user + "-" + strconv.Itoa(year)
+		if _, found := queryResults[userYear]; !found {
+			collectionError.UserYears = append(collectionError.UserYears, userYear)
+		}
+	}
+	return collectionError
+}
+
+// Merge adds the query results of a retry to the report and aggregates them
+// again, keeping the sections that aren't derived from the query results. The
+// errors of the retry replace the report's errors.
+func (r *Report) Merge(ctx context.Context, queryResults map[string]QueryResult, errors []CollectionError) error {
+
+	if r.QueryResults == nil {
+		r.QueryResults = make(map[string]QueryResult)
+	}
+	MergeQueryResults(r.QueryResults, queryResults)
+
+	reporter := Reporter{FiscalYearStart: time.Month(r.FiscalYearStart), IncludeRestricted: r.IncludeRestricted}
+	if r.RepositoryCounts != nil {
+		reporter.RepositoryCount = r.RepositoryCounts.Counted
+	}
+	if r.RepositoryFilter != nil {
+		reporter.Filter = *r.RepositoryFilter
+	}
+	aggregatedResults, err := reporter.Aggregate(ctx, r.QueryResults)
+	if err != nil {
+		return err
+	}
+	aggregatedResults.Profiles = r.Profiles
+	aggregatedResults.Custom = r.Custom
+	aggregatedResults.Collaborators = r.Collaborators
+	aggregatedResults.Forecast = r.Forecast
+	aggregatedResults.Streaks = r.Streaks
+	aggregatedResults.Activity = r.Activity
+	aggregatedResults.Goals = r.Goals
+	aggregatedResults.TicketProjects = r.TicketProjects
+	aggregatedResults.ByPeriod = r.ByPeriod
+	aggregatedResults.Granularity = r.Granularity
+	aggregatedResults.Errors = errors
+	r.AggregatedResults = aggregatedResults
+	return nil
+}
@@ -0,0 +1,87 @@
+package reporting_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test recording the user-years a failed collection left without results
+func TestNewCollectionError(t *testing.T) {
+	queryResults := commitsByYear("user1", map[int]int{2023: 5, 2024: 3})
+
+	collectionError := rpt.NewCollectionError(rpt.Credential{Username: "user1", Provider: "gitlab"}, rpt.YearRange(2021, 2024), time.January,
+		queryResults, errors.New("rate limited"))
+	assert.Equal(t, rpt.CollectionError{
+		User:      "user1",
+		Provider:  "gitlab",
+		UserYears: []string{"user1-2021", "user1-2022"},
+		Error:     "rate limited",
+	}, collectionError)
+
+	// Ensure fiscal years are listed like the year they end in
+	collectionError = rpt.NewCollectionError(rpt.Credential{Username: "user1", Provider: "gitlab"}, rpt.FiscalYearRange(2022, 2024, time.July), time.July,
+		queryResults, errors.New("rate limited"))
+	assert.Equal(t, []string{"user1-2022"}, collectionError.UserYears)
+}
+
+// Test merging retried query results into a report
+func TestReportMerge(t *testing.T) {
+	reporter := &rpt.Reporter{}
+	queryResults := commitsByYear("user1", map[int]int{2023: 5})
+	aggregatedResults, err := reporter.Aggregate(context.Background(), queryResults)
+	require.NoError(t, err)
+	aggregatedResults.Errors = []rpt.CollectionError{{User: "user1", UserYears: []string{"user1-2022"}, Error: "timeout"}}
+	aggregatedResults.Forecast = &rpt.Forecast{Year: 2023, Projected: 10}
+	aggregatedResults.Activity = &rpt.HourlyActivity{Timezone: "UTC", Commits: 5, ByHour: [24]int{9: 5}}
+
+	// Ensure the report round trips with the errors and query results at the top level
+	reportJSON, err := json.Marshal(rpt.Report{AggregatedResults: aggregatedResults, QueryResults: queryResults})
+	require.NoError(t, err)
+	var fields map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(reportJSON, &fields))
+	assert.Contains(t, fields, "errors")
+	assert.Contains(t, fields, "queryResults")
+
+	var report rpt.Report
+	require.NoError(t, json.Unmarshal(reportJSON, &report))
+	err = report.Merge(context.Background(), commitsByYear("user1", map[int]int{2022: 7}), nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, 12, report.TotalCommitContributions)
+	assert.Len(t, report.QueryResults, 2)
+	assert.Len(t, report.Analysis.TotalCommitContributions.ByYear, 2)
+	assert.Empty(t, report.Errors)
+	assert.Equal(t, aggregatedResults.Forecast, report.Forecast)
+	assert.Equal(t, aggregatedResults.Activity, report.Activity)
+}
+
+// Test merging into a report that counts the restricted contributions
+func TestReportMergeIncludeRestricted(t *testing.T) {
+	date := time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)
+	queryResults := rpt.QueryResultsFromContributions("user1", []rpt.Contribution{
+		{Kind: rpt.ContributionIssue, Repository: rpt.Repository{Name: "app"}, Date: date, Count: 2},
+		{Kind: rpt.ContributionRestricted, Date: date, Count: 3},
+	})
+	aggregatedResults, err := (&rpt.Reporter{IncludeRestricted: true}).Aggregate(context.Background(), queryResults)
+	require.NoError(t, err)
+	assert.Equal(t, 5, aggregatedResults.TotalOtherContributions)
+
+	// Ensure the setting round trips and the merged report still counts them
+	reportJSON, err := json.Marshal(rpt.Report{AggregatedResults: aggregatedResults, QueryResults: queryResults})
+	require.NoError(t, err)
+	var report rpt.Report
+	require.NoError(t, json.Unmarshal(reportJSON, &report))
+	assert.True(t, report.IncludeRestricted)
+	require.NoError(t, report.Merge(context.Background(), commitsByYear("user1", map[int]int{2022: 7}), nil))
+
+	assert.True(t, report.IncludeRestricted)
+	assert.Equal(t, 5, report.TotalOtherContributions)
+	assert.Equal(t, 3, report.TotalRestrictedContributions)
+}
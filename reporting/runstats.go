@@ -0,0 +1,169 @@
+package reporting
+
+import (
+	"maps"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RunStats describe the work a run did, to tune its settings
+type RunStats struct {
+	// The wall time of the run
+	DurationSeconds float64 `json:"durationSeconds"`
+	// The provider API requests sent
+	Requests int `json:"requests"`
+	// The requests that failed or had an error status
+	FailedRequests int `json:"failedRequests"`
+	// The requests sent again after a failure
+	Retries int `json:"retries"`
+	// The user-years read from a cache instead of a provider
+	CacheHits int `json:"cacheHits"`
+	// The user-years looked up in a cache and not found
+	CacheMisses int `json:"cacheMisses"`
+	// The share of cache lookups that were hits, when a cache was used
+	CacheHitRatio *float64 `json:"cacheHitRatio,omitempty"`
+	// The rate limit consumption for each API resource, like graphql or core
+	RateLimits map[string]RateLimitUsage `json:"rateLimits,omitempty"`
+	// The GraphQL rate limit points the contributions queries cost, as GitHub reported
+	QueryCost int `json:"queryCost"`
+}
+
+// RateLimitUsage describes the rate limit consumed from one API resource
+type RateLimitUsage struct {
+	// The points consumed during the run, across every token
+	Used int `json:"used"`
+	// The lowest points remaining seen for any token
+	Remaining int `json:"remaining"`
+	// The points allowed each rate limit window
+	Limit int `json:"limit"`
+}
+
+// A StatsTransport counts the requests it sends and the rate limit consumed,
+// from the X-RateLimit headers GitHub sends with each response
+type StatsTransport struct {
+	// The transport that sends the requests, or http.DefaultTransport if nil
+	Base http.RoundTripper
+
+	mutex          sync.Mutex
+	requests       int
+	failedRequests int
+	retries        int
+	cacheHits      int
+	cacheMisses    int
+	queryCost      int
+	rateLimits     map[string]RateLimitUsage
+	// The last points used for each resource and token
+	lastUsed map[string]int
+}
+
+// RoundTrip sends the request and records its response
+func (s *StatsTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+
+	base := s.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	response, err := base.RoundTrip(request)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.requests++
+	if err != nil || response.StatusCode >= http.StatusBadRequest {
+		s.failedRequests++
+	}
+	if err == nil {
+		s.recordRateLimit(request.Header.Get("Authorization"), response.Header)
+	}
+	return response, err
+}
+
+// recordRateLimit adds the points consumed since the token's last response.
+// Limits are kept per token, so the first response of each counts its own cost
+// and a lower count than before means the window reset.
+func (s *StatsTransport) recordRateLimit(authorization string, header http.Header) {
+
+	used, err := strconv.Atoi(header.Get("X-RateLimit-Used"))
+	if err != nil {
+		return
+	}
+	remaining, _ := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	limit, _ := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	resource := header.Get("X-RateLimit-Resource")
+	if resource == "" {
+		resource = "core"
+	}
+
+	if s.rateLimits == nil {
+		s.rateLimits = make(map[string]RateLimitUsage)
+		s.lastUsed = make(map[string]int)
+	}
+	usage, found := s.rateLimits[resource]
+	if !found || remaining < usage.Remaining {
+		usage.Remaining = remaining
+	}
+	usage.Limit = max(usage.Limit, limit)
+
+	key := resource + " " + authorization
+	lastUsed, found := s.lastUsed[key]
+	switch {
+	case !found:
+		usage.Used += min(used, 1)
+	case used >= lastUsed:
+		usage.Used += used - lastUsed
+	default:
+		usage.Used += used
+	}
+	s.lastUsed[key] = used
+	s.rateLimits[resource] = usage
+}
+
+// RecordRetry counts a request sent again after a failure
+func (s *StatsTransport) RecordRetry() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.retries++
+}
+
+// RecordQueryCost adds up the rate limit cost of a GraphQL query
+func (s *StatsTransport) RecordQueryCost(cost int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.queryCost += cost
+}
+
+// RecordCacheLookup counts a user-year looked up in a cache
+func (s *StatsTransport) RecordCacheLookup(hit bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if hit {
+		s.cacheHits++
+	} else {
+		s.cacheMisses++
+	}
+}
+
+// RunStats returns the statistics recorded so far for a run of the duration
+func (s *StatsTransport) RunStats(duration time.Duration) RunStats {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	stats := RunStats{
+		DurationSeconds: duration.Seconds(),
+		Requests:        s.requests,
+		FailedRequests:  s.failedRequests,
+		Retries:         s.retries,
+		CacheHits:       s.cacheHits,
+		CacheMisses:     s.cacheMisses,
+		QueryCost:       s.queryCost,
+	}
+	if lookups := s.cacheHits + s.cacheMisses; lookups > 0 {
+		ratio := float64(s.cacheHits) / float64(lookups)
+		stats.CacheHitRatio = &ratio
+	}
+	if len(s.rateLimits) > 0 {
+		stats.RateLimits = maps.Clone(s.rateLimits)
+	}
+	return stats
+}
@@ -0,0 +1,80 @@
+package reporting_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test counting requests and the rate limit consumed
+func TestStatsTransport(t *testing.T) {
+	used := map[string]int{"Bearer token1": 40, "Bearer token2": 7}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		authorization := r.Header.Get("Authorization")
+		used[authorization]++
+		w.Header().Set("X-RateLimit-Resource", "graphql")
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Used", strconv.Itoa(used[authorization]))
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(5000-used[authorization]))
+	}))
+	defer server.Close()
+
+	stats := &rpt.StatsTransport{}
+	client := &http.Client{Transport: stats}
+	send := func(path string, authorization string) {
+		request, err := http.NewRequest(http.MethodGet, server.URL+path, nil)
+		require.NoError(t, err)
+		request.Header.Set("Authorization", authorization)
+		response, err := client.Do(request)
+		require.NoError(t, err)
+		response.Body.Close()
+	}
+
+	// Three requests with one token and two with another, with one costing 2 points
+	send("/", "Bearer token1")
+	send("/", "Bearer token1")
+	used["Bearer token1"]++
+	send("/", "Bearer token1")
+	send("/", "Bearer token2")
+	send("/", "Bearer token2")
+	send("/missing", "Bearer token2")
+	stats.RecordRetry()
+	stats.RecordCacheLookup(true)
+	stats.RecordCacheLookup(true)
+	stats.RecordCacheLookup(true)
+	stats.RecordCacheLookup(false)
+	stats.RecordQueryCost(1)
+	stats.RecordQueryCost(2)
+
+	runStats := stats.RunStats(90 * time.Second)
+	assert.Equal(t, 90.0, runStats.DurationSeconds)
+	assert.Equal(t, 6, runStats.Requests)
+	assert.Equal(t, 1, runStats.FailedRequests)
+	assert.Equal(t, 1, runStats.Retries)
+	assert.Equal(t, 3, runStats.CacheHits)
+	assert.Equal(t, 1, runStats.CacheMisses)
+	require.NotNil(t, runStats.CacheHitRatio)
+	assert.Equal(t, 0.75, *runStats.CacheHitRatio)
+	assert.Equal(t, 3, runStats.QueryCost)
+	assert.Equal(t, map[string]rpt.RateLimitUsage{
+		"graphql": {Used: 6, Remaining: 4956, Limit: 5000},
+	}, runStats.RateLimits)
+}
+
+// Test the statistics of a run without requests or a cache
+func TestStatsTransportEmpty(t *testing.T) {
+	runStats := (&rpt.StatsTransport{}).RunStats(time.Second)
+	assert.Equal(t, 0, runStats.Requests)
+	assert.Nil(t, runStats.CacheHitRatio)
+	assert.Nil(t, runStats.RateLimits)
+}
@@ -0,0 +1,112 @@
+package reporting
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// An S3 publisher uploads the report artifacts of each run to an Amazon S3 bucket
+type S3 struct {
+	// The bucket name
+	Bucket string
+	// The key prefix for uploaded objects, like reports/
+	Prefix string
+	// The AWS region of the bucket
+	Region string
+	// The server-side encryption, either AES256 or aws:kms, if any
+	ServerSideEncryption string
+	// The KMS key ID used with aws:kms encryption, if not the default key
+	KMSKeyID string
+	// A custom S3 compatible endpoint using path-style URLs, if any
+	Endpoint string
+	// The credentials used to sign requests
+	Credentials AWSCredentials
+	// The HTTP client used to send requests
+	Client *http.Client
+	// The output format of the report artifact, like yaml, or json if blank
+	Format string
+}
+
+// Constructs a new S3 object
+// The bucket and prefix locate the uploaded objects
+// The region is the AWS region of the bucket
+// The credentials are used to sign the requests
+func NewS3(bucket string, prefix string, region string, credentials AWSCredentials) (s3 *S3, err error) {
+
+	if bucket == "" {
+		err = fmt.Errorf("the s3 bucket cannot be blank")
+		return nil, err
+	}
+
+	if region == "" {
+		err = fmt.Errorf("the s3 region cannot be blank")
+		return nil, err
+	}
+
+	return &S3{
+		Bucket:      bucket,
+		Prefix:      prefix,
+		Region:      region,
+		Credentials: credentials,
+		Client:      http.DefaultClient,
+	}, err
+}
+
+// Export uploads each report artifact under the prefix, named with the time of the run,
+// like reports/20241101T120000Z/report.json
+func (s *S3) Export(queryResults map[string]QueryResult, aggregatedResults AggregatedResults) error {
+
+	if s.ServerSideEncryption != "" && s.ServerSideEncryption != "AES256" && s.ServerSideEncryption != "aws:kms" {
+		return fmt.Errorf("unsupported s3 server-side encryption %q", s.ServerSideEncryption)
+	}
+
+	artifacts, err := ReportArtifacts(queryResults, aggregatedResults, s.Format)
+	if err != nil {
+		return err
+	}
+
+	for _, artifact := range artifacts {
+		key := s.Prefix + artifactTimestamp(aggregatedResults) + "/" + artifact.Name
+		err = s.put(key, artifact)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// put uploads a single artifact to the object key
+func (s *S3) put(key string, artifact Artifact) error {
+
+	endpoint := "https://" + s.Bucket + ".s3." + s.Region + ".amazonaws.com/" + key
+	if s.Endpoint != "" {
+		endpoint = strings.TrimSuffix(s.Endpoint, "/") + "/" + s.Bucket + "/" + key
+	}
+
+	request, err := http.NewRequest(http.MethodPut, endpoint, bytes.NewReader(artifact.Content))
+	if err != nil {
+		return fmt.Errorf("failed to build the s3 request: %w", err)
+	}
+	request.Header.Set("Content-Type", artifact.ContentType)
+	if s.ServerSideEncryption != "" {
+		request.Header.Set("X-Amz-Server-Side-Encryption", s.ServerSideEncryption)
+	}
+	if s.ServerSideEncryption == "aws:kms" && s.KMSKeyID != "" {
+		request.Header.Set("X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id", s.KMSKeyID)
+	}
+	signAWSRequest(request, artifact.Content, s.Credentials, s.Region, "s3", time.Now())
+
+	response, err := s.Client.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to upload %s to s3: %w", key, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode/100 != 2 {
+		return fmt.Errorf("failed to upload %s to s3: s3 returned %s", key, response.Status)
+	}
+	return nil
+}
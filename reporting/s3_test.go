@@ -0,0 +1,58 @@
+package reporting_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the NewS3 constructor
+func TestNewS3(t *testing.T) {
+	_, err := rpt.NewS3("", "reports/", "us-west-2", rpt.AWSCredentials{})
+	assert.Error(t, err)
+
+	_, err = rpt.NewS3("bucket", "reports/", "", rpt.AWSCredentials{})
+	assert.Error(t, err)
+}
+
+// Test the S3 Export method
+func TestS3Export(t *testing.T) {
+	uploads := make(map[string]*http.Request)
+	bodies := make(map[string]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		uploads[r.URL.Path] = r
+		body, _ := io.ReadAll(r.Body)
+		bodies[r.URL.Path] = string(body)
+	}))
+	defer server.Close()
+
+	credentials := rpt.AWSCredentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "secret"}
+	s3, err := rpt.NewS3("reports-bucket", "ghcontributions/", "us-west-2", credentials)
+	assert.NoError(t, err)
+	s3.Endpoint = server.URL
+	s3.ServerSideEncryption = "aws:kms"
+	s3.KMSKeyID = "alias/reports"
+
+	err = s3.Export(nil, rpt.AggregatedResults{Timestamp: 1730462400, TotalCommitContributions: 42})
+	assert.NoError(t, err)
+
+	key := "/reports-bucket/ghcontributions/20241101T120000Z/report.json"
+	if assert.Contains(t, uploads, key) {
+		request := uploads[key]
+		assert.Equal(t, http.MethodPut, request.Method)
+		assert.Equal(t, "application/json", request.Header.Get("Content-Type"))
+		assert.Equal(t, "aws:kms", request.Header.Get("X-Amz-Server-Side-Encryption"))
+		assert.Equal(t, "alias/reports", request.Header.Get("X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id"))
+		assert.NotEmpty(t, request.Header.Get("X-Amz-Content-Sha256"))
+		assert.Contains(t, request.Header.Get("Authorization"), "/us-west-2/s3/aws4_request")
+		assert.Contains(t, bodies[key], `"totalCommitContributions": 42`)
+	}
+
+	// Ensure unsupported encryption is rejected
+	s3.ServerSideEncryption = "rot13"
+	assert.Error(t, s3.Export(nil, rpt.AggregatedResults{}))
+}
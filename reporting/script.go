@@ -0,0 +1,151 @@
+package reporting
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+
+	"go.starlark.net/starlark"
+)
+
+// The name of the function a report script defines
+const scriptFunction = "report"
+
+// The most Starlark execution steps a report script can take
+const scriptMaxSteps = 100_000_000
+
+// RunScript runs a Starlark report script over the query results. The script
+// defines a report function that receives a list of user-years, each a dict with
+// the user, the year, the counts of each kind of contribution, and the
+// repositories contributed to, and returns a dict of fields to add to the report.
+func RunScript(filename string, source []byte, queryResults map[string]QueryResult) (map[string]any, error) {
+
+	thread := &starlark.Thread{
+		Name:  filename,
+		Print: func(thread *starlark.Thread, message string) { slog.Info(message, "script", filename) },
+	}
+	thread.SetMaxExecutionSteps(scriptMaxSteps)
+
+	globals, err := starlark.ExecFile(thread, filename, source, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run the script %s: %w", filename, err)
+	}
+	function, ok := globals[scriptFunction].(starlark.Callable)
+	if !ok {
+		return nil, fmt.Errorf("the script %s must define a %s function", filename, scriptFunction)
+	}
+
+	var userYears = starlark.NewList(nil)
+	for _, userYear := range slices.Sorted(maps.Keys(queryResults)) {
+		userYears.Append(scriptUserYear(userYear, queryResults[userYear]))
+	}
+	result, err := starlark.Call(thread, function, starlark.Tuple{userYears}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run the script %s: %w", filename, err)
+	}
+
+	fields, err := fromStarlark(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the result of the script %s: %w", filename, err)
+	}
+	report, ok := fields.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("the %s function of the script %s must return a dict, not %s", scriptFunction, filename, result.Type())
+	}
+	return report, nil
+}
+
+// scriptUserYear converts a user-year's query result to a Starlark dict
+func scriptUserYear(userYear string, queryResult QueryResult) *starlark.Dict {
+
+	user, year := splitUserYear(userYear)
+	collection := queryResult.User.ContributionsCollection
+
+	// Count each kind of contribution to each repository
+	var repositories = make(map[string]*starlark.Dict)
+	count := func(repositoryContributions []RepositoryContribution, kind string) {
+		for _, repositoryContribution := range repositoryContributions {
+			key := repositoryContribution.key()
+			if repositories[key] == nil {
+				repositories[key] = starlark.NewDict(6)
+				repositories[key].SetKey(starlark.String("name"), starlark.String(repositoryContribution.Repository.Name))
+				repositories[key].SetKey(starlark.String("url"), starlark.String(repositoryContribution.Repository.URL))
+				for _, field := range []string{"commits", "issues", "pullRequests", "reviews"} {
+					repositories[key].SetKey(starlark.String(field), starlark.MakeInt(0))
+				}
+			}
+			previous, _, _ := repositories[key].Get(starlark.String(kind))
+			total, _ := starlark.AsInt32(previous)
+			repositories[key].SetKey(starlark.String(kind), starlark.MakeInt(total+int(repositoryContribution.Contributions.TotalCount)))
+		}
+	}
+	count(collection.CommitContributionsByRepository, "commits")
+	count(collection.IssueContributionsByRepository, "issues")
+	count(collection.PullRequestContributionsByRepository, "pullRequests")
+	count(collection.PullRequestReviewContributionsByRepository, "reviews")
+
+	var repositoryList = starlark.NewList(nil)
+	for _, key := range slices.Sorted(maps.Keys(repositories)) {
+		repositoryList.Append(repositories[key])
+	}
+
+	dict := starlark.NewDict(9)
+	dict.SetKey(starlark.String("user"), starlark.String(user))
+	dict.SetKey(starlark.String("year"), starlark.MakeInt(year))
+	dict.SetKey(starlark.String("commits"), starlark.MakeInt(int(collection.TotalCommitContributions)))
+	dict.SetKey(starlark.String("issues"), starlark.MakeInt(int(collection.TotalIssueContributions)))
+	dict.SetKey(starlark.String("pullRequests"), starlark.MakeInt(int(collection.TotalPullRequestContributions)))
+	dict.SetKey(starlark.String("reviews"), starlark.MakeInt(int(collection.TotalPullRequestReviewContributions)))
+	dict.SetKey(starlark.String("restricted"), starlark.MakeInt(int(collection.RestrictedContributionsCount)))
+	dict.SetKey(starlark.String("repositories"), repositoryList)
+	return dict
+}
+
+// fromStarlark converts a Starlark value to a Go value that encodes as JSON
+func fromStarlark(value starlark.Value) (any, error) {
+
+	switch value := value.(type) {
+	case starlark.NoneType:
+		return nil, nil
+	case starlark.Bool:
+		return bool(value), nil
+	case starlark.Int:
+		number, ok := value.Int64()
+		if !ok {
+			return nil, fmt.Errorf("the integer %s is too large", value)
+		}
+		return number, nil
+	case starlark.Float:
+		return float64(value), nil
+	case starlark.String:
+		return string(value), nil
+	case starlark.Indexable:
+		// Lists and tuples
+		var list = make([]any, 0, value.Len())
+		for index := range value.Len() {
+			item, err := fromStarlark(value.Index(index))
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, item)
+		}
+		return list, nil
+	case *starlark.Dict:
+		var dict = make(map[string]any)
+		for _, item := range value.Items() {
+			key, ok := item[0].(starlark.String)
+			if !ok {
+				return nil, fmt.Errorf("the dict key %s must be a string", item[0])
+			}
+			converted, err := fromStarlark(item[1])
+			if err != nil {
+				return nil, err
+			}
+			dict[string(key)] = converted
+		}
+		return dict, nil
+	default:
+		return nil, fmt.Errorf("the %s value %s can't be added to the report", value.Type(), value)
+	}
+}
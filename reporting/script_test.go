@@ -0,0 +1,67 @@
+package reporting_test
+
+import (
+	"testing"
+	"time"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test running a report script over the user-years
+func TestRunScript(t *testing.T) {
+	date := time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)
+	queryResults := rpt.QueryResultsFromContributions("alice", []rpt.Contribution{
+		{Kind: rpt.ContributionCommit, Repository: rpt.Repository{Name: "api", URL: "u1"}, Date: date, Count: 6},
+		{Kind: rpt.ContributionPullRequestReview, Repository: rpt.Repository{Name: "api", URL: "u1"}, Date: date, Count: 4},
+		{Kind: rpt.ContributionCommit, Repository: rpt.Repository{Name: "docs", URL: "u2"}, Date: date.AddDate(-1, 0, 0), Count: 2},
+	})
+
+	script := `
+def report(results):
+    reviews, commits, busiest = 0, 0, {}
+    for r in results:
+        reviews += r["reviews"]
+        commits += r["commits"]
+        for repo in r["repositories"]:
+            busiest[repo["name"]] = busiest.get(repo["name"], 0) + repo["commits"] + repo["reviews"]
+    return {
+        "reviewRatio": reviews / commits,
+        "years": [r["year"] for r in results],
+        "busiest": busiest,
+        "note": None,
+    }
+`
+	fields, err := rpt.RunScript("metrics.star", []byte(script), queryResults)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"reviewRatio": 0.5,
+		"years":       []any{int64(2022), int64(2023)},
+		"busiest":     map[string]any{"api": int64(10), "docs": int64(2)},
+		"note":        nil,
+	}, fields)
+}
+
+// Test the errors of report scripts
+func TestRunScriptErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		errMsg string
+	}{
+		{"syntax error", "def report(results)\n", "failed to run the script"},
+		{"no report function", "x = 1\n", "must define a report function"},
+		{"runtime error", "def report(results):\n    return 1 // 0\n", "failed to run the script"},
+		{"not a dict", "def report(results):\n    return [1]\n", "must return a dict, not list"},
+		{"unsupported value", "def report(results):\n    return {\"f\": len}\n", "can't be added to the report"},
+		{"non-string key", "def report(results):\n    return {1: 2}\n", "must be a string"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := rpt.RunScript("test.star", []byte(test.script), map[string]rpt.QueryResult{})
+			assert.ErrorContains(t, err, test.errMsg)
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package reporting
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"sync"
+)
+
+// A Server serves the latest results over HTTP: the report as JSON at /report,
+// the metrics of each user-year and of the collections in the Prometheus text
+// format at /metrics, a shields.io endpoint badge at /badge, rendered at
+// /badge.svg, and a health check at /healthz. A POST to /refresh asks
+// for a collection at once. It's an exporter receiving the results of each
+// collection, and it's safe for concurrent use, so they can be exported while serving.
+type Server struct {
+	// Refresh receives the requests to collect at once, or is nil to refuse them
+	Refresh chan<- struct{}
+
+	mutex        sync.RWMutex
+	queryResults map[string]QueryResult
+	results      *AggregatedResults
+	// The counts added up across every collection exported
+	collections      int
+	requests         int
+	failedRequests   int
+	retries          int
+	collectionErrors int
+}
+
+// Export replaces the results served, adding the collection's counts to the
+// counters of every collection
+func (s *Server) Export(queryResults map[string]QueryResult, aggregatedResults AggregatedResults) error {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.queryResults = queryResults
+	s.results = &aggregatedResults
+	s.collections++
+	s.collectionErrors += len(aggregatedResults.Errors)
+	if runStats := aggregatedResults.RunStats; runStats != nil {
+		s.requests += runStats.Requests
+		s.failedRequests += runStats.FailedRequests
+		s.retries += runStats.Retries
+	}
+	return nil
+}
+
+// metrics returns the gauges of the latest results followed by the counters
+// of every collection
+func (s *Server) metrics() []metric {
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	metrics := slices.Concat(s.results.metrics(), userYearMetrics(s.queryResults))
+	if s.results.RunStats != nil {
+		metrics = append(metrics, s.results.RunStats.metrics()...)
+	}
+	return append(metrics,
+		metric{
+			name:    MetricsNamespace + "_collections_total",
+			help:    "The count of collections since the server started.",
+			value:   float64(s.collections),
+			counter: true,
+		},
+		metric{
+			name:    MetricsNamespace + "_requests_total",
+			help:    "The count of provider API requests sent.",
+			value:   float64(s.requests),
+			counter: true,
+		},
+		metric{
+			name:    MetricsNamespace + "_failed_requests_total",
+			help:    "The count of provider API requests that failed or had an error status.",
+			value:   float64(s.failedRequests),
+			counter: true,
+		},
+		metric{
+			name:    MetricsNamespace + "_retries_total",
+			help:    "The count of provider API requests sent again after a failure.",
+			value:   float64(s.retries),
+			counter: true,
+		},
+		metric{
+			name:    MetricsNamespace + "_collection_errors_total",
+			help:    "The count of user collections that failed.",
+			value:   float64(s.collectionErrors),
+			counter: true,
+		},
+	)
+}
+
+// ServeHTTP serves the latest results, or 503 Service Unavailable until the
+// first collection has finished
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	if r.URL.Path == "/refresh" {
+		s.refresh(w, r)
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.mutex.RLock()
+	results := s.results
+	s.mutex.RUnlock()
+	if results == nil && slices.Contains([]string{"/healthz", "/report", "/metrics", "/badge", "/badge.svg"}, r.URL.Path) {
+		http.Error(w, "no results collected yet", http.StatusServiceUnavailable)
+		return
+	}
+
+	// Render the response first so a failure can still send an error status
+	var body bytes.Buffer
+	var err error
+	switch r.URL.Path {
+	case "/healthz":
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		body.WriteString("ok\n")
+	case "/report":
+		w.Header().Set("Content-Type", "application/json")
+		err = json.NewEncoder(&body).Encode(results)
+	case "/metrics":
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		err = writeMetrics(&body, s.metrics())
+	case "/badge":
+		w.Header().Set("Content-Type", "application/json")
+		err = json.NewEncoder(&body).Encode(results.Badge())
+	case "/badge.svg":
+		w.Header().Set("Content-Type", "image/svg+xml")
+		err = results.Badge().WriteSVG(&body)
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Write(body.Bytes())
+}
+
+// refresh asks for a collection at once, unless one is already waiting
+func (s *Server) refresh(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.Refresh == nil {
+		http.Error(w, "refreshing isn't enabled", http.StatusNotFound)
+		return
+	}
+	select {
+	case s.Refresh <- struct{}{}:
+	default:
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
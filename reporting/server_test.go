@@ -0,0 +1,66 @@
+package reporting_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test serving the latest results over HTTP
+func TestServer(t *testing.T) {
+	refresh := make(chan struct{}, 1)
+	server := &rpt.Server{Refresh: refresh}
+	get := func(path string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		return recorder
+	}
+
+	// Ensure nothing is served before the first collection
+	assert.Equal(t, http.StatusServiceUnavailable, get("/healthz").Code)
+	assert.Equal(t, http.StatusServiceUnavailable, get("/report").Code)
+
+	aggregatedResults := rpt.AggregatedResults{Timestamp: 1700000000, TotalCommitContributions: 42,
+		Errors: []rpt.CollectionError{{User: "user2", Error: "timeout"}},
+		RunStats: &rpt.RunStats{Requests: 3, FailedRequests: 1,
+			RateLimits: map[string]rpt.RateLimitUsage{"graphql": {Used: 3, Remaining: 4997, Limit: 5000}}}}
+	queryResults := commitsByYear("user1", map[int]int{2023: 30, 2024: 12})
+	require.NoError(t, server.Export(queryResults, aggregatedResults))
+	assert.Equal(t, http.StatusOK, get("/healthz").Code)
+	response := get("/report")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "application/json", response.Header().Get("Content-Type"))
+	var report rpt.AggregatedResults
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &report))
+	assert.Equal(t, 42, report.TotalCommitContributions)
+	metrics := get("/metrics").Body.String()
+	assert.Contains(t, metrics, "ghcontributions_total_commit_contributions 42\n")
+	assert.Contains(t, metrics, `ghcontributions_commit_contributions{user="user1",year="2023"} 30`+"\n")
+	assert.Contains(t, metrics, `ghcontributions_rate_limit_remaining{resource="graphql"} 4997`+"\n")
+	assert.Contains(t, metrics, "# TYPE ghcontributions_requests_total counter\n")
+
+	// Ensure the counters add up every collection
+	require.NoError(t, server.Export(queryResults, aggregatedResults))
+	metrics = get("/metrics").Body.String()
+	assert.Contains(t, metrics, "ghcontributions_collections_total 2\n")
+	assert.Contains(t, metrics, "ghcontributions_requests_total 6\n")
+	assert.Contains(t, metrics, "ghcontributions_failed_requests_total 2\n")
+	assert.Contains(t, metrics, "ghcontributions_collection_errors_total 2\n")
+	assert.Contains(t, get("/badge").Body.String(), `"message":"42"`)
+	assert.Equal(t, "image/svg+xml", get("/badge.svg").Header().Get("Content-Type"))
+	assert.Equal(t, http.StatusNotFound, get("/other").Code)
+
+	// Ensure a refresh is requested only by a POST, and only once while one is waiting
+	assert.Equal(t, http.StatusMethodNotAllowed, get("/refresh").Code)
+	for range 2 {
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/refresh", nil))
+		assert.Equal(t, http.StatusAccepted, recorder.Code)
+	}
+	assert.Len(t, refresh, 1)
+}
@@ -0,0 +1,98 @@
+package reporting
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+)
+
+// The OAuth scope needed to append rows to Google Sheets
+const SheetsScope = "https://www.googleapis.com/auth/spreadsheets"
+
+// The row layouts supported when appending to a Google Sheet
+const (
+	// SheetsRowsPerRun appends one row of totals for each run
+	SheetsRowsPerRun = "run"
+	// SheetsRowsPerUserYear appends one row for each user-year in the run
+	SheetsRowsPerUserYear = "user-year"
+)
+
+// A Sheets publisher appends the results of each run to a Google Sheet
+type Sheets struct {
+	// The spreadsheet ID, from the sheet's URL
+	SpreadsheetID string
+	// The A1 notation range of the table to append to, like Contributions!A1
+	Range string
+	// The row layout, either SheetsRowsPerRun or SheetsRowsPerUserYear
+	Rows string
+	// The Sheets API endpoint
+	Endpoint string
+	// An HTTP client authenticated for the Sheets API
+	Client *http.Client
+}
+
+// Constructs a new Sheets object
+// The spreadsheetID and sheetRange locate the table to append to
+// The rows is the row layout, either SheetsRowsPerRun or SheetsRowsPerUserYear
+// The client is authenticated for the Sheets API, like one from NewGoogleClient
+func NewSheets(spreadsheetID string, sheetRange string, rows string, client *http.Client) (sheets *Sheets, err error) {
+
+	if spreadsheetID == "" {
+		err = fmt.Errorf("the spreadsheet ID cannot be blank")
+		return nil, err
+	}
+
+	if rows != SheetsRowsPerRun && rows != SheetsRowsPerUserYear {
+		err = fmt.Errorf("the sheet rows must be %q or %q", SheetsRowsPerRun, SheetsRowsPerUserYear)
+		return nil, err
+	}
+
+	if sheetRange == "" {
+		sheetRange = "A1"
+	}
+
+	return &Sheets{
+		SpreadsheetID: spreadsheetID,
+		Range:         sheetRange,
+		Rows:          rows,
+		Endpoint:      "https://sheets.googleapis.com",
+		Client:        client,
+	}, err
+}
+
+// Export appends the rows for the run after the last row of the table
+func (s *Sheets) Export(queryResults map[string]QueryResult, aggregatedResults AggregatedResults) error {
+
+	date := time.Unix(int64(aggregatedResults.Timestamp), 0).UTC().Format(time.DateTime)
+	var rows [][]any
+	if s.Rows == SheetsRowsPerRun {
+		rows = append(rows, []any{
+			date,
+			aggregatedResults.TotalCommitContributions,
+			aggregatedResults.TotalRepositories,
+			aggregatedResults.TotalOtherContributions,
+		})
+	} else {
+		for _, userYear := range slices.Sorted(maps.Keys(queryResults)) {
+			user, year := splitUserYear(userYear)
+			collection := queryResults[userYear].User.ContributionsCollection
+			rows = append(rows, []any{
+				date,
+				user,
+				year,
+				int(collection.TotalCommitContributions),
+				int(collection.TotalIssueContributions),
+				int(collection.TotalPullRequestContributions),
+				int(collection.TotalPullRequestReviewContributions),
+				collection.TotalRepositories(),
+			})
+		}
+	}
+
+	endpoint := s.Endpoint + "/v4/spreadsheets/" + url.PathEscape(s.SpreadsheetID) +
+		"/values/" + url.PathEscape(s.Range) + ":append?valueInputOption=USER_ENTERED&insertDataOption=INSERT_ROWS"
+	return postJSON(s.Client, endpoint, map[string]any{"values": rows}, "google sheets")
+}
@@ -0,0 +1,71 @@
+package reporting_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the NewSheets constructor
+func TestNewSheets(t *testing.T) {
+	_, err := rpt.NewSheets("", "", rpt.SheetsRowsPerRun, http.DefaultClient)
+	assert.Error(t, err)
+
+	_, err = rpt.NewSheets("sheet-id", "", "per-month", http.DefaultClient)
+	assert.Error(t, err)
+}
+
+// Test the Sheets Export method for each row layout
+func TestSheetsExport(t *testing.T) {
+	tests := []struct {
+		name     string
+		rows     string
+		expected [][]any
+	}{
+		{
+			name:     "a row per run",
+			rows:     rpt.SheetsRowsPerRun,
+			expected: [][]any{{"2024-11-01 12:00:00", 10.0, 1.0, 10.0}},
+		},
+		{
+			name:     "a row per user-year",
+			rows:     rpt.SheetsRowsPerUserYear,
+			expected: [][]any{{"2024-11-01 12:00:00", "user1", 2023.0, 10.0, 5.0, 3.0, 2.0, 1.0}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var path string
+			var body struct {
+				Values [][]any `json:"values"`
+			}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				path = r.URL.Path
+				json.NewDecoder(r.Body).Decode(&body)
+			}))
+			defer server.Close()
+
+			sheets, err := rpt.NewSheets("sheet-id", "Contributions!A1", test.rows, server.Client())
+			assert.NoError(t, err)
+			sheets.Endpoint = server.URL
+
+			queryResults, err := loadQueryResultsMap("single_user_single_year.json")
+			assert.NoError(t, err)
+			results, err := (&rpt.Reporter{}).Aggregate(context.Background(), queryResults)
+			assert.NoError(t, err)
+			results.Timestamp = 1730462400
+
+			err = sheets.Export(queryResults, results)
+			assert.NoError(t, err)
+
+			assert.Equal(t, "/v4/spreadsheets/sheet-id/values/Contributions!A1:append", path)
+			assert.Equal(t, test.expected, body.Values)
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package reporting
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// A Slack publisher posts a summary of the results to a Slack incoming webhook
+type Slack struct {
+	// The incoming webhook URL
+	WebhookURL string
+	// The results of the previous snapshot, used to report deltas
+	Previous *AggregatedResults
+	// The HTTP client used to post messages
+	Client *http.Client
+}
+
+// Constructs a new Slack object
+// The webhookURL is the Slack incoming webhook URL
+func NewSlack(webhookURL string) (slack *Slack, err error) {
+
+	if webhookURL == "" {
+		err = fmt.Errorf("the slack webhook URL cannot be blank")
+		return nil, err
+	}
+
+	return &Slack{
+		WebhookURL: webhookURL,
+		Client:     http.DefaultClient,
+	}, err
+}
+
+// Export posts the summary with per-user highlights to the webhook
+func (s *Slack) Export(queryResults map[string]QueryResult, aggregatedResults AggregatedResults) error {
+	message := s.message(Summarize(aggregatedResults, s.Previous))
+	return postJSON(s.Client, s.WebhookURL, message, "slack")
+}
+
+// message builds a Block Kit message from the summary
+func (s *Slack) message(summary Summary) map[string]any {
+
+	commits, repositories, other := metricDeltas(summary.Delta)
+	totals := fmt.Sprintf("*%s* commits across *%s* repositories, and *%s* other contributions",
+		formatMetric(summary.Totals.TotalCommitContributions, commits),
+		formatMetric(summary.Totals.TotalRepositories, repositories),
+		formatMetric(summary.Totals.TotalOtherContributions, other))
+
+	blocks := []map[string]any{
+		{
+			"type": "header",
+			"text": map[string]any{"type": "plain_text", "text": "GitHub contributions summary"},
+		},
+		{
+			"type": "section",
+			"text": map[string]any{"type": "mrkdwn", "text": totals},
+		},
+	}
+
+	if len(summary.Users) > 0 {
+		var highlights bytes.Buffer
+		for _, user := range summary.Users {
+			commits, repositories, other := metricDeltas(user.Delta)
+			fmt.Fprintf(&highlights, "• *%s*: %s commits, %s repositories, %s other\n", user.User,
+				formatMetric(user.Totals.TotalCommitContributions, commits),
+				formatMetric(user.Totals.TotalRepositories, repositories),
+				formatMetric(user.Totals.TotalOtherContributions, other))
+		}
+		blocks = append(blocks,
+			map[string]any{"type": "divider"},
+			map[string]any{
+				"type": "section",
+				"text": map[string]any{"type": "mrkdwn", "text": highlights.String()},
+			})
+	}
+
+	if len(summary.Goals) > 0 {
+		var goals bytes.Buffer
+		for _, goal := range summary.Goals {
+			fmt.Fprintf(&goals, "%s\n", goal)
+		}
+		blocks = append(blocks,
+			map[string]any{"type": "divider"},
+			map[string]any{
+				"type": "section",
+				"text": map[string]any{"type": "mrkdwn", "text": "*Goals*\n```" + goals.String() + "```"},
+			})
+	}
+
+	return map[string]any{
+		"text":   totals,
+		"blocks": blocks,
+	}
+}
+
+// postJSON posts the value as JSON to the URL, naming the service in errors
+func postJSON(client *http.Client, url string, value any, service string) error {
+
+	body, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	response, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to post to %s: %w", service, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode/100 != 2 {
+		return fmt.Errorf("failed to post to %s: %s returned %s", service, service, response.Status)
+	}
+	return nil
+}
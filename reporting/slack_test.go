@@ -0,0 +1,46 @@
+package reporting_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the NewSlack constructor
+func TestNewSlack(t *testing.T) {
+	_, err := rpt.NewSlack("")
+	assert.Error(t, err)
+}
+
+// Test the Slack Export method
+func TestSlackExport(t *testing.T) {
+	var message map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&message)
+	}))
+	defer server.Close()
+
+	slack, err := rpt.NewSlack(server.URL)
+	assert.NoError(t, err)
+	slack.Previous = &rpt.AggregatedResults{TotalCommitContributions: 8}
+
+	results := rpt.AggregatedResults{
+		TotalCommitContributions: 10,
+		TotalRepositories:        2,
+		ByUser: map[string]rpt.Totals{
+			"user1": {TotalCommitContributions: 10, TotalRepositories: 2},
+		},
+	}
+	err = slack.Export(nil, results)
+	assert.NoError(t, err)
+
+	assert.Contains(t, message["text"], "*10 (+2)* commits")
+	blocks := message["blocks"].([]any)
+	assert.Len(t, blocks, 4)
+	highlights := blocks[3].(map[string]any)["text"].(map[string]any)["text"]
+	assert.Contains(t, highlights, "*user1*: 10 (+10) commits")
+}
@@ -0,0 +1,141 @@
+package reporting
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// A Snapshot records the aggregated results of a single run
+type Snapshot struct {
+	Timestamp int               `json:"timestamp"`
+	Results   AggregatedResults `json:"results"`
+	// The raw per user-year results, keyed like user-year
+	QueryResults map[string]QueryResult `json:"queryResults,omitempty"`
+	// Details about the run that produced the results
+	Run RunMetadata `json:"run"`
+}
+
+// RunMetadata describes how a run's results were collected
+type RunMetadata struct {
+	StartTime       int      `json:"startTime"`
+	DurationSeconds float64  `json:"durationSeconds"`
+	FirstYear       int      `json:"firstYear"`
+	LastYear        int      `json:"lastYear"`
+	Users           []string `json:"users"`
+}
+
+// A SnapshotStore persists the history of run snapshots
+type SnapshotStore interface {
+	// Save appends a snapshot to the history
+	Save(snapshot Snapshot) error
+	// Snapshots returns the history of snapshots, oldest first
+	Snapshots() ([]Snapshot, error)
+}
+
+// LatestSnapshot returns the most recent snapshot in the store, and
+// false if the store has no snapshots yet
+func LatestSnapshot(store SnapshotStore) (snapshot Snapshot, found bool, err error) {
+	snapshots, err := store.Snapshots()
+	if err != nil || len(snapshots) == 0 {
+		return Snapshot{}, false, err
+	}
+	return snapshots[len(snapshots)-1], true, nil
+}
+
+// NewSnapshotStore opens the snapshot store for the path, choosing the
+// backend from the file extension: .db, .sqlite, and .sqlite3 files use
+// SQLite, .bolt and .bbolt files use bbolt, and any other file uses JSON
+func NewSnapshotStore(path string) (store SnapshotStore, err error) {
+
+	switch filepath.Ext(path) {
+	case ".db", ".sqlite", ".sqlite3":
+		return NewSQLiteSnapshotStore(path)
+	case ".bolt", ".bbolt":
+		return NewBoltSnapshotStore(path)
+	default:
+		return NewFileSnapshotStore(path)
+	}
+}
+
+// A FileSnapshotStore keeps the snapshot history as a list in a JSON file
+type FileSnapshotStore struct {
+	// The path of the JSON file
+	Path string
+}
+
+// Constructs a new FileSnapshotStore object
+// The path is the JSON file holding the snapshot history, created on the first save
+func NewFileSnapshotStore(path string) (store *FileSnapshotStore, err error) {
+
+	if path == "" {
+		err = fmt.Errorf("the snapshot file path cannot be blank")
+		return nil, err
+	}
+
+	return &FileSnapshotStore{Path: path}, err
+}
+
+// Snapshots reads the snapshot history from the file, oldest first
+func (f *FileSnapshotStore) Snapshots() ([]Snapshot, error) {
+
+	var snapshots = make([]Snapshot, 0)
+	data, err := os.ReadFile(f.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return snapshots, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the snapshot file: %w", err)
+	}
+
+	err = json.Unmarshal(data, &snapshots)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the snapshot file: %w", err)
+	}
+	return snapshots, nil
+}
+
+// Save appends the snapshot to the history, replacing the file atomically
+func (f *FileSnapshotStore) Save(snapshot Snapshot) error {
+
+	snapshots, err := f.Snapshots()
+	if err != nil {
+		return err
+	}
+	snapshots = append(snapshots, snapshot)
+
+	data, err := json.MarshalIndent(snapshots, "", "  ")
+	if err != nil {
+		return err
+	}
+	return WriteFileAtomically(f.Path, data)
+}
+
+// WriteFileAtomically writes the data to a temporary file in the same
+// directory and renames it over the path, so readers never see partial files
+func WriteFileAtomically(path string, data []byte) error {
+
+	temporaryFile, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to create a temporary file: %w", err)
+	}
+	defer os.Remove(temporaryFile.Name())
+
+	_, err = temporaryFile.Write(data)
+	if err == nil {
+		err = temporaryFile.Close()
+	} else {
+		temporaryFile.Close()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	err = os.Rename(temporaryFile.Name(), path)
+	if err != nil {
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package reporting_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the NewFileSnapshotStore constructor
+func TestNewFileSnapshotStore(t *testing.T) {
+	_, err := rpt.NewFileSnapshotStore("")
+	assert.Error(t, err)
+}
+
+// Test saving and listing snapshots in a FileSnapshotStore
+func TestFileSnapshotStore(t *testing.T) {
+	store, err := rpt.NewFileSnapshotStore(filepath.Join(t.TempDir(), "snapshots.json"))
+	assert.NoError(t, err)
+
+	// Ensure an empty store has no latest snapshot
+	_, found, err := rpt.LatestSnapshot(store)
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	for _, commits := range []int{10, 20} {
+		snapshot := rpt.Snapshot{
+			Timestamp: commits,
+			Results:   rpt.AggregatedResults{TotalCommitContributions: commits},
+		}
+		assert.NoError(t, store.Save(snapshot))
+	}
+
+	snapshots, err := store.Snapshots()
+	assert.NoError(t, err)
+	assert.Len(t, snapshots, 2)
+
+	latest, found, err := rpt.LatestSnapshot(store)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, 20, latest.Results.TotalCommitContributions)
+}
@@ -0,0 +1,210 @@
+package reporting
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// The SQLite schema, with a row per run and a row per user-year result
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS runs (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	timestamp INTEGER NOT NULL,
+	start_time INTEGER NOT NULL,
+	duration_seconds REAL NOT NULL,
+	first_year INTEGER NOT NULL,
+	last_year INTEGER NOT NULL,
+	users TEXT NOT NULL,
+	total_commit_contributions INTEGER NOT NULL,
+	total_repositories INTEGER NOT NULL,
+	total_other_contributions INTEGER NOT NULL,
+	results TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS user_year_results (
+	run_id INTEGER NOT NULL REFERENCES runs(id) ON DELETE CASCADE,
+	user TEXT NOT NULL,
+	year INTEGER NOT NULL,
+	commits INTEGER NOT NULL,
+	issues INTEGER NOT NULL,
+	pull_requests INTEGER NOT NULL,
+	pull_request_reviews INTEGER NOT NULL,
+	repositories INTEGER NOT NULL,
+	restricted INTEGER NOT NULL,
+	result TEXT NOT NULL,
+	PRIMARY KEY (run_id, user, year)
+);
+`
+
+// A SQLiteSnapshotStore keeps the snapshot history, including the raw
+// user-year results and run metadata, in a single SQLite database file
+type SQLiteSnapshotStore struct {
+	// The path of the database file
+	Path string
+	db   *sql.DB
+}
+
+// Constructs a new SQLiteSnapshotStore object
+// The path is the SQLite database file, created with the schema if needed
+func NewSQLiteSnapshotStore(path string) (store *SQLiteSnapshotStore, err error) {
+
+	if path == "" {
+		err = fmt.Errorf("the snapshot database path cannot be blank")
+		return nil, err
+	}
+
+	db, err := sql.Open("sqlite3", path+"?_foreign_keys=on&_busy_timeout=5000")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the snapshot database: %w", err)
+	}
+	_, err = db.Exec(sqliteSchema)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create the snapshot database schema: %w", err)
+	}
+
+	return &SQLiteSnapshotStore{Path: path, db: db}, err
+}
+
+// Close closes the database
+func (s *SQLiteSnapshotStore) Close() error {
+	return s.db.Close()
+}
+
+// Save inserts the run and its user-year results in a single transaction
+func (s *SQLiteSnapshotStore) Save(snapshot Snapshot) error {
+
+	results, err := json.Marshal(snapshot.Results)
+	if err != nil {
+		return err
+	}
+	users, err := json.Marshal(snapshot.Run.Users)
+	if err != nil {
+		return err
+	}
+
+	transaction, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to save the snapshot: %w", err)
+	}
+	defer transaction.Rollback()
+
+	run, err := transaction.Exec(`INSERT INTO runs (timestamp, start_time, duration_seconds,
+		first_year, last_year, users, total_commit_contributions, total_repositories,
+		total_other_contributions, results) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		snapshot.Timestamp, snapshot.Run.StartTime, snapshot.Run.DurationSeconds,
+		snapshot.Run.FirstYear, snapshot.Run.LastYear, string(users),
+		snapshot.Results.TotalCommitContributions, snapshot.Results.TotalRepositories,
+		snapshot.Results.TotalOtherContributions, string(results))
+	if err != nil {
+		return fmt.Errorf("failed to save the snapshot: %w", err)
+	}
+	runID, err := run.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to save the snapshot: %w", err)
+	}
+
+	for _, userYear := range slices.Sorted(maps.Keys(snapshot.QueryResults)) {
+		queryResult := snapshot.QueryResults[userYear]
+		result, err := json.Marshal(queryResult)
+		if err != nil {
+			return err
+		}
+		user, year := splitUserYear(userYear)
+		collection := queryResult.User.ContributionsCollection
+		_, err = transaction.Exec(`INSERT INTO user_year_results (run_id, user, year, commits,
+			issues, pull_requests, pull_request_reviews, repositories, restricted, result)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			runID, user, year,
+			int(collection.TotalCommitContributions),
+			int(collection.TotalIssueContributions),
+			int(collection.TotalPullRequestContributions),
+			int(collection.TotalPullRequestReviewContributions),
+			collection.TotalRepositories(),
+			int(collection.RestrictedContributionsCount),
+			string(result))
+		if err != nil {
+			return fmt.Errorf("failed to save the %s results: %w", userYear, err)
+		}
+	}
+
+	err = transaction.Commit()
+	if err != nil {
+		return fmt.Errorf("failed to save the snapshot: %w", err)
+	}
+	return nil
+}
+
+// Snapshots reads the snapshot history from the database, oldest first
+func (s *SQLiteSnapshotStore) Snapshots() ([]Snapshot, error) {
+
+	rows, err := s.db.Query(`SELECT id, timestamp, start_time, duration_seconds,
+		first_year, last_year, users, results FROM runs ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	var snapshots = make([]Snapshot, 0)
+	var runIDs = make(map[int64]int)
+	for rows.Next() {
+		var snapshot Snapshot
+		var runID int64
+		var users, results string
+		err = rows.Scan(&runID, &snapshot.Timestamp, &snapshot.Run.StartTime,
+			&snapshot.Run.DurationSeconds, &snapshot.Run.FirstYear, &snapshot.Run.LastYear,
+			&users, &results)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the snapshots: %w", err)
+		}
+		err = json.Unmarshal([]byte(users), &snapshot.Run.Users)
+		if err == nil {
+			err = json.Unmarshal([]byte(results), &snapshot.Results)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the snapshot for run %d: %w", runID, err)
+		}
+		runIDs[runID] = len(snapshots)
+		snapshots = append(snapshots, snapshot)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the snapshots: %w", err)
+	}
+
+	// Attach the raw user-year results to their runs
+	resultRows, err := s.db.Query(`SELECT run_id, user, year, result FROM user_year_results`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the user-year results: %w", err)
+	}
+	defer resultRows.Close()
+
+	for resultRows.Next() {
+		var runID int64
+		var user, result string
+		var year int
+		err = resultRows.Scan(&runID, &user, &year, &result)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the user-year results: %w", err)
+		}
+		var queryResult QueryResult
+		err = json.Unmarshal([]byte(result), &queryResult)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the %s-%d results: %w", user, year, err)
+		}
+		snapshot := &snapshots[runIDs[runID]]
+		if snapshot.QueryResults == nil {
+			snapshot.QueryResults = make(map[string]QueryResult)
+		}
+		snapshot.QueryResults[fmt.Sprintf("%s-%d", user, year)] = queryResult
+	}
+	err = resultRows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the user-year results: %w", err)
+	}
+	return snapshots, nil
+}
@@ -0,0 +1,78 @@
+package reporting_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the NewSQLiteSnapshotStore constructor
+func TestNewSQLiteSnapshotStore(t *testing.T) {
+	_, err := rpt.NewSQLiteSnapshotStore("")
+	assert.Error(t, err)
+}
+
+// Test saving and listing snapshots in a SQLiteSnapshotStore
+func TestSQLiteSnapshotStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.db")
+	store, err := rpt.NewSQLiteSnapshotStore(path)
+	assert.NoError(t, err)
+	defer store.Close()
+
+	// Ensure an empty store has no latest snapshot
+	_, found, err := rpt.LatestSnapshot(store)
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	queryResults, err := loadQueryResultsMap("multiple_years_deduplicated.json")
+	assert.NoError(t, err)
+
+	for _, commits := range []int{10, 20} {
+		snapshot := rpt.Snapshot{
+			Timestamp:    commits,
+			Results:      rpt.AggregatedResults{TotalCommitContributions: commits},
+			QueryResults: queryResults,
+			Run: rpt.RunMetadata{
+				StartTime: commits - 1,
+				FirstYear: 2022,
+				LastYear:  2024,
+				Users:     []string{"user1"},
+			},
+		}
+		assert.NoError(t, store.Save(snapshot))
+	}
+
+	// Ensure the snapshots survive reopening the database
+	assert.NoError(t, store.Close())
+	store, err = rpt.NewSQLiteSnapshotStore(path)
+	assert.NoError(t, err)
+
+	snapshots, err := store.Snapshots()
+	assert.NoError(t, err)
+	assert.Len(t, snapshots, 2)
+
+	latest, found, err := rpt.LatestSnapshot(store)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, 20, latest.Results.TotalCommitContributions)
+	assert.Equal(t, 19, latest.Run.StartTime)
+	assert.Equal(t, []string{"user1"}, latest.Run.Users)
+	assert.Equal(t, queryResults, latest.QueryResults)
+}
+
+// Test choosing the snapshot store backend from the file extension
+func TestNewSnapshotStore(t *testing.T) {
+	store, err := rpt.NewSnapshotStore(filepath.Join(t.TempDir(), "history.sqlite"))
+	assert.NoError(t, err)
+	assert.IsType(t, &rpt.SQLiteSnapshotStore{}, store)
+
+	store, err = rpt.NewSnapshotStore(filepath.Join(t.TempDir(), "history.bolt"))
+	assert.NoError(t, err)
+	assert.IsType(t, &rpt.BoltSnapshotStore{}, store)
+
+	store, err = rpt.NewSnapshotStore(filepath.Join(t.TempDir(), "snapshots.json"))
+	assert.NoError(t, err)
+	assert.IsType(t, &rpt.FileSnapshotStore{}, store)
+}
@@ -0,0 +1,135 @@
+package reporting
+
+import (
+	"fmt"
+	"maps"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A StatsD client emits collection metrics and final totals to a StatsD
+// or DogStatsD endpoint over UDP
+type StatsD struct {
+	// The host:port address of the StatsD endpoint
+	Address string
+	// The prefix added to every metric name
+	Prefix string
+	// Whether to send DogStatsD tags, or to fold the tags into the metric name
+	Tagged bool
+	// The UDP connection to the endpoint
+	conn net.Conn
+}
+
+// Constructs a new StatsD object
+// The address is the host:port of the StatsD endpoint
+// The tagged flag enables DogStatsD style tags for the user and year
+func NewStatsD(address string, tagged bool) (statsd *StatsD, err error) {
+
+	if address == "" {
+		err = fmt.Errorf("the statsd address cannot be blank")
+		return nil, err
+	}
+
+	conn, err := net.Dial("udp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to statsd: %w", err)
+	}
+
+	return &StatsD{
+		Address: address,
+		Prefix:  MetricsNamespace + ".",
+		Tagged:  tagged,
+		conn:    conn,
+	}, err
+}
+
+// Gauge sends a gauge value for the named metric
+func (s *StatsD) Gauge(name string, value int, tags map[string]string) error {
+	return s.send(name, strconv.Itoa(value)+"|g", tags)
+}
+
+// Timing sends a duration in milliseconds for the named metric
+func (s *StatsD) Timing(name string, duration time.Duration, tags map[string]string) error {
+	return s.send(name, strconv.FormatInt(duration.Milliseconds(), 10)+"|ms", tags)
+}
+
+// Close closes the connection to the StatsD endpoint
+func (s *StatsD) Close() error {
+	return s.conn.Close()
+}
+
+// Export sends per user-year contribution gauges tagged by user and year,
+// followed by the aggregated totals
+func (s *StatsD) Export(queryResults map[string]QueryResult, aggregatedResults AggregatedResults) error {
+
+	for _, userYear := range slices.Sorted(maps.Keys(queryResults)) {
+		user, year := splitUserYear(userYear)
+		tags := map[string]string{"user": user, "year": strconv.Itoa(year)}
+		collection := queryResults[userYear].User.ContributionsCollection
+
+		var gauges = map[string]int{
+			"commits":              int(collection.TotalCommitContributions),
+			"issues":               int(collection.TotalIssueContributions),
+			"pull_requests":        int(collection.TotalPullRequestContributions),
+			"pull_request_reviews": int(collection.TotalPullRequestReviewContributions),
+		}
+		for _, name := range slices.Sorted(maps.Keys(gauges)) {
+			err := s.Gauge(name, gauges[name], tags)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	var totals = map[string]int{
+		"total.commits":      aggregatedResults.TotalCommitContributions,
+		"total.repositories": aggregatedResults.TotalRepositories,
+		"total.other":        aggregatedResults.TotalOtherContributions,
+	}
+	for _, name := range slices.Sorted(maps.Keys(totals)) {
+		err := s.Gauge(name, totals[name], nil)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// send writes a single metric line, like prefix.name:value|type|#key:value
+func (s *StatsD) send(name string, valueAndType string, tags map[string]string) error {
+
+	keys := slices.Sorted(maps.Keys(tags))
+	var line strings.Builder
+	line.WriteString(s.Prefix)
+	line.WriteString(name)
+
+	// Plain StatsD has no tags, so fold them into the metric name
+	if !s.Tagged {
+		for _, key := range keys {
+			line.WriteString("." + key + "." + sanitizeStatsDName(tags[key]))
+		}
+	}
+	line.WriteString(":" + valueAndType)
+
+	if s.Tagged && len(keys) > 0 {
+		pairs := make([]string, 0, len(keys))
+		for _, key := range keys {
+			pairs = append(pairs, key+":"+tags[key])
+		}
+		line.WriteString("|#" + strings.Join(pairs, ","))
+	}
+
+	_, err := s.conn.Write([]byte(line.String()))
+	if err != nil {
+		return fmt.Errorf("failed to send %s to statsd: %w", name, err)
+	}
+	return nil
+}
+
+// sanitizeStatsDName replaces characters that StatsD treats as separators
+func sanitizeStatsDName(name string) string {
+	return strings.NewReplacer(".", "_", ":", "_", "|", "_", "@", "_").Replace(name)
+}
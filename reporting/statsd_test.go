@@ -0,0 +1,88 @@
+package reporting_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+)
+
+// listenStatsD starts a local UDP listener that collects received metric lines
+func listenStatsD(t *testing.T) (net.PacketConn, func() []string) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen for statsd packets: %v", err)
+	}
+	received := func() []string {
+		lines := make([]string, 0)
+		buffer := make([]byte, 1024)
+		for {
+			conn.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+			n, _, err := conn.ReadFrom(buffer)
+			if err != nil {
+				return lines
+			}
+			lines = append(lines, string(buffer[:n]))
+		}
+	}
+	return conn, received
+}
+
+// Test the NewStatsD constructor
+func TestNewStatsD(t *testing.T) {
+	_, err := rpt.NewStatsD("", false)
+	assert.Error(t, err)
+}
+
+// Test the StatsD Export method
+func TestStatsDExport(t *testing.T) {
+	tests := []struct {
+		name     string
+		tagged   bool
+		expected []string
+	}{
+		{
+			name:   "dogstatsd tags",
+			tagged: true,
+			expected: []string{
+				"ghcontributions.commits:10|g|#user:user1,year:2023",
+				"ghcontributions.total.commits:10|g",
+			},
+		},
+		{
+			name:   "plain statsd names",
+			tagged: false,
+			expected: []string{
+				"ghcontributions.commits.user.user1.year.2023:10|g",
+				"ghcontributions.total.repositories:1|g",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conn, received := listenStatsD(t)
+			defer conn.Close()
+
+			queryResults, err := loadQueryResultsMap("single_user_single_year.json")
+			assert.NoError(t, err)
+			aggregatedResults, err := (&rpt.Reporter{}).Aggregate(context.Background(), queryResults)
+			assert.NoError(t, err)
+
+			statsd, err := rpt.NewStatsD(conn.LocalAddr().String(), test.tagged)
+			assert.NoError(t, err)
+			defer statsd.Close()
+
+			err = statsd.Export(queryResults, aggregatedResults)
+			assert.NoError(t, err)
+
+			lines := received()
+			for _, line := range test.expected {
+				assert.Contains(t, lines, line)
+			}
+		})
+	}
+}
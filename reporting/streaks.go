@@ -0,0 +1,67 @@
+package reporting
+
+import (
+	"math"
+	"time"
+)
+
+// Streaks holds the metrics of the days with contributions, from the daily counts
+// of the contribution calendar merged across accounts and years
+type Streaks struct {
+	// The consecutive days with contributions up to today, or up to yesterday
+	// when there are none yet today, like GitHub's streak
+	CurrentStreak int `json:"currentStreak"`
+	// The most consecutive days with contributions, and the first of them, the
+	// earliest when streaks tie
+	LongestStreak      int       `json:"longestStreak"`
+	LongestStreakStart time.Time `json:"longestStreakStart,omitzero"`
+	// The day with the most contributions, the earliest when days tie
+	BusiestDay ContributionDay `json:"busiestDay,omitzero"`
+	// The days with any contributions, and their average contributions
+	ActiveDays          int     `json:"activeDays"`
+	AveragePerActiveDay float64 `json:"averagePerActiveDay"`
+}
+
+// FindStreaks finds the streaks of the daily counts, adding up the counts of each
+// day, like those of several accounts, ending the current streak at the time
+func FindStreaks(days []ContributionDay, now time.Time) Streaks {
+
+	var streaks Streaks
+	total := 0
+	streak := 0
+	var previous, streakStart time.Time
+	for _, day := range MergeContributionDays(days) {
+		if day.Count <= 0 {
+			continue
+		}
+		streaks.ActiveDays++
+		total += day.Count
+		if day.Count > streaks.BusiestDay.Count {
+			streaks.BusiestDay = day
+		}
+
+		// Continue the streak from the day before, or start another
+		if streak > 0 && day.Date.Equal(previous.AddDate(0, 0, 1)) {
+			streak++
+		} else {
+			streak = 1
+			streakStart = day.Date
+		}
+		if streak > streaks.LongestStreak {
+			streaks.LongestStreak = streak
+			streaks.LongestStreakStart = streakStart
+		}
+		previous = day.Date
+	}
+
+	// The last streak is current when it reaches today or yesterday
+	year, month, date := now.UTC().Date()
+	today := time.Date(year, month, date, 0, 0, 0, 0, time.UTC)
+	if streak > 0 && !previous.Before(today.AddDate(0, 0, -1)) {
+		streaks.CurrentStreak = streak
+	}
+	if streaks.ActiveDays > 0 {
+		streaks.AveragePerActiveDay = math.Round(float64(total)/float64(streaks.ActiveDays)*100) / 100
+	}
+	return streaks
+}
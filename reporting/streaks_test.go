@@ -0,0 +1,41 @@
+package reporting_test
+
+import (
+	"testing"
+	"time"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test finding the streaks of days with contributions across two accounts
+func TestFindStreaks(t *testing.T) {
+	day := func(month time.Month, date int, count int) rpt.ContributionDay {
+		return rpt.ContributionDay{Date: time.Date(2024, month, date, 0, 0, 0, 0, time.UTC), Count: count}
+	}
+	days := []rpt.ContributionDay{
+		day(time.January, 30, 2), day(time.January, 31, 1), day(time.February, 1, 3),
+		day(time.February, 2, 0),
+		day(time.March, 9, 4), day(time.March, 10, 0),
+		// The second account's days, joining the first account's into a streak of four
+		day(time.March, 10, 5), day(time.March, 11, 1), day(time.March, 8, 2),
+	}
+	now := time.Date(2024, time.March, 12, 15, 0, 0, 0, time.UTC)
+
+	streaks := rpt.FindStreaks(days, now)
+	assert.Equal(t, 4, streaks.LongestStreak)
+	assert.Equal(t, day(time.March, 8, 0).Date, streaks.LongestStreakStart)
+	assert.Equal(t, 4, streaks.CurrentStreak)
+	assert.Equal(t, day(time.March, 10, 5), streaks.BusiestDay)
+	assert.Equal(t, 7, streaks.ActiveDays)
+	assert.Equal(t, 2.57, streaks.AveragePerActiveDay)
+
+	// Ensure a streak that ended before yesterday isn't current
+	streaks = rpt.FindStreaks(days[:6], now)
+	assert.Zero(t, streaks.CurrentStreak)
+	assert.Equal(t, 3, streaks.LongestStreak)
+	assert.Equal(t, day(time.January, 30, 0).Date, streaks.LongestStreakStart)
+
+	// Ensure no days have no streaks
+	assert.Equal(t, rpt.Streaks{}, rpt.FindStreaks(nil, now))
+}
@@ -0,0 +1,136 @@
+package reporting
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// A Summary presents the aggregated results for notifications, with the
+// change in each metric since a previous snapshot when one is available
+type Summary struct {
+	// The totals across all users
+	Totals Totals
+	// The change in the totals since the previous snapshot, if any
+	Delta *Totals
+	// The highlights for each user, with the most commits first
+	Users []UserHighlight
+	// The progress toward each goal, if any
+	Goals []GoalProgress
+	// The year over year analysis, if any
+	Analysis *Analysis
+}
+
+// A UserHighlight holds the totals for a single user
+type UserHighlight struct {
+	User   string
+	Totals Totals
+	// The change in the user's totals since the previous snapshot, if any
+	Delta *Totals
+}
+
+// Summarize builds a summary of the current results, with deltas
+// against the previous results when they are not nil
+func Summarize(current AggregatedResults, previous *AggregatedResults) Summary {
+
+	summary := Summary{Totals: current.Totals(), Goals: current.Goals, Analysis: current.Analysis}
+	if previous != nil {
+		delta := current.Totals().Sub(previous.Totals())
+		summary.Delta = &delta
+	}
+
+	for _, user := range slices.Sorted(maps.Keys(current.ByUser)) {
+		highlight := UserHighlight{User: user, Totals: current.ByUser[user]}
+		if previous != nil {
+			delta := current.ByUser[user].Sub(previous.ByUser[user])
+			highlight.Delta = &delta
+		}
+		summary.Users = append(summary.Users, highlight)
+	}
+	slices.SortStableFunc(summary.Users, func(a, b UserHighlight) int {
+		return cmp.Compare(b.Totals.TotalCommitContributions, a.Totals.TotalCommitContributions)
+	})
+	return summary
+}
+
+// Text renders the summary as plain text, with a line per user
+func (s Summary) Text() string {
+
+	commits, repositories, other := metricDeltas(s.Delta)
+	var text strings.Builder
+	fmt.Fprintf(&text, "GitHub contributions summary\n\nCommits: %s\nRepositories: %s\nOther contributions: %s\n",
+		formatMetric(s.Totals.TotalCommitContributions, commits),
+		formatMetric(s.Totals.TotalRepositories, repositories),
+		formatMetric(s.Totals.TotalOtherContributions, other))
+
+	for _, user := range s.Users {
+		commits, repositories, other := metricDeltas(user.Delta)
+		fmt.Fprintf(&text, "\n%s: %s commits, %s repositories, %s other", user.User,
+			formatMetric(user.Totals.TotalCommitContributions, commits),
+			formatMetric(user.Totals.TotalRepositories, repositories),
+			formatMetric(user.Totals.TotalOtherContributions, other))
+	}
+
+	// Place the latest year among the earlier years
+	if s.Analysis != nil && s.Analysis.TotalCommitContributions.Percentile != nil {
+		byYear := s.Analysis.TotalCommitContributions.ByYear
+		fmt.Fprintf(&text, "\n\n%d against earlier years: commits in the %s percentile, "+
+			"repositories in the %s, other contributions in the %s", byYear[len(byYear)-1].Year,
+			ordinal(*s.Analysis.TotalCommitContributions.Percentile),
+			ordinal(*s.Analysis.TotalRepositories.Percentile),
+			ordinal(*s.Analysis.TotalOtherContributions.Percentile))
+	}
+
+	if len(s.Goals) > 0 {
+		text.WriteString("\n\nGoals:")
+		for _, goal := range s.Goals {
+			fmt.Fprintf(&text, "\n%s", goal)
+		}
+	}
+	return text.String()
+}
+
+// formatDelta formats a change in a metric with an explicit sign, like +4 or -2
+func formatDelta(delta int) string {
+	if delta > 0 {
+		return "+" + strconv.Itoa(delta)
+	}
+	if delta == 0 {
+		return "±0"
+	}
+	return strconv.Itoa(delta)
+}
+
+// formatMetric formats a metric value, followed by its delta in parentheses when available
+func formatMetric(value int, delta *int) string {
+	if delta == nil {
+		return strconv.Itoa(value)
+	}
+	return strconv.Itoa(value) + " (" + formatDelta(*delta) + ")"
+}
+
+// ordinal formats a number as an ordinal, like 1st, 22nd, or 80th
+func ordinal(number int) string {
+	suffix := "th"
+	switch {
+	case number%100 >= 11 && number%100 <= 13:
+	case number%10 == 1:
+		suffix = "st"
+	case number%10 == 2:
+		suffix = "nd"
+	case number%10 == 3:
+		suffix = "rd"
+	}
+	return strconv.Itoa(number) + suffix
+}
+
+// metricDeltas returns pointers to each metric of the delta, or nils without one
+func metricDeltas(delta *Totals) (commits *int, repositories *int, other *int) {
+	if delta == nil {
+		return nil, nil, nil
+	}
+	return &delta.TotalCommitContributions, &delta.TotalRepositories, &delta.TotalOtherContributions
+}
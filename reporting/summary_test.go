@@ -0,0 +1,39 @@
+package reporting_test
+
+import (
+	"testing"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the Summarize function
+func TestSummarize(t *testing.T) {
+	current := rpt.AggregatedResults{
+		TotalCommitContributions: 30,
+		ByUser: map[string]rpt.Totals{
+			"alice": {TotalCommitContributions: 10},
+			"bob":   {TotalCommitContributions: 20},
+		},
+	}
+	previous := rpt.AggregatedResults{
+		TotalCommitContributions: 25,
+		ByUser: map[string]rpt.Totals{
+			"alice": {TotalCommitContributions: 8},
+		},
+	}
+
+	// Ensure there are no deltas without a previous snapshot
+	summary := rpt.Summarize(current, nil)
+	assert.Nil(t, summary.Delta)
+	assert.Equal(t, 30, summary.Totals.TotalCommitContributions)
+
+	summary = rpt.Summarize(current, &previous)
+	assert.Equal(t, 5, summary.Delta.TotalCommitContributions)
+
+	// Ensure users are ordered by commits, with their own deltas
+	assert.Equal(t, "bob", summary.Users[0].User)
+	assert.Equal(t, 20, summary.Users[0].Delta.TotalCommitContributions)
+	assert.Equal(t, "alice", summary.Users[1].User)
+	assert.Equal(t, 2, summary.Users[1].Delta.TotalCommitContributions)
+}
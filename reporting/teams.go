@@ -0,0 +1,98 @@
+package reporting
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// A Teams publisher posts an Adaptive Card summarizing the results to a
+// Microsoft Teams incoming webhook or workflow
+type Teams struct {
+	// The webhook URL
+	WebhookURL string
+	// The results of the previous snapshot, used to report deltas
+	Previous *AggregatedResults
+	// The HTTP client used to post messages
+	Client *http.Client
+}
+
+// Constructs a new Teams object
+// The webhookURL is the Teams incoming webhook or workflow URL
+func NewTeams(webhookURL string) (teams *Teams, err error) {
+
+	if webhookURL == "" {
+		err = fmt.Errorf("the teams webhook URL cannot be blank")
+		return nil, err
+	}
+
+	return &Teams{
+		WebhookURL: webhookURL,
+		Client:     http.DefaultClient,
+	}, err
+}
+
+// Export posts the summary card with a fact per user to the webhook
+func (t *Teams) Export(queryResults map[string]QueryResult, aggregatedResults AggregatedResults) error {
+	message := t.message(Summarize(aggregatedResults, t.Previous), aggregatedResults.Timestamp)
+	return postJSON(t.Client, t.WebhookURL, message, "teams")
+}
+
+// message builds a webhook message with an Adaptive Card attachment
+func (t *Teams) message(summary Summary, timestamp int) map[string]any {
+
+	commits, repositories, other := metricDeltas(summary.Delta)
+	totals := []map[string]any{
+		{"title": "Commits", "value": formatMetric(summary.Totals.TotalCommitContributions, commits)},
+		{"title": "Repositories", "value": formatMetric(summary.Totals.TotalRepositories, repositories)},
+		{"title": "Other", "value": formatMetric(summary.Totals.TotalOtherContributions, other)},
+	}
+
+	users := make([]map[string]any, 0, len(summary.Users))
+	for _, user := range summary.Users {
+		commits, repositories, other := metricDeltas(user.Delta)
+		users = append(users, map[string]any{
+			"title": user.User,
+			"value": fmt.Sprintf("%s commits, %s repositories, %s other",
+				formatMetric(user.Totals.TotalCommitContributions, commits),
+				formatMetric(user.Totals.TotalRepositories, repositories),
+				formatMetric(user.Totals.TotalOtherContributions, other)),
+		})
+	}
+
+	body := []map[string]any{
+		{
+			"type":   "TextBlock",
+			"size":   "Large",
+			"weight": "Bolder",
+			"text":   "GitHub contributions summary",
+		},
+		{
+			"type":     "TextBlock",
+			"isSubtle": true,
+			"spacing":  "None",
+			"text":     time.Unix(int64(timestamp), 0).UTC().Format("Monday, January 2, 2006"),
+		},
+		{"type": "FactSet", "facts": totals},
+	}
+	if len(users) > 0 {
+		body = append(body, map[string]any{"type": "FactSet", "separator": true, "facts": users})
+	}
+
+	card := map[string]any{
+		"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+		"type":    "AdaptiveCard",
+		"version": "1.4",
+		"body":    body,
+	}
+
+	return map[string]any{
+		"type": "message",
+		"attachments": []map[string]any{
+			{
+				"contentType": "application/vnd.microsoft.card.adaptive",
+				"content":     card,
+			},
+		},
+	}
+}
@@ -0,0 +1,61 @@
+package reporting_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the NewTeams constructor
+func TestNewTeams(t *testing.T) {
+	_, err := rpt.NewTeams("")
+	assert.Error(t, err)
+}
+
+// Test the Teams Export method
+func TestTeamsExport(t *testing.T) {
+	var message struct {
+		Type        string
+		Attachments []struct {
+			ContentType string
+			Content     struct {
+				Type string
+				Body []struct {
+					Type  string
+					Facts []struct {
+						Title string
+						Value string
+					}
+				}
+			}
+		}
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&message)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	teams, err := rpt.NewTeams(server.URL)
+	assert.NoError(t, err)
+
+	results := rpt.AggregatedResults{
+		TotalCommitContributions: 10,
+		ByUser: map[string]rpt.Totals{
+			"user1": {TotalCommitContributions: 10},
+		},
+	}
+	err = teams.Export(nil, results)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "message", message.Type)
+	card := message.Attachments[0].Content
+	assert.Equal(t, "AdaptiveCard", card.Type)
+	assert.Len(t, card.Body, 4)
+	assert.Equal(t, "10", card.Body[2].Facts[0].Value)
+	assert.Equal(t, "user1", card.Body[3].Facts[0].Title)
+}
@@ -0,0 +1,49 @@
+package reporting
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// A Textfile writes the metrics of a run for the node_exporter textfile
+// collector, which suits cron-style runs on a host node_exporter scrapes
+type Textfile struct {
+	// The path of the metrics file, in the collector's directory
+	Path string
+}
+
+// Constructs a new Textfile object
+// The path must end in .prom, as the collector reads no other files
+func NewTextfile(path string) (textfile *Textfile, err error) {
+
+	if filepath.Ext(path) != ".prom" {
+		err = fmt.Errorf("the textfile path %q must end in .prom", path)
+		return nil, err
+	}
+
+	return &Textfile{Path: path}, err
+}
+
+// Export replaces the metrics file with the aggregated results. The file is
+// renamed into place so the collector never reads a partial file.
+func (t *Textfile) Export(queryResults map[string]QueryResult, aggregatedResults AggregatedResults) error {
+
+	var body bytes.Buffer
+	err := aggregatedResults.WriteMetrics(&body)
+	if err != nil {
+		return err
+	}
+	err = WriteFileAtomically(t.Path, body.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to write the textfile: %w", err)
+	}
+
+	// Let node_exporter read the file when it runs as another user
+	err = os.Chmod(t.Path, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to write the textfile: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package reporting_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test the NewTextfile constructor
+func TestNewTextfile(t *testing.T) {
+	_, err := rpt.NewTextfile("")
+	assert.Error(t, err)
+	_, err = rpt.NewTextfile("/var/lib/node_exporter/ghcontributions.txt")
+	assert.Error(t, err)
+
+	textfile, err := rpt.NewTextfile("/var/lib/node_exporter/ghcontributions.prom")
+	assert.NoError(t, err)
+	assert.Equal(t, "/var/lib/node_exporter/ghcontributions.prom", textfile.Path)
+}
+
+// Test writing the metrics file
+func TestTextfileExport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ghcontributions.prom")
+	textfile, err := rpt.NewTextfile(path)
+	require.NoError(t, err)
+
+	// Ensure a later run replaces the file
+	require.NoError(t, os.WriteFile(path, []byte("stale\n"), 0600))
+	err = textfile.Export(nil, rpt.AggregatedResults{Timestamp: 1700000000, TotalCommitContributions: 42})
+	require.NoError(t, err)
+
+	contents, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(contents), "# TYPE ghcontributions_total_commit_contributions gauge\n")
+	assert.Contains(t, string(contents), "ghcontributions_total_commit_contributions 42\n")
+	assert.Contains(t, string(contents), "ghcontributions_last_run_timestamp_seconds 1700000000\n")
+	assert.NotContains(t, string(contents), "stale")
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0644), info.Mode().Perm())
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
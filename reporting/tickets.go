@@ -0,0 +1,144 @@
+package reporting
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// The default pattern of issue keys, like Jira's PROJ-123
+const DefaultTicketPattern = `\b[A-Z][A-Z0-9_]+-[1-9][0-9]*\b`
+
+// A PullRequest holds the text of a pull request a user contributed
+type PullRequest struct {
+	Repository string    `json:"repository"`
+	Title      string    `json:"title"`
+	Body       string    `json:"body"`
+	URL        string    `json:"url"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// A PullRequestSource lists the pull requests a user opened, for providers
+// that can enrich contributions with linked issue keys
+type PullRequestSource interface {
+	PullRequests(ctx context.Context, user string, dateRange DateRange) ([]PullRequest, error)
+}
+
+// A TicketProject counts the pull requests that reference issues in a single
+// issue tracker project, like the PROJ in PROJ-123
+type TicketProject struct {
+	// The count of pull requests referencing the project at least once
+	PullRequests int `json:"pullRequests"`
+	// The issue keys referenced, sorted
+	Keys []string `json:"keys"`
+}
+
+// A pullRequestsQuery lists a page of the user's pull request contributions
+type pullRequestsQuery struct {
+	User struct {
+		ContributionsCollection struct {
+			PullRequestContributions struct {
+				Nodes []struct {
+					PullRequest struct {
+						Title      githubv4.String
+						Body       githubv4.String
+						URL        githubv4.String
+						CreatedAt  githubv4.DateTime
+						Repository struct {
+							NameWithOwner githubv4.String
+						}
+					}
+				}
+				PageInfo struct {
+					HasNextPage githubv4.Boolean
+					EndCursor   githubv4.String
+				}
+			} `graphql:"pullRequestContributions(first: 100, after: $cursor)"`
+		} `graphql:"contributionsCollection(from: $from, to: $to)"`
+	} `graphql:"user(login: $login)"`
+}
+
+// PullRequests lists the pull requests the user opened, a year at a time
+func (g *GitHub) PullRequests(ctx context.Context, user string, dateRange DateRange) ([]PullRequest, error) {
+
+	var pullRequests []PullRequest
+	for year := dateRange.From.Year(); year <= dateRange.To.Add(-time.Second).Year(); year++ {
+
+		// Query whole years, clipped to the range
+		from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		if from.Before(dateRange.From) {
+			from = dateRange.From
+		}
+		to := time.Date(year+1, time.January, 1, 0, 0, 0, 0, time.UTC)
+		if to.After(dateRange.To) {
+			to = dateRange.To
+		}
+
+		var variables = map[string]interface{}{
+			"login":  githubv4.String(user),
+			"from":   githubv4.DateTime{Time: from},
+			"to":     githubv4.DateTime{Time: to.Add(-time.Second)},
+			"cursor": (*githubv4.String)(nil),
+		}
+		for {
+			var query pullRequestsQuery
+			err := g.Client.Query(ctx, &query, variables)
+			if err != nil {
+				return pullRequests, fmt.Errorf("failed to query github for pull requests: %w", err)
+			}
+			contributions := query.User.ContributionsCollection.PullRequestContributions
+			for _, node := range contributions.Nodes {
+				pullRequests = append(pullRequests, PullRequest{
+					Repository: string(node.PullRequest.Repository.NameWithOwner),
+					Title:      string(node.PullRequest.Title),
+					Body:       string(node.PullRequest.Body),
+					URL:        string(node.PullRequest.URL),
+					CreatedAt:  node.PullRequest.CreatedAt.Time,
+				})
+			}
+			if !contributions.PageInfo.HasNextPage {
+				break
+			}
+			variables["cursor"] = githubv4.NewString(contributions.PageInfo.EndCursor)
+		}
+	}
+	return pullRequests, nil
+}
+
+// GroupByTicketProject finds the issue keys in each pull request's title and body,
+// and groups the pull requests by the project part of the keys
+func GroupByTicketProject(pullRequests []PullRequest, pattern *regexp.Regexp) map[string]TicketProject {
+
+	var pullRequestCounts = make(map[string]int)
+	var keys = make(map[string]map[string]bool)
+	for _, pullRequest := range pullRequests {
+		// Count each pull request once per project, however many keys it has
+		var projects = make(map[string]bool)
+		for _, key := range pattern.FindAllString(pullRequest.Title+"\n"+pullRequest.Body, -1) {
+			project := key[:strings.LastIndex(key, "-")]
+			projects[project] = true
+			if keys[project] == nil {
+				keys[project] = make(map[string]bool)
+			}
+			keys[project][key] = true
+		}
+		for project := range projects {
+			pullRequestCounts[project]++
+		}
+	}
+
+	var ticketProjects = make(map[string]TicketProject)
+	for project, count := range pullRequestCounts {
+		ticketProjects[project] = TicketProject{
+			PullRequests: count,
+			Keys:         slices.Sorted(maps.Keys(keys[project])),
+		}
+	}
+	return ticketProjects
+}
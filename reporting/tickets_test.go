@@ -0,0 +1,83 @@
+package reporting_test
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"regexp"
+	"slices"
+	"testing"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+)
+
+// A fake GraphQL client that decodes canned JSON pages into each query, in order
+type pagedGraphQLClient struct {
+	Pages     []string
+	Variables []map[string]interface{}
+}
+
+// Query decodes the next page into the query
+func (p *pagedGraphQLClient) Query(ctx context.Context, q interface{}, variables map[string]interface{}) error {
+	copied := make(map[string]interface{})
+	for key, value := range variables {
+		copied[key] = value
+	}
+	p.Variables = append(p.Variables, copied)
+	page := p.Pages[0]
+	p.Pages = p.Pages[1:]
+	return json.Unmarshal([]byte(page), q)
+}
+
+// Test listing pull requests with the GitHub provider
+func TestGitHubPullRequests(t *testing.T) {
+	client := &pagedGraphQLClient{Pages: []string{
+		`{"user": {"contributionsCollection": {"pullRequestContributions": {
+			"nodes": [{"pullRequest": {"title": "PROJ-1 Fix", "body": "", "url": "https://github.com/o/r/pull/1",
+				"createdAt": "2023-02-01T00:00:00Z", "repository": {"nameWithOwner": "o/r"}}}],
+			"pageInfo": {"hasNextPage": true, "endCursor": "c1"}}}}}`,
+		`{"user": {"contributionsCollection": {"pullRequestContributions": {
+			"nodes": [{"pullRequest": {"title": "Tidy", "body": "Part of OPS-7", "url": "https://github.com/o/r/pull/2",
+				"createdAt": "2023-03-01T00:00:00Z", "repository": {"nameWithOwner": "o/r"}}}],
+			"pageInfo": {"hasNextPage": false}}}}}`,
+		`{"user": {"contributionsCollection": {"pullRequestContributions": {"nodes": [], "pageInfo": {}}}}}`,
+	}}
+
+	gitHub, err := rpt.NewGitHub(client)
+	assert.NoError(t, err)
+	pullRequests, err := gitHub.PullRequests(context.Background(), "user1", rpt.YearRange(2023, 2024))
+	assert.NoError(t, err)
+
+	assert.Len(t, pullRequests, 2)
+	assert.Equal(t, "o/r", pullRequests[0].Repository)
+	assert.Equal(t, "Part of OPS-7", pullRequests[1].Body)
+
+	// Ensure the second page continues from the cursor, and each year starts over
+	assert.Len(t, client.Variables, 3)
+	assert.Nil(t, client.Variables[0]["cursor"])
+	assert.Equal(t, githubv4.NewString("c1"), client.Variables[1]["cursor"])
+	assert.Equal(t, 2024, client.Variables[2]["from"].(githubv4.DateTime).Year())
+}
+
+// Test grouping pull requests by the issue tracker projects they reference
+func TestGroupByTicketProject(t *testing.T) {
+	pullRequests := []rpt.PullRequest{
+		{Title: "PROJ-12: Add the exporter", Body: "Also fixes PROJ-9 and relates to OPS-3"},
+		{Title: "Fix the build", Body: "See https://jira.example.com/browse/PROJ-12"},
+		{Title: "Update the docs", Body: "No ticket, just cleanup"},
+	}
+
+	ticketProjects := rpt.GroupByTicketProject(pullRequests, regexp.MustCompile(rpt.DefaultTicketPattern))
+	assert.Equal(t, map[string]rpt.TicketProject{
+		"PROJ": {PullRequests: 2, Keys: []string{"PROJ-12", "PROJ-9"}},
+		"OPS":  {PullRequests: 1, Keys: []string{"OPS-3"}},
+	}, ticketProjects)
+
+	// Ensure a narrower pattern skips look-alikes such as UTF-8
+	pullRequests = append(pullRequests, rpt.PullRequest{Title: "OPS-4 Convert to UTF-8"})
+	ticketProjects = rpt.GroupByTicketProject(pullRequests, regexp.MustCompile(`\b(PROJ|OPS)-[0-9]+\b`))
+	assert.Equal(t, []string{"OPS", "PROJ"}, slices.Sorted(maps.Keys(ticketProjects)))
+	assert.Equal(t, 2, ticketProjects["OPS"].PullRequests)
+}
@@ -0,0 +1,48 @@
+package reporting
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// A HeaderTransport sets a User-Agent and extra headers on each request, like
+// the attribution and routing headers a GitHub Enterprise gateway requires
+type HeaderTransport struct {
+	// The transport that sends the requests, or http.DefaultTransport if nil
+	Base http.RoundTripper
+	// The User-Agent header, or Go's default if empty
+	UserAgent string
+	// The extra headers by name
+	Headers map[string]string
+}
+
+// Constructs a new HeaderTransport object
+// The base is the transport that sends the requests, or nil for http.DefaultTransport
+func NewHeaderTransport(base http.RoundTripper, userAgent string, headers map[string]string) (transport *HeaderTransport, err error) {
+
+	for name := range headers {
+		if name == "" {
+			return nil, fmt.Errorf("the header names cannot be empty")
+		}
+	}
+	return &HeaderTransport{Base: base, UserAgent: userAgent, Headers: headers}, nil
+}
+
+// RoundTrip sends a copy of the request with the headers set
+func (h *HeaderTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+
+	// A RoundTripper must not modify the caller's request
+	request = request.Clone(request.Context())
+	for name, value := range h.Headers {
+		request.Header.Set(name, value)
+	}
+	if h.UserAgent != "" {
+		request.Header.Set("User-Agent", h.UserAgent)
+	}
+
+	base := h.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(request)
+}
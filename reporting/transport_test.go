@@ -0,0 +1,52 @@
+package reporting_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test the NewHeaderTransport constructor
+func TestNewHeaderTransport(t *testing.T) {
+	_, err := rpt.NewHeaderTransport(nil, "", map[string]string{"": "value"})
+	assert.Error(t, err)
+
+	transport, err := rpt.NewHeaderTransport(nil, "ghcontributions/1.0", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "ghcontributions/1.0", transport.UserAgent)
+}
+
+// Test setting the User-Agent and extra headers on requests
+func TestHeaderTransportRoundTrip(t *testing.T) {
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+	}))
+	defer server.Close()
+
+	transport, err := rpt.NewHeaderTransport(nil, "ghcontributions/1.0", map[string]string{
+		"X-Team":  "platform",
+		"X-Route": "ghes",
+	})
+	require.NoError(t, err)
+	client := &http.Client{Transport: transport}
+
+	request, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	request.Header.Set("X-Route", "default")
+	response, err := client.Do(request)
+	require.NoError(t, err)
+	response.Body.Close()
+
+	assert.Equal(t, "ghcontributions/1.0", header.Get("User-Agent"))
+	assert.Equal(t, "platform", header.Get("X-Team"))
+	assert.Equal(t, "ghes", header.Get("X-Route"))
+
+	// Ensure the caller's request is left unchanged
+	assert.Equal(t, "default", request.Header.Get("X-Route"))
+	assert.Empty(t, request.Header.Get("X-Team"))
+}
@@ -0,0 +1,107 @@
+package reporting
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// The scopes a classic GitHub token needs by default, repo to count contributions
+// to private repositories and read:org to list private organization memberships
+var DefaultGitHubScopes = []string{"repo", "read:org"}
+
+// The scopes granted along with a broader scope, as GitHub lists only the broader one
+var impliedGitHubScopes = map[string][]string{
+	"repo":      {"repo:status", "repo_deployment", "public_repo", "repo:invite", "security_events"},
+	"admin:org": {"write:org", "read:org"},
+	"write:org": {"read:org"},
+	"user":      {"read:user", "user:email", "user:follow"},
+}
+
+// A TokenValidation is the result of checking a credential's token
+type TokenValidation struct {
+	// The username the credential is configured with
+	Username string `json:"username"`
+	// The login the token authenticates as
+	Login string `json:"login,omitempty"`
+	// The scopes of a classic token, or none for fine-grained tokens, which don't list them
+	Scopes []string `json:"scopes,omitempty"`
+	// What keeps the token from collecting the user, empty when it can
+	Problems []string `json:"problems,omitempty"`
+}
+
+// Valid reports whether the token can collect the user
+func (t TokenValidation) Valid() bool {
+	return len(t.Problems) == 0
+}
+
+// hasScope reports whether the granted scopes include the scope, directly or by
+// a broader scope implying it
+func hasScope(granted []string, scope string) bool {
+	for _, grant := range granted {
+		if grant == scope || slices.Contains(impliedGitHubScopes[grant], scope) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateToken checks that the token works, that it authenticates as the username,
+// compared without case like GitHub logins, and that a classic token has the scopes.
+// Fine-grained tokens don't list their permissions, so their scopes aren't checked.
+func (g *GitHub) ValidateToken(ctx context.Context, username string, scopes []string) (TokenValidation, error) {
+
+	validation := TokenValidation{Username: username}
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, g.RESTURL+"/user", nil)
+	if err != nil {
+		return validation, err
+	}
+	request.Header.Set("Accept", "application/vnd.github+json")
+
+	response, err := g.HTTPClient.Do(request)
+	if err != nil {
+		return validation, fmt.Errorf("failed to validate the token of %s: %w", username, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusUnauthorized {
+		validation.Problems = append(validation.Problems, "the token is invalid or expired")
+		return validation, nil
+	}
+	if response.StatusCode/100 != 2 {
+		return validation, fmt.Errorf("failed to validate the token of %s: github returned %s", username, response.Status)
+	}
+
+	var user struct {
+		Login string `json:"login"`
+	}
+	err = json.NewDecoder(response.Body).Decode(&user)
+	if err != nil {
+		return validation, fmt.Errorf("failed to parse the github user: %w", err)
+	}
+	validation.Login = user.Login
+	if !strings.EqualFold(user.Login, username) {
+		validation.Problems = append(validation.Problems,
+			fmt.Sprintf("the token belongs to %s rather than %s", user.Login, username))
+	}
+
+	// Only classic tokens send the header, even when they have no scopes
+	header, classic := response.Header["X-Oauth-Scopes"]
+	if !classic {
+		return validation, nil
+	}
+	for _, scope := range strings.Split(strings.Join(header, ","), ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			validation.Scopes = append(validation.Scopes, scope)
+		}
+	}
+	for _, scope := range scopes {
+		if !hasScope(validation.Scopes, scope) {
+			validation.Problems = append(validation.Problems, "the token is missing the "+scope+" scope")
+		}
+	}
+	return validation, nil
+}
@@ -0,0 +1,68 @@
+package reporting_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test checking the login and scopes of GitHub tokens
+func TestGitHubValidateToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/user", r.URL.Path)
+		switch r.Header.Get("Authorization") {
+		case "classic":
+			w.Header().Set("X-OAuth-Scopes", "admin:org, repo")
+		case "narrow":
+			w.Header().Set("X-OAuth-Scopes", "public_repo")
+		case "expired":
+			http.Error(w, `{"message": "Bad credentials"}`, http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `{"login": "User1", "id": 1}`)
+	}))
+	defer server.Close()
+
+	gitHub, err := rpt.NewGitHub(&MockGraphQLClient{})
+	require.NoError(t, err)
+	gitHub.RESTURL = server.URL
+	validate := func(token string, username string) rpt.TokenValidation {
+		gitHub.HTTPClient = &http.Client{Transport: authorizationTransport(token)}
+		validation, err := gitHub.ValidateToken(context.Background(), username, rpt.DefaultGitHubScopes)
+		require.NoError(t, err)
+		return validation
+	}
+
+	// Ensure logins are compared without case, and broader scopes imply narrower ones
+	validation := validate("classic", "user1")
+	assert.True(t, validation.Valid(), validation.Problems)
+	assert.Equal(t, []string{"admin:org", "repo"}, validation.Scopes)
+
+	validation = validate("narrow", "user1")
+	assert.Equal(t, []string{"the token is missing the repo scope", "the token is missing the read:org scope"},
+		validation.Problems)
+
+	// Ensure fine-grained tokens, which send no scopes, are only checked for their login
+	validation = validate("fine-grained", "user2")
+	assert.Equal(t, "User1", validation.Login)
+	assert.Equal(t, []string{"the token belongs to User1 rather than user2"}, validation.Problems)
+
+	validation = validate("expired", "user1")
+	assert.False(t, validation.Valid())
+}
+
+// An authorizationTransport sets the Authorization header of each request
+type authorizationTransport string
+
+// RoundTrip sends the request with the Authorization header
+func (a authorizationTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	request = request.Clone(request.Context())
+	request.Header.Set("Authorization", string(a))
+	return http.DefaultTransport.RoundTrip(request)
+}
@@ -0,0 +1,103 @@
+package reporting
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// A Vault reads credentials from a HashiCorp Vault key/value secret, so rotated
+// tokens are read where they're kept rather than from a file
+type Vault struct {
+	// The Vault server, like https://vault.example.com:8200
+	Address string
+	// The Vault token authenticating the requests
+	Token string
+	// The Vault Enterprise namespace of the secret, if any
+	Namespace string
+	// The HTTP client used for the API requests
+	Client *http.Client
+}
+
+// Constructs a new Vault object
+// The address is the Vault server, like the VAULT_ADDR variable
+// The token authenticates the requests, like the VAULT_TOKEN variable
+func NewVault(address string, token string) (vault *Vault, err error) {
+
+	if address == "" {
+		err = fmt.Errorf("the vault address cannot be blank")
+		return nil, err
+	}
+	if token == "" {
+		err = fmt.Errorf("the vault token cannot be blank")
+		return nil, err
+	}
+
+	return &Vault{
+		Address: strings.TrimSuffix(address, "/"),
+		Token:   token,
+		Client:  http.DefaultClient,
+	}, err
+}
+
+// Credentials reads the secret at the path, like secret/data/ghtokens for version 2
+// of the key/value engine or secret/ghtokens for version 1. Each key of the secret
+// is a GitHub username and its value the token, and they're sorted by username.
+func (v *Vault) Credentials(ctx context.Context, path string) (Credentials, error) {
+
+	requestURL := v.Address + "/v1/" + strings.Trim(path, "/")
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("X-Vault-Token", v.Token)
+	if v.Namespace != "" {
+		request.Header.Set("X-Vault-Namespace", v.Namespace)
+	}
+
+	response, err := v.Client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the vault secret %s: %w", path, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("failed to read the vault secret %s: vault returned %s", path, response.Status)
+	}
+
+	// Version 2 secrets nest their data along with its metadata
+	var secret struct {
+		Data map[string]json.RawMessage `json:"data"`
+	}
+	err = json.NewDecoder(response.Body).Decode(&secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the vault secret %s: %w", path, err)
+	}
+	data := secret.Data
+	if _, found := data["metadata"]; found {
+		versioned := data["data"]
+		data = nil
+		err = json.Unmarshal(versioned, &data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the vault secret %s: %w", path, err)
+		}
+	}
+
+	var credentials Credentials
+	for _, username := range slices.Sorted(maps.Keys(data)) {
+		var token string
+		err = json.Unmarshal(data[username], &token)
+		if err != nil {
+			return nil, fmt.Errorf("the token of %s in the vault secret %s is not a string", username, path)
+		}
+		credentials = append(credentials, Credential{Username: username, Token: token})
+	}
+	if len(credentials) == 0 {
+		return nil, fmt.Errorf("the vault secret %s has no credentials", path)
+	}
+	return credentials, nil
+}
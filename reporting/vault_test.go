@@ -0,0 +1,65 @@
+package reporting_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	rpt "github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test the NewVault constructor
+func TestNewVault(t *testing.T) {
+	_, err := rpt.NewVault("", "token")
+	assert.Error(t, err)
+	_, err = rpt.NewVault("https://vault.example.com:8200", "")
+	assert.Error(t, err)
+
+	vault, err := rpt.NewVault("https://vault.example.com:8200/", "token")
+	require.NoError(t, err)
+	assert.Equal(t, "https://vault.example.com:8200", vault.Address)
+}
+
+// Test reading credentials from version 1 and 2 key/value secrets
+func TestVaultCredentials(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "vault-token", r.Header.Get("X-Vault-Token"))
+		assert.Equal(t, "team", r.Header.Get("X-Vault-Namespace"))
+		switch r.URL.Path {
+		case "/v1/secret/data/ghtokens":
+			w.Write([]byte(`{"data": {
+				"data": {"user2": "token2", "user1": "token1"},
+				"metadata": {"version": 3}}}`))
+		case "/v1/kv/ghtokens":
+			w.Write([]byte(`{"data": {"user1": "token1"}}`))
+		case "/v1/kv/numbers":
+			w.Write([]byte(`{"data": {"user1": 1}}`))
+		default:
+			http.Error(w, `{"errors": []}`, http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	vault, err := rpt.NewVault(server.URL, "vault-token")
+	require.NoError(t, err)
+	vault.Namespace = "team"
+
+	credentials, err := vault.Credentials(context.Background(), "secret/data/ghtokens")
+	require.NoError(t, err)
+	assert.Equal(t, rpt.Credentials{
+		{Username: "user1", Token: "token1"},
+		{Username: "user2", Token: "token2"},
+	}, credentials)
+
+	credentials, err = vault.Credentials(context.Background(), "/kv/ghtokens")
+	require.NoError(t, err)
+	assert.Equal(t, rpt.Credentials{{Username: "user1", Token: "token1"}}, credentials)
+
+	_, err = vault.Credentials(context.Background(), "kv/numbers")
+	assert.Error(t, err)
+	_, err = vault.Credentials(context.Background(), "kv/missing")
+	assert.ErrorContains(t, err, "404")
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/christopher-s-jones/ghcontributions/reporting"
+)
+
+// resume runs the resume subcommand, collecting the contributions of each
+// employment period and printing a summary per job
+func resume(ctx context.Context, args []string) {
+
+	flags := flag.NewFlagSet("resume", flag.ExitOnError)
+	periodsPath := flags.String("periods", "", "The path of a JSON file listing employment periods, like \n{\"label\": \"Acme Corp\", \"period\": \"2016..2019\"}.")
+	credentialsFilePath := flags.String("credentials", "gh-tokens.json", "The name of the file containing Github usernames \nand API token values")
+	encrypted := flags.Bool("encrypted", false, "Whether the credentials file is PGP encrypted.")
+	passphraseFile := flags.String("passphrase-file", "", "The path of a file holding the passphrase of the encrypted \ncredentials file, instead of prompting for it.")
+	useGHAuth := flags.Bool("use-gh-auth", false, "Whether to read the tokens of the GitHub CLI's accounts, \nlike -credentials gh:, instead of the credentials file.")
+	user := flags.String("user", "", "A GitHub username to report on with the GITHUB_TOKEN \nvariable, instead of the credentials file.")
+	users := flags.String("users", "", "Comma separated GitHub usernames to report on with one \nshared token, GITHUB_TOKEN or the credentials file's first.")
+	usersFilePath := flags.String("users-file", "", "The path of a file listing a GitHub username on each line, \nreported on like -users.")
+	apiURL := flags.String("api-url", "", "The base URL of a GitHub Enterprise Server, like https://ghe.example.com, \nfor the GitHub credentials without a url of their own.")
+	userAgent := flags.String("user-agent", "", "The User-Agent header sent with provider API requests.")
+	headers := flags.String("headers", "", "Comma separated Name=Value headers added to \nprovider API requests.")
+	includeRestricted := flags.Bool("include-restricted", false, "Whether to count the anonymized contributions to private \nGitHub repositories among the other contributions.")
+	asJSON := flags.Bool("json", false, "Whether to print the summary as JSON.")
+	flags.Usage = func() {
+		fmt.Println("Summarize contributions for each employment period")
+		fmt.Println("\nUsage:")
+		fmt.Printf(" %s resume -periods jobs.json [options]\n\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if *useGHAuth {
+		*credentialsFilePath = ghAuthCredentials
+	}
+
+	if *periodsPath == "" {
+		flags.Usage()
+		log.Fatalf("The resume subcommand requires a -periods file")
+	}
+	periodsJSON, err := os.ReadFile(*periodsPath)
+	if err != nil {
+		log.Fatalf("Couldn't read the employment periods: %s", err)
+	}
+	periods, err := reporting.ParseEmploymentPeriods(periodsJSON, time.Now())
+	if err != nil {
+		flags.Usage()
+		log.Fatalf("Couldn't load the employment periods: %s", err)
+	}
+
+	usernames, err := listUsers(*user, *users, *usersFilePath)
+	if err != nil {
+		flags.Usage()
+		log.Fatalf("Couldn't list the users: %s", err)
+	}
+	credentials, err := loadCredentials(*credentialsFilePath, *encrypted, *passphraseFile, usernames, *apiURL)
+	if err != nil {
+		flags.Usage()
+		log.Fatalf("Couldn't load the credentials: %s", err)
+	}
+	httpClient, _, err := newHTTPClient(*userAgent, *headers)
+	if err != nil {
+		flags.Usage()
+		log.Fatalf("Couldn't configure the API requests: %s", err)
+	}
+
+	var queryResults []map[string]reporting.QueryResult
+	for _, period := range periods {
+		queryResults = append(queryResults, collectPeriod(ctx, *credentials, httpClient, period.Range))
+	}
+	reporter := reporting.Reporter{IncludeRestricted: *includeRestricted}
+	entries := reporter.Resume(periods, queryResults)
+
+	if !*asJSON {
+		fmt.Print(reporting.ResumeText(entries))
+		return
+	}
+	entriesJSON, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		log.Fatalf("Couldn't report the summary: %s", err)
+	}
+	fmt.Println(string(entriesJSON))
+}
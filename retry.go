@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/christopher-s-jones/ghcontributions/reporting"
+)
+
+// retryFailed runs the retry-failed subcommand, collecting the user-years listed
+// in the errors of a report again and merging them into the report
+func retryFailed(ctx context.Context, args []string) {
+
+	flags := flag.NewFlagSet("retry-failed", flag.ExitOnError)
+	credentialsFilePath := flags.String("credentials", "gh-tokens.json", "The name of the file containing Github usernames \nand API token values")
+	encrypted := flags.Bool("encrypted", false, "Whether the credentials file is PGP encrypted.")
+	passphraseFile := flags.String("passphrase-file", "", "The path of a file holding the passphrase of the encrypted \ncredentials file, instead of prompting for it.")
+	useGHAuth := flags.Bool("use-gh-auth", false, "Whether to read the tokens of the GitHub CLI's accounts, \nlike -credentials gh:, instead of the credentials file.")
+	user := flags.String("user", "", "A GitHub username to report on with the GITHUB_TOKEN \nvariable, instead of the credentials file.")
+	users := flags.String("users", "", "Comma separated GitHub usernames to report on with one \nshared token, GITHUB_TOKEN or the credentials file's first.")
+	usersFilePath := flags.String("users-file", "", "The path of a file listing a GitHub username on each line, \nreported on like -users.")
+	apiURL := flags.String("api-url", "", "The base URL of a GitHub Enterprise Server, like https://ghe.example.com, \nfor the GitHub credentials without a url of their own.")
+	userAgent := flags.String("user-agent", "", "The User-Agent header sent with provider API requests.")
+	headers := flags.String("headers", "", "Comma separated Name=Value headers added to \nprovider API requests.")
+	flags.Usage = func() {
+		fmt.Println("Retry the failed collections of a report")
+		fmt.Println("\nUsage:")
+		fmt.Printf(" %s retry-failed report.json [options]\n\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+
+	// Allow the options before or after the report path
+	flags.Parse(args)
+	reportPath := flags.Arg(0)
+	flags.Parse(flags.Args()[min(1, flags.NArg()):])
+	if *useGHAuth {
+		*credentialsFilePath = ghAuthCredentials
+	}
+	if reportPath == "" {
+		flags.Usage()
+		log.Fatalf("The retry-failed subcommand requires a report file")
+	}
+
+	report, err := readReport(reportPath)
+	if err != nil {
+		log.Fatalf("Couldn't load the report: %s", err)
+	}
+	if len(report.Errors) == 0 {
+		slog.Info("The report has no failed collections to retry")
+		return
+	}
+
+	usernames, err := listUsers(*user, *users, *usersFilePath)
+	if err != nil {
+		flags.Usage()
+		log.Fatalf("Couldn't list the users: %s", err)
+	}
+	credentials, err := loadCredentials(*credentialsFilePath, *encrypted, *passphraseFile, usernames, *apiURL)
+	if err != nil {
+		flags.Usage()
+		log.Fatalf("Couldn't load the credentials: %s", err)
+	}
+	httpClient, stats, err := newHTTPClient(*userAgent, *headers)
+	if err != nil {
+		flags.Usage()
+		log.Fatalf("Couldn't configure the API requests: %s", err)
+	}
+
+	// Collect each failed user-year on its own, keeping the errors that happen again,
+	// in the report's fiscal years when it has them
+	fiscalYearStart := time.Month(report.FiscalYearStart)
+	runStart := time.Now()
+	var queryResults = make(map[string]reporting.QueryResult)
+	var collectionErrors []reporting.CollectionError
+	for _, collectionError := range report.Errors {
+		credential, found := findCredential(*credentials, collectionError)
+		if !found {
+			slog.Warn("Couldn't find a credential to retry", "user", collectionError.User)
+			collectionErrors = append(collectionErrors, collectionError)
+			continue
+		}
+		provider, err := newProvider(credential, httpClient)
+		if err != nil {
+			log.Fatalf("Couldn't create a provider for %s: %s", credential.Username, err)
+		}
+		if gitHub, ok := provider.(*reporting.GitHub); ok {
+			gitHub.FiscalYearStart = fiscalYearStart
+		}
+		for _, userYear := range collectionError.UserYears {
+			year, err := strconv.Atoi(userYear[strings.LastIndex(userYear, "-")+1:])
+			if err != nil {
+				log.Fatalf("Couldn't parse the user-year %s: %s", userYear, err)
+			}
+			collector := &reporting.ProviderCollector{Provider: provider, User: credential.Username,
+				Range: reporting.FiscalYearRange(year, year, fiscalYearStart), FiscalYearStart: fiscalYearStart}
+			userQueryResults, err := collector.Collect(ctx)
+			if err != nil {
+				slog.Error("Couldn't collect", "user", credential.Username, "year", year, "error", err)
+				collectionErrors = append(collectionErrors, reporting.NewCollectionError(credential,
+					collector.Range, fiscalYearStart, userQueryResults, err))
+				continue
+			}
+			reporting.MergeQueryResults(queryResults, userQueryResults)
+		}
+	}
+
+	// After an interrupt, still keep the user-years retried so far
+	if ctx.Err() != nil {
+		slog.Warn("Interrupted, keeping the user-years retried so far")
+	}
+	retried := len(report.Errors)
+	err = report.Merge(context.WithoutCancel(ctx), queryResults, collectionErrors)
+	if err != nil {
+		log.Fatalf("Couldn't aggregate the results: %s", err)
+	}
+	runStats := stats.RunStats(time.Since(runStart))
+	report.RunStats = &runStats
+	err = writeReport(reportPath, *report)
+	if err != nil {
+		log.Fatalf("Couldn't write the report: %s", err)
+	}
+	slog.Info("Retried the failed collections", "retried", retried, "failing", len(collectionErrors))
+}
+
+// findCredential finds the credential the collection failed with, of the same
+// user on the same provider and instance
+func findCredential(credentials reporting.Credentials, collectionError reporting.CollectionError) (reporting.Credential, bool) {
+	for _, credential := range credentials {
+		if credential.Username == collectionError.User && credential.Provider == collectionError.Provider &&
+			credential.Host() == collectionError.Host {
+			return credential, true
+		}
+	}
+	return reporting.Credential{}, false
+}
+
+// isCompleteReport reports whether the file holds a report without any failed collections
+func isCompleteReport(path string) bool {
+	report, err := readReport(path)
+	return err == nil && len(report.Errors) == 0
+}
+
+// readReport reads a report written by writeReport
+func readReport(path string) (*reporting.Report, error) {
+	reportJSON, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the report: %w", err)
+	}
+	var report reporting.Report
+	err = json.Unmarshal(reportJSON, &report)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the report: %w", err)
+	}
+	return &report, nil
+}
+
+// writeReport writes the report as indented JSON
+func writeReport(path string, report reporting.Report) error {
+	reportJSON, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	return reporting.WriteFileAtomically(path, reportJSON)
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/christopher-s-jones/ghcontributions/reporting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test finding the credential a collection failed with among those of the same login
+func TestFindCredential(t *testing.T) {
+	credentials := reporting.Credentials{
+		{Username: "user1", Token: "public"},
+		{Username: "user1", Token: "enterprise", URL: "https://ghe.example.com"},
+	}
+
+	credential, found := findCredential(credentials, reporting.CollectionError{User: "user1", Host: "ghe.example.com"})
+	assert.True(t, found)
+	assert.Equal(t, "enterprise", credential.Token)
+
+	credential, found = findCredential(credentials, reporting.CollectionError{User: "user1"})
+	assert.True(t, found)
+	assert.Equal(t, "public", credential.Token)
+
+	_, found = findCredential(credentials, reporting.CollectionError{User: "user1", Host: "other.example.com"})
+	assert.False(t, found)
+}
+
+// Test telling a complete report from a partial or missing one
+func TestIsCompleteReport(t *testing.T) {
+	dir := t.TempDir()
+	complete := filepath.Join(dir, "complete.json")
+	require.NoError(t, writeReport(complete, reporting.Report{}))
+	assert.True(t, isCompleteReport(complete))
+
+	partial := filepath.Join(dir, "partial.json")
+	require.NoError(t, writeReport(partial, reporting.Report{AggregatedResults: reporting.AggregatedResults{
+		Errors: []reporting.CollectionError{{User: "user1", Error: "interrupted"}},
+	}}))
+	assert.False(t, isCompleteReport(partial))
+	assert.False(t, isCompleteReport(filepath.Join(dir, "missing.json")))
+}
+
+// Test the status passed to the -post-report hook
+func TestRunPostReportHook(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "status.txt")
+	config := Configuration{postReportHook: `echo "$GHCONTRIBUTIONS_STATUS" > ` + output}
+	status := func() string {
+		written, err := os.ReadFile(output)
+		require.NoError(t, err)
+		return string(written)
+	}
+
+	runPostReportHook(context.Background(), config, map[string]string{}, reporting.AggregatedResults{}, false)
+	assert.Equal(t, "success\n", status())
+
+	// Ensure an interrupted run is partial even without any errors
+	runPostReportHook(context.Background(), config, map[string]string{}, reporting.AggregatedResults{}, true)
+	assert.Equal(t, "partial\n", status())
+}
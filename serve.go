@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/christopher-s-jones/ghcontributions/reporting"
+)
+
+// How long the server waits for the responses in progress when stopping
+const shutdownTimeout = 10 * time.Second
+
+// serve runs the serve subcommand, serving the latest results on the address
+// while collecting them again at each interval like the daemon. Each collection
+// exports its results to the server.
+func serve(ctx context.Context, address string, interval time.Duration,
+	collect func(ctx context.Context, server *reporting.Server) error) error {
+
+	// Listen before collecting, so a port that's taken fails at once
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+	refresh := make(chan struct{}, 1)
+	server := &reporting.Server{Refresh: refresh}
+	httpServer := &http.Server{Handler: server, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		err := httpServer.Serve(listener)
+		if !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("Couldn't serve the results", "error", err)
+		}
+	}()
+	slog.Info("Serving the results", "address", listener.Addr().String())
+
+	err = daemon(ctx, interval, refresh, func(ctx context.Context) error {
+		return collect(ctx, server)
+	})
+
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shutdownTimeout)
+	defer cancel()
+	return errors.Join(err, httpServer.Shutdown(shutdownCtx))
+}
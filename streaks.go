@@ -0,0 +1,34 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/christopher-s-jones/ghcontributions/reporting"
+)
+
+// findStreaks finds the streaks of the daily counts over each collector's range,
+// from every collector's provider that has a contribution calendar
+func findStreaks(ctx context.Context, collectors []*reporting.ProviderCollector, now time.Time) *reporting.Streaks {
+
+	var days []reporting.ContributionDay
+	for _, collector := range collectors {
+		source, ok := collector.Provider.(reporting.CalendarSource)
+		if !ok {
+			continue
+		}
+		// The calendar is read a year at a time, the longest range it covers
+		for _, year := range reporting.SplitPeriods(collector.Range, reporting.GranularityYear, time.January) {
+			userDays, err := source.ContributionDays(ctx, collector.User, year)
+			if err != nil {
+				slog.Warn("Couldn't read the contribution calendar", "user", collector.User,
+					"year", year.From.Year(), "error", err)
+				break
+			}
+			days = append(days, userDays...)
+		}
+	}
+	streaks := reporting.FindStreaks(days, now)
+	return &streaks
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/christopher-s-jones/ghcontributions/reporting"
+)
+
+// validate runs the validate subcommand, checking that each credential's token
+// works, belongs to its username, and has the required scopes
+func validate(ctx context.Context, args []string) {
+
+	flags := flag.NewFlagSet("validate", flag.ExitOnError)
+	credentialsFilePath := flags.String("credentials", "gh-tokens.json", "The name of the file containing Github usernames \nand API token values")
+	encrypted := flags.Bool("encrypted", false, "Whether the credentials file is PGP encrypted.")
+	passphraseFile := flags.String("passphrase-file", "", "The path of a file holding the passphrase of the encrypted \ncredentials file, instead of prompting for it.")
+	useGHAuth := flags.Bool("use-gh-auth", false, "Whether to read the tokens of the GitHub CLI's accounts, \nlike -credentials gh:, instead of the credentials file.")
+	apiURL := flags.String("api-url", "", "The base URL of a GitHub Enterprise Server, like https://ghe.example.com, \nfor the GitHub credentials without a url of their own.")
+	scopes := flags.String("scopes", strings.Join(reporting.DefaultGitHubScopes, ","), "Comma separated scopes each classic GitHub token needs, \nor none if blank.")
+	userAgent := flags.String("user-agent", "", "The User-Agent header sent with provider API requests.")
+	headers := flags.String("headers", "", "Comma separated Name=Value headers added to \nprovider API requests.")
+	asJSON := flags.Bool("json", false, "Whether to print the checks as JSON.")
+	flags.Usage = func() {
+		fmt.Println("Check that each credential's token works, belongs to its username, and has the scopes")
+		fmt.Println("\nUsage:")
+		fmt.Printf(" %s validate [options]\n\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if *useGHAuth {
+		*credentialsFilePath = ghAuthCredentials
+	}
+
+	credentials, err := loadCredentials(*credentialsFilePath, *encrypted, *passphraseFile, nil, *apiURL)
+	if err != nil {
+		flags.Usage()
+		log.Fatalf("Couldn't load the credentials: %s", err)
+	}
+	httpClient, _, err := newHTTPClient(*userAgent, *headers)
+	if err != nil {
+		flags.Usage()
+		log.Fatalf("Couldn't configure the API requests: %s", err)
+	}
+	var requiredScopes []string
+	if *scopes != "" {
+		requiredScopes = strings.Split(*scopes, ",")
+	}
+
+	var validations []reporting.TokenValidation
+	for _, credential := range *credentials {
+		validation := reporting.TokenValidation{Username: credential.Username}
+		provider, err := newProvider(credential, httpClient)
+		gitHub, isGitHub := provider.(*reporting.GitHub)
+		switch {
+		case err != nil:
+			validation.Problems = append(validation.Problems, err.Error())
+		case !isGitHub:
+			// Only GitHub tokens can be checked, so other providers are left out
+			continue
+		case credential.AppID != 0:
+			// An installation token belongs to the App rather than a user, so only
+			// check that one is issued
+			app, err := newGitHubApp(credential, httpClient)
+			if err == nil {
+				_, err = app.Token()
+			}
+			if err != nil {
+				validation.Problems = append(validation.Problems, err.Error())
+			}
+		default:
+			validation, err = gitHub.ValidateToken(ctx, credential.Username, requiredScopes)
+			if err != nil {
+				validation.Problems = append(validation.Problems, err.Error())
+			}
+		}
+		validations = append(validations, validation)
+	}
+	printValidations(validations, *asJSON)
+
+	for _, validation := range validations {
+		if !validation.Valid() {
+			os.Exit(1)
+		}
+	}
+}
+
+// printValidations prints a line for each credential checked, or the checks as JSON
+func printValidations(validations []reporting.TokenValidation, asJSON bool) {
+	if asJSON {
+		validationsJSON, err := json.MarshalIndent(validations, "", "  ")
+		if err != nil {
+			log.Fatalf("Couldn't report the checks: %s", err)
+		}
+		fmt.Println(string(validationsJSON))
+		return
+	}
+	for _, validation := range validations {
+		if validation.Valid() {
+			fmt.Printf("%-8s %-24s %s\n", "ok", validation.Username, strings.Join(validation.Scopes, ","))
+			continue
+		}
+		for _, problem := range validation.Problems {
+			fmt.Printf("%-8s %-24s %s\n", "failed", validation.Username, problem)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// The version of the command, set when building a release with
+// -ldflags "-X main.version=v1.2.3"
+var version = ""
+
+// versionCommand runs the version subcommand, printing the version of the command
+func versionCommand() {
+	fmt.Printf("ghcontributions %s\n", buildVersion())
+}
+
+// buildVersion returns the version set when building, or else the module version
+// go install and go build record, or the commit built from when there's none
+func buildVersion() string {
+
+	if version != "" {
+		return version
+	}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	if info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	for _, setting := range info.Settings {
+		if setting.Key == "vcs.revision" {
+			return "(devel) " + setting.Value[:min(len(setting.Value), 12)]
+		}
+	}
+	return "(devel)"
+}